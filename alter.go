@@ -0,0 +1,102 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"strings"
+)
+
+//alterKeyword statement changing table i.e. ALTER TABLE music ADD REPLICA 'eu-west-1'
+const alterKeyword = "ALTER"
+
+//alterTableExecution applies ALTER TABLE name action i.e. ADD|DROP REPLICA 'region', SET TAGS 'key=value,...', UNSET TAGS 'key,...' or SET PITR ON|OFF
+func (m *manager) alterTableExecution(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string) (sql.Result, error) {
+	fragments := splitFields(strings.TrimSuffix(strings.TrimSpace(SQL), ";"))
+	if len(fragments) < 4 || strings.ToUpper(fragments[0]) != alterKeyword || strings.ToUpper(fragments[1]) != "TABLE" {
+		return nil, fmt.Errorf("invalid alter statement: %v, expected ALTER TABLE name action", SQL)
+	}
+	table := m.tableName(unquote(fragments[2]))
+	action := fragments[3:]
+	var err error
+	switch {
+	case len(action) == 3 && strings.ToUpper(action[1]) == "REPLICA":
+		err = alterReplica(ctx, db, table, action[0], unquote(action[2]))
+	case len(action) == 3 && strings.ToUpper(action[1]) == tagsKeyword:
+		err = m.alterTags(ctx, db, table, action[0], unquote(action[2]))
+	case len(action) == 3 && strings.ToUpper(action[0]) == "SET" && strings.ToUpper(action[1]) == pitrKeyword:
+		err = alterPointInTimeRecovery(ctx, db, table, action[2])
+	default:
+		return nil, fmt.Errorf("unsupported alter table action: %v, expected ADD|DROP REPLICA 'region', SET|UNSET TAGS 'tags' or SET PITR ON|OFF", strings.Join(action, " "))
+	}
+	m.tables.invalidate(table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to alter %v, %v", table, err)
+	}
+	return dsc.NewSQLResult(0, 0), nil
+}
+
+//alterReplica creates (ADD) or deletes (DROP) global table replica with UpdateTable and waits till the table and its replicas are active
+func alterReplica(ctx context.Context, db dynamodbiface.DynamoDBAPI, table, operation, region string) error {
+	update := &dynamodb.ReplicationGroupUpdate{}
+	switch strings.ToUpper(operation) {
+	case "ADD":
+		update.Create = &dynamodb.CreateReplicationGroupMemberAction{RegionName: aws.String(region)}
+	case "DROP":
+		update.Delete = &dynamodb.DeleteReplicationGroupMemberAction{RegionName: aws.String(region)}
+	default:
+		return fmt.Errorf("unsupported replica operation: %v, expected ADD or DROP", operation)
+	}
+	if _, err := db.UpdateTableWithContext(ctx, &dynamodb.UpdateTableInput{TableName: aws.String(table), ReplicaUpdates: []*dynamodb.ReplicationGroupUpdate{update}}); err != nil {
+		return err
+	}
+	return waitForUpdateCompletion(ctx, db, table)
+}
+
+//tableUpdateState represents table description state matched by update waiter
+type tableUpdateState struct {
+	Updating bool
+}
+
+//waitForUpdateCompletion waits till table and its replicas are active, it returns an error if waiter attempts are exceeded
+func waitForUpdateCompletion(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string) error {
+	input := &dynamodb.DescribeTableInput{TableName: aws.String(table)}
+	waiter := request.Waiter{
+		Name: "WaitUntilTableUpdated",
+		Acceptors: []request.WaiterAcceptor{
+			{State: request.SuccessWaiterState, Matcher: request.PathWaiterMatch, Argument: "Updating", Expected: false},
+		},
+		NewRequest: func(options []request.Option) (*request.Request, error) {
+			req, output := db.DescribeTableRequest(input)
+			req.SetContext(ctx)
+			req.ApplyOptions(options...)
+			req.Handlers.Complete.PushBack(func(req *request.Request) {
+				if req.Error == nil && output.Table != nil {
+					req.Data = &tableUpdateState{Updating: isTableUpdating(output.Table)}
+				}
+			})
+			return req, nil
+		},
+	}
+	waiter.ApplyOptions(waiterOptions...)
+	return waiter.WaitWithContext(ctx)
+}
+
+//isTableUpdating returns true if table or any of its replicas is in transition
+func isTableUpdating(description *dynamodb.TableDescription) bool {
+	if aws.StringValue(description.TableStatus) != dynamodb.TableStatusActive {
+		return true
+	}
+	for _, replica := range description.Replicas {
+		switch aws.StringValue(replica.ReplicaStatus) {
+		case dynamodb.ReplicaStatusCreating, dynamodb.ReplicaStatusUpdating, dynamodb.ReplicaStatusDeleting:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManager_AlterTable(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint":    "memory://alter",
+		"region":      "us-east-1",
+		"tablePrefix": "dev_",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS users", "CREATE TABLE users(Id INT HASH KEY)"} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	var useCases = []struct {
+		description string
+		SQL         string
+		replicas    []string
+		hasError    bool
+	}{
+		{
+			description: "add replica",
+			SQL:         "ALTER TABLE users ADD REPLICA 'eu-west-1'",
+			replicas:    []string{"eu-west-1"},
+		},
+		{
+			description: "add another replica",
+			SQL:         "alter table users add replica 'ap-south-1';",
+			replicas:    []string{"eu-west-1", "ap-south-1"},
+		},
+		{
+			description: "drop replica",
+			SQL:         "ALTER TABLE users DROP REPLICA 'eu-west-1'",
+			replicas:    []string{"ap-south-1"},
+		},
+		{
+			description: "missing replica",
+			SQL:         "ALTER TABLE users DROP REPLICA 'eu-west-1'",
+			hasError:    true,
+		},
+		{
+			description: "unsupported action",
+			SQL:         "ALTER TABLE users ADD COLUMN Name",
+			hasError:    true,
+		},
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db, _ := asDatabase(connection)
+	for _, useCase := range useCases {
+		_, err := manager.Execute(useCase.SQL)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		output, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("dev_users")})
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		var replicas = make([]string, 0)
+		for _, replica := range output.Table.Replicas {
+			replicas = append(replicas, aws.StringValue(replica.RegionName))
+		}
+		assert.EqualValues(t, useCase.replicas, replicas, useCase.description)
+	}
+}
+
+func TestWaitForUpdateCompletion(t *testing.T) {
+	defer func(options []request.WaiterOption) { waiterOptions = options }(waiterOptions)
+	waiterOptions = []request.WaiterOption{request.WithWaiterDelay(request.ConstantWaiterDelay(time.Millisecond)), request.WithWaiterMaxAttempts(3)}
+	var useCases = []struct {
+		description string
+		body        string
+		hasError    bool
+	}{
+		{
+			description: "active table and replicas",
+			body:        `{"Table":{"TableName":"users","TableStatus":"ACTIVE","Replicas":[{"RegionName":"eu-west-1","ReplicaStatus":"ACTIVE"}]}}`,
+		},
+		{
+			description: "replica creating till attempts are exceeded",
+			body:        `{"Table":{"TableName":"users","TableStatus":"ACTIVE","Replicas":[{"RegionName":"eu-west-1","ReplicaStatus":"CREATING"}]}}`,
+			hasError:    true,
+		},
+		{
+			description: "table updating till attempts are exceeded",
+			body:        `{"Table":{"TableName":"users","TableStatus":"UPDATING"}}`,
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			writer.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			_, _ = writer.Write([]byte(useCase.body))
+		}))
+		db := dynamodb.New(session.Must(session.NewSession()), aws.NewConfig().
+			WithEndpoint(server.URL).
+			WithRegion("us-east-1").
+			WithCredentials(credentials.NewStaticCredentials("key", "secret", "")))
+		err := waitForUpdateCompletion(context.Background(), db, "users")
+		server.Close()
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			assert.EqualValues(t, 3, atomic.LoadInt32(&calls), useCase.description)
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&calls), useCase.description)
+	}
+}
@@ -0,0 +1,184 @@
+package dyndb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"strings"
+)
+
+//encodeAttribute returns DynamoDB JSON representation of attribute value i.e. {"N":"1984"}
+func encodeAttribute(value *dynamodb.AttributeValue) interface{} {
+	switch {
+	case value.S != nil:
+		return map[string]interface{}{"S": *value.S}
+	case value.N != nil:
+		return map[string]interface{}{"N": *value.N}
+	case value.BOOL != nil:
+		return map[string]interface{}{"BOOL": *value.BOOL}
+	case value.NULL != nil:
+		return map[string]interface{}{"NULL": *value.NULL}
+	case value.B != nil:
+		return map[string]interface{}{"B": value.B}
+	case value.SS != nil:
+		return map[string]interface{}{"SS": aws.StringValueSlice(value.SS)}
+	case value.NS != nil:
+		return map[string]interface{}{"NS": aws.StringValueSlice(value.NS)}
+	case value.BS != nil:
+		return map[string]interface{}{"BS": value.BS}
+	case value.L != nil:
+		var list = make([]interface{}, 0, len(value.L))
+		for _, item := range value.L {
+			list = append(list, encodeAttribute(item))
+		}
+		return map[string]interface{}{"L": list}
+	case value.M != nil:
+		return map[string]interface{}{"M": encodeItem(value.M)}
+	}
+	return map[string]interface{}{"NULL": true}
+}
+
+//encodeItem returns DynamoDB JSON representation of an item
+func encodeItem(item map[string]*dynamodb.AttributeValue) map[string]interface{} {
+	var result = make(map[string]interface{})
+	for key, value := range item {
+		result[key] = encodeAttribute(value)
+	}
+	return result
+}
+
+//decodeAttribute decodes DynamoDB JSON attribute value
+func decodeAttribute(data []byte) (*dynamodb.AttributeValue, error) {
+	var result = &dynamodb.AttributeValue{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	if result.S == nil && result.N == nil && result.BOOL == nil && result.NULL == nil && result.B == nil &&
+		result.SS == nil && result.NS == nil && result.BS == nil && result.L == nil && result.M == nil {
+		return nil, fmt.Errorf("invalid DynamoDB JSON attribute: %s", data)
+	}
+	return result, nil
+}
+
+//plainAttribute returns plain JSON representation of attribute value, numbers use json.Number to keep precision, sets become lists
+func plainAttribute(value *dynamodb.AttributeValue) interface{} {
+	switch {
+	case value.S != nil:
+		return *value.S
+	case value.N != nil:
+		return json.Number(*value.N)
+	case value.BOOL != nil:
+		return *value.BOOL
+	case value.B != nil:
+		return value.B
+	case value.SS != nil:
+		return aws.StringValueSlice(value.SS)
+	case value.NS != nil:
+		var numbers = make([]json.Number, 0, len(value.NS))
+		for _, item := range value.NS {
+			numbers = append(numbers, json.Number(*item))
+		}
+		return numbers
+	case value.BS != nil:
+		return value.BS
+	case value.L != nil:
+		var list = make([]interface{}, 0, len(value.L))
+		for _, item := range value.L {
+			list = append(list, plainAttribute(item))
+		}
+		return list
+	case value.M != nil:
+		return plainItem(value.M)
+	}
+	return nil
+}
+
+//plainItem returns plain JSON representation of an item
+func plainItem(item map[string]*dynamodb.AttributeValue) map[string]interface{} {
+	var result = make(map[string]interface{})
+	for key, value := range item {
+		result[key] = plainAttribute(value)
+	}
+	return result
+}
+
+//asAttribute converts value decoded from plain JSON with json.Number enabled into attribute value
+func asAttribute(value interface{}) (*dynamodb.AttributeValue, error) {
+	switch actual := value.(type) {
+	case nil:
+		return &dynamodb.AttributeValue{NULL: aws.Bool(true)}, nil
+	case string:
+		return &dynamodb.AttributeValue{S: aws.String(actual)}, nil
+	case json.Number:
+		return &dynamodb.AttributeValue{N: aws.String(actual.String())}, nil
+	case bool:
+		return &dynamodb.AttributeValue{BOOL: aws.Bool(actual)}, nil
+	case []interface{}:
+		var list = make([]*dynamodb.AttributeValue, 0, len(actual))
+		for _, item := range actual {
+			attribute, err := asAttribute(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, attribute)
+		}
+		return &dynamodb.AttributeValue{L: list}, nil
+	case map[string]interface{}:
+		item, err := asItem(actual)
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.AttributeValue{M: item}, nil
+	}
+	return nil, fmt.Errorf("unsupported value type: %T", value)
+}
+
+//asItem converts record decoded from plain JSON into an item
+func asItem(record map[string]interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	var result = make(map[string]*dynamodb.AttributeValue)
+	for key, value := range record {
+		attribute, err := asAttribute(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", key, err)
+		}
+		result[key] = attribute
+	}
+	return result, nil
+}
+
+//decodePlainValue decodes CSV cell, valid JSON numbers, booleans, null, lists and maps keep their type,
+//JSON string is decoded as a string, i.e. "123" written for a string that looks like a number, other text is a string
+func decodePlainValue(text string) (*dynamodb.AttributeValue, error) {
+	value, ok := decodeJSONValue(text)
+	if !ok {
+		return &dynamodb.AttributeValue{S: aws.String(text)}, nil
+	}
+	return asAttribute(value)
+}
+
+//encodePlainString returns CSV cell for a string, empty text or text decoded by decodePlainValue as other value is written as JSON string
+func encodePlainString(text string) (string, error) {
+	if _, ok := decodeJSONValue(text); !ok && text != "" {
+		return text, nil
+	}
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(text); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+//decodeJSONValue decodes text holding a single JSON value
+func decodeJSONValue(text string) (interface{}, bool) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return nil, false
+	}
+	return value, true
+}
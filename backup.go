@@ -0,0 +1,209 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"strings"
+	"time"
+)
+
+const (
+	//backupKeyword statement creating on-demand backup i.e. BACKUP TABLE music AS 'daily'
+	backupKeyword = "BACKUP"
+	//restoreKeyword statement restoring table from backup i.e. RESTORE TABLE music_restored FROM BACKUP 'daily',
+	//or to point in time i.e. RESTORE TABLE music_restored FROM TABLE music AT '2024-01-02T15:04:05Z', latest restorable time is used without AT
+	restoreKeyword = "RESTORE"
+	//pitrKeyword point in time recovery alter table option i.e. ALTER TABLE music SET PITR ON
+	pitrKeyword = "PITR"
+	//backupARNPrefix distinguishes backup ARN from backup name
+	backupARNPrefix = "arn:"
+)
+
+//backupTableExecution creates backup with CreateBackup and waits till it is available, backup details are returned with sql.Out parameter
+func (m *manager) backupTableExecution(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string, returned *returnedItems) (sql.Result, error) {
+	fragments := statementFragments(SQL)
+	if len(fragments) != 4 || strings.ToUpper(fragments[0]) != backupKeyword || strings.ToUpper(fragments[2]) != "AS" {
+		return nil, fmt.Errorf("invalid backup statement: %v, expected BACKUP TABLE name AS 'backup'", SQL)
+	}
+	table := m.tableName(unquote(fragments[1]))
+	output, err := db.CreateBackupWithContext(ctx, &dynamodb.CreateBackupInput{TableName: aws.String(table), BackupName: aws.String(unquote(fragments[3]))})
+	if err != nil {
+		return nil, fmt.Errorf("failed to backup %v, %v", table, err)
+	}
+	details, err := waitForBackupCompletion(ctx, db, output.BackupDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to backup %v, %v", table, err)
+	}
+	if returned != nil {
+		*returned.destination = append(*returned.destination, map[string]interface{}{
+			"BackupArn":              aws.StringValue(details.BackupArn),
+			"BackupName":             aws.StringValue(details.BackupName),
+			"BackupStatus":           aws.StringValue(details.BackupStatus),
+			"BackupCreationDateTime": aws.TimeValue(details.BackupCreationDateTime),
+			"BackupSizeBytes":        aws.Int64Value(details.BackupSizeBytes),
+		})
+	}
+	if aws.StringValue(details.BackupStatus) != dynamodb.BackupStatusAvailable {
+		return nil, fmt.Errorf("backup %v of %v is %v", aws.StringValue(details.BackupArn), table, aws.StringValue(details.BackupStatus))
+	}
+	return dsc.NewSQLResult(0, 0), nil
+}
+
+//waitForBackupCompletion waits till backup is no longer CREATING, it returns the latest backup details
+func waitForBackupCompletion(ctx context.Context, db dynamodbiface.DynamoDBAPI, details *dynamodb.BackupDetails) (*dynamodb.BackupDetails, error) {
+	if aws.StringValue(details.BackupStatus) != dynamodb.BackupStatusCreating {
+		return details, nil
+	}
+	input := &dynamodb.DescribeBackupInput{BackupArn: details.BackupArn}
+	waiter := request.Waiter{
+		Name: "WaitUntilBackupCreated",
+		Acceptors: []request.WaiterAcceptor{{
+			State: request.RetryWaiterState, Matcher: request.PathWaiterMatch,
+			Argument: "BackupDescription.BackupDetails.BackupStatus", Expected: dynamodb.BackupStatusCreating,
+		}, {
+			State: request.SuccessWaiterState, Matcher: request.PathWaiterMatch,
+			Argument: "BackupDescription.BackupDetails.BackupStatus", Expected: dynamodb.BackupStatusAvailable,
+		}, {
+			State: request.SuccessWaiterState, Matcher: request.PathWaiterMatch,
+			Argument: "BackupDescription.BackupDetails.BackupStatus", Expected: dynamodb.BackupStatusDeleted,
+		}},
+		NewRequest: func(options []request.Option) (*request.Request, error) {
+			req, _ := db.DescribeBackupRequest(input)
+			req.SetContext(ctx)
+			req.ApplyOptions(options...)
+			return req, nil
+		},
+	}
+	waiter.ApplyOptions(waiterOptions...)
+	if err := waiter.WaitWithContext(ctx); err != nil {
+		return nil, err
+	}
+	output, err := db.DescribeBackupWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return output.BackupDescription.BackupDetails, nil
+}
+
+//restoreTableExecution restores table from backup with RestoreTableFromBackup or to point in time with RestoreTableToPointInTime and waits till it is created
+func (m *manager) restoreTableExecution(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string) (sql.Result, error) {
+	fragments := statementFragments(SQL)
+	if len(fragments) < 5 || strings.ToUpper(fragments[0]) != restoreKeyword || strings.ToUpper(fragments[2]) != "FROM" {
+		return nil, fmt.Errorf("invalid restore statement: %v, expected RESTORE TABLE name FROM BACKUP 'backup' or RESTORE TABLE name FROM TABLE source [AT 'time']", SQL)
+	}
+	target := m.tableName(unquote(fragments[1]))
+	var err error
+	switch source := fragments[3:]; {
+	case len(source) == 2 && strings.ToUpper(source[0]) == backupKeyword:
+		var backupARN string
+		if backupARN, err = findBackup(ctx, db, unquote(source[1])); err == nil {
+			_, err = db.RestoreTableFromBackupWithContext(ctx, &dynamodb.RestoreTableFromBackupInput{TargetTableName: aws.String(target), BackupArn: aws.String(backupARN)})
+		}
+	case (len(source) == 2 || len(source) == 4) && strings.ToUpper(source[0]) == "TABLE":
+		input := &dynamodb.RestoreTableToPointInTimeInput{SourceTableName: aws.String(m.tableName(unquote(source[1]))), TargetTableName: aws.String(target)}
+		if len(source) == 2 {
+			input.UseLatestRestorableTime = aws.Bool(true)
+		} else if strings.ToUpper(source[2]) != "AT" {
+			return nil, fmt.Errorf("invalid restore statement: %v, expected AT 'time'", SQL)
+		} else {
+			var restoreTime time.Time
+			if restoreTime, err = time.Parse(time.RFC3339, unquote(source[3])); err != nil {
+				return nil, fmt.Errorf("invalid restore time: %v, %v", source[3], err)
+			}
+			input.RestoreDateTime = &restoreTime
+		}
+		_, err = db.RestoreTableToPointInTimeWithContext(ctx, input)
+	default:
+		return nil, fmt.Errorf("invalid restore source: %v, expected BACKUP 'backup' or TABLE source [AT 'time']", strings.Join(source, " "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore %v, %v", target, err)
+	}
+	err = waitForCreateCompletion(ctx, db, target)
+	m.tables.invalidate(target)
+	if err != nil {
+		return nil, err
+	}
+	return dsc.NewSQLResult(0, 0), nil
+}
+
+//findBackup returns backup ARN, backup name is resolved to ARN of the most recent backup with that name
+func findBackup(ctx context.Context, db dynamodbiface.DynamoDBAPI, backup string) (string, error) {
+	if strings.HasPrefix(backup, backupARNPrefix) {
+		return backup, nil
+	}
+	var result *dynamodb.BackupSummary
+	input := &dynamodb.ListBackupsInput{}
+	for {
+		output, err := db.ListBackupsWithContext(ctx, input)
+		if err != nil {
+			return "", fmt.Errorf("failed to list backups, %v", err)
+		}
+		for _, summary := range output.BackupSummaries {
+			if aws.StringValue(summary.BackupName) != backup {
+				continue
+			}
+			if result == nil || aws.TimeValue(summary.BackupCreationDateTime).After(aws.TimeValue(result.BackupCreationDateTime)) {
+				result = summary
+			}
+		}
+		if output.LastEvaluatedBackupArn == nil {
+			break
+		}
+		input.ExclusiveStartBackupArn = output.LastEvaluatedBackupArn
+	}
+	if result == nil {
+		return "", fmt.Errorf("backup not found: %v", backup)
+	}
+	return aws.StringValue(result.BackupArn), nil
+}
+
+//alterPointInTimeRecovery enables (ON) or disables (OFF) point in time recovery with UpdateContinuousBackups and waits till the status is changed
+func alterPointInTimeRecovery(ctx context.Context, db dynamodbiface.DynamoDBAPI, table, value string) error {
+	var enabled bool
+	switch strings.ToUpper(value) {
+	case "ON":
+		enabled = true
+	case "OFF":
+	default:
+		return fmt.Errorf("unsupported %v value: %v, expected ON or OFF", pitrKeyword, value)
+	}
+	_, err := db.UpdateContinuousBackupsWithContext(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(table),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(enabled)},
+	})
+	if err != nil {
+		return err
+	}
+	expected := dynamodb.PointInTimeRecoveryStatusDisabled
+	if enabled {
+		expected = dynamodb.PointInTimeRecoveryStatusEnabled
+	}
+	return waitForRecoveryStatus(ctx, db, table, expected)
+}
+
+//waitForRecoveryStatus waits till point in time recovery has expected status
+func waitForRecoveryStatus(ctx context.Context, db dynamodbiface.DynamoDBAPI, table, status string) error {
+	input := &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String(table)}
+	waiter := request.Waiter{
+		Name: "WaitUntilPointInTimeRecovery" + status,
+		Acceptors: []request.WaiterAcceptor{{
+			State: request.SuccessWaiterState, Matcher: request.PathWaiterMatch,
+			Argument: "ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus", Expected: status,
+		}},
+		NewRequest: func(options []request.Option) (*request.Request, error) {
+			req, _ := db.DescribeContinuousBackupsRequest(input)
+			req.SetContext(ctx)
+			req.ApplyOptions(options...)
+			return req, nil
+		},
+	}
+	waiter.ApplyOptions(waiterOptions...)
+	return waiter.WaitWithContext(ctx)
+}
@@ -0,0 +1,135 @@
+package dyndb
+
+import (
+	"database/sql"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"testing"
+	"time"
+)
+
+func TestManager_BackupRestore(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint":    "memory://backup",
+		"region":      "us-west-1",
+		"tablePrefix": "dev_",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS users",
+		"DROP TABLE IF EXISTS users_daily",
+		"DROP TABLE IF EXISTS users_latest",
+		"CREATE TABLE users(Id INT HASH KEY)",
+	} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	_, err = manager.Execute("INSERT INTO users(Id, Name) VALUES(?, ?)", 1, "user1")
+	assert.Nil(t, err)
+	var backups []map[string]interface{}
+	_, err = manager.Execute("BACKUP TABLE users AS 'daily'", sql.Out{Dest: &backups})
+	if !assert.Nil(t, err) || !assert.Len(t, backups, 1) {
+		return
+	}
+	assert.EqualValues(t, "daily", backups[0]["BackupName"])
+	assert.EqualValues(t, dynamodb.BackupStatusAvailable, backups[0]["BackupStatus"])
+	_, err = manager.Execute("INSERT INTO users(Id, Name) VALUES(?, ?)", 2, "user2")
+	assert.Nil(t, err)
+
+	var useCases = []struct {
+		description string
+		SQL         string
+		table       string
+		expect      int
+		hasError    bool
+	}{
+		{
+			description: "restore from backup name",
+			SQL:         "RESTORE TABLE users_daily FROM BACKUP 'daily'",
+			table:       "users_daily",
+			expect:      1,
+		},
+		{
+			description: "restore to existing table",
+			SQL:         "RESTORE TABLE users_daily FROM BACKUP '" + backups[0]["BackupArn"].(string) + "'",
+			hasError:    true,
+		},
+		{
+			description: "missing backup",
+			SQL:         "RESTORE TABLE users_weekly FROM BACKUP 'weekly'",
+			hasError:    true,
+		},
+		{
+			description: "point in time recovery disabled",
+			SQL:         "RESTORE TABLE users_latest FROM TABLE users",
+			hasError:    true,
+		},
+		{
+			description: "enable point in time recovery",
+			SQL:         "ALTER TABLE users SET PITR ON",
+		},
+		{
+			description: "restore time outside window",
+			SQL:         "RESTORE TABLE users_latest FROM TABLE users AT '" + time.Now().Add(-time.Hour).Format(time.RFC3339) + "'",
+			hasError:    true,
+		},
+		{
+			description: "restore to latest restorable time",
+			SQL:         "RESTORE TABLE users_latest FROM TABLE users",
+			table:       "users_latest",
+			expect:      2,
+		},
+		{
+			description: "invalid point in time recovery value",
+			SQL:         "ALTER TABLE users SET PITR MAYBE",
+			hasError:    true,
+		},
+		{
+			description: "invalid restore source",
+			SQL:         "RESTORE TABLE users_latest FROM users",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		_, err := manager.Execute(useCase.SQL)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) || useCase.table == "" {
+			continue
+		}
+		var records = make([]map[string]interface{}, 0)
+		err = manager.ReadAll(&records, "SELECT Id, Name FROM "+useCase.table, nil, nil)
+		if assert.Nil(t, err, useCase.description) {
+			assert.Len(t, records, useCase.expect, useCase.description)
+		}
+	}
+
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db, _ := asDatabase(connection)
+	output, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("dev_users_daily")})
+	if assert.Nil(t, err) && assert.NotNil(t, output.Table.RestoreSummary) {
+		assert.EqualValues(t, backups[0]["BackupArn"], aws.StringValue(output.Table.RestoreSummary.SourceBackupArn))
+	}
+	_, err = manager.Execute("ALTER TABLE users SET PITR OFF")
+	assert.Nil(t, err)
+	continuous, err := db.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{TableName: aws.String("dev_users")})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, dynamodb.PointInTimeRecoveryStatusDisabled, aws.StringValue(continuous.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus))
+	}
+}
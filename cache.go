@@ -0,0 +1,95 @@
+package dyndb
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"sync"
+	"time"
+)
+
+const (
+	//tableCacheTTLKey config parameter, time to live of cached table descriptions in ms, 0 disables caching
+	tableCacheTTLKey = "tableCacheTTLMs"
+	//defaultTableCacheTTL default time to live of cached table descriptions
+	defaultTableCacheTTL = time.Minute
+)
+
+//cachedTable represents cached table description
+type cachedTable struct {
+	description *dynamodb.TableDescription
+	cached      time.Time
+}
+
+//tableCache caches table descriptions (key schema, indexes and attribute definitions) shared by manager connections,
+//entries expire after time to live or when DDL is executed through the driver
+type tableCache struct {
+	mutex  sync.RWMutex
+	tables map[string]*cachedTable
+}
+
+//get returns table description cached within ttl or nil
+func (c *tableCache) get(table string, ttl time.Duration) *dynamodb.TableDescription {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	cached, ok := c.tables[table]
+	if !ok || time.Since(cached.cached) >= ttl {
+		return nil
+	}
+	return cached.description
+}
+
+func (c *tableCache) put(table string, description *dynamodb.TableDescription) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tables[table] = &cachedTable{description: description, cached: time.Now()}
+}
+
+//invalidate removes cached table description
+func (c *tableCache) invalidate(table string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.tables, table)
+}
+
+func newTableCache() *tableCache {
+	return &tableCache{tables: make(map[string]*cachedTable)}
+}
+
+//describe returns table description cached within ttl, otherwise it describes the table with DescribeTable and caches it if ttl is positive
+func (c *tableCache) describe(db dynamodbiface.DynamoDBAPI, table string, ttl time.Duration) (*dynamodb.TableDescription, error) {
+	if description := c.get(table, ttl); description != nil {
+		return description, nil
+	}
+	output, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(table)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table %v, %v", table, err)
+	}
+	if ttl > 0 {
+		c.put(table, output.Table)
+	}
+	return output.Table, nil
+}
+
+//describe returns table description, it is cached for tableCacheTTLMs config parameter or a minute by default
+func (m *manager) describe(db dynamodbiface.DynamoDBAPI, table string) (*dynamodb.TableDescription, error) {
+	ttl := m.Config().GetDuration(tableCacheTTLKey, time.Millisecond, defaultTableCacheTTL)
+	return m.tables.describe(db, table, ttl)
+}
+
+//describeCached returns table description using datastore manager cache if available
+func describeCached(datastoreManager dsc.Manager, db dynamodbiface.DynamoDBAPI, table string) (*dynamodb.TableDescription, error) {
+	if m, ok := datastoreManager.(*manager); ok {
+		return m.describe(db, table)
+	}
+	return newTableCache().describe(db, table, 0)
+}
+
+//invalidateTable removes cached table description after DDL
+func invalidateTable(datastoreManager dsc.Manager, table string) {
+	if m, ok := datastoreManager.(*manager); ok {
+		m.tables.invalidate(table)
+	}
+}
@@ -0,0 +1,87 @@
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"testing"
+)
+
+//describeCounter wraps DynamoDB client counting DescribeTable calls
+type describeCounter struct {
+	dynamodbiface.DynamoDBAPI
+	count int
+}
+
+func (c *describeCounter) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	c.count++
+	return c.DynamoDBAPI.DescribeTable(input)
+}
+
+func TestManager_Describe(t *testing.T) {
+	var useCases = []struct {
+		description string
+		ttl         interface{}
+		expect      int
+	}{
+		{
+			description: "cached by default",
+			expect:      1,
+		},
+		{
+			description: "caching disabled",
+			ttl:         0,
+			expect:      3,
+		},
+	}
+	for _, useCase := range useCases {
+		parameters := map[string]interface{}{
+			"endpoint": "memory://cache",
+			"region":   "us-west-1",
+		}
+		if useCase.ttl != nil {
+			parameters["tableCacheTTLMs"] = useCase.ttl
+		}
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", parameters)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		client := &describeCounter{}
+		manager, err := NewManagerFactory(func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+			client.DynamoDBAPI = dynamodb.New(sess, awsConfig)
+			return client, nil
+		}).Create(config)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		read := func() {
+			var records = make([]map[string]interface{}, 0)
+			err := manager.ReadAll(&records, "SELECT * FROM cached WHERE Id = ?", []interface{}{1}, nil)
+			assert.Nil(t, err, useCase.description)
+		}
+		for _, SQL := range []string{"DROP TABLE IF EXISTS cached", "CREATE TABLE cached(Id INT HASH KEY)"} {
+			_, err = manager.Execute(SQL)
+			if !assert.Nil(t, err, SQL) {
+				return
+			}
+		}
+		client.count = 0
+		for i := 0; i < 3; i++ {
+			read()
+		}
+		assert.EqualValues(t, useCase.expect, client.count, useCase.description)
+
+		_, err = manager.Execute("DROP TABLE cached")
+		assert.Nil(t, err, useCase.description)
+		_, err = manager.Execute("CREATE TABLE cached(Name VARCHAR(255) HASH KEY)")
+		assert.Nil(t, err, useCase.description)
+		plan, err := Explain(manager, "SELECT * FROM cached WHERE Name = ?", []interface{}{"a"})
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, getItemOperation, plan.Operation, useCase.description)
+		}
+		assert.EqualValues(t, "Name", dsc.GetDatastoreDialect("dyndb").GetKeyName(manager, "", "cached"), useCase.description)
+	}
+}
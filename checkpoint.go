@@ -0,0 +1,94 @@
+package dyndb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+//shardEnd checkpoint marks fully processed closed shard
+const shardEnd = "SHARD_END"
+
+//CheckpointStore represents stream reader progress store, it keeps last processed sequence number per stream shard
+type CheckpointStore interface {
+	//Get returns last processed sequence number for a shard or empty string if shard has not been processed yet
+	Get(streamARN, shardID string) (string, error)
+
+	//Put stores last processed sequence number for a shard
+	Put(streamARN, shardID, sequenceNumber string) error
+}
+
+type memoryCheckpointStore struct {
+	mux         *sync.RWMutex
+	checkpoints map[string]map[string]string
+}
+
+func (s *memoryCheckpointStore) Get(streamARN, shardID string) (string, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.checkpoints[streamARN][shardID], nil
+}
+
+func (s *memoryCheckpointStore) Put(streamARN, shardID, sequenceNumber string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, ok := s.checkpoints[streamARN]; !ok {
+		s.checkpoints[streamARN] = make(map[string]string)
+	}
+	s.checkpoints[streamARN][shardID] = sequenceNumber
+	return nil
+}
+
+//NewMemoryCheckpointStore returns checkpoint store keeping progress in memory
+func NewMemoryCheckpointStore() CheckpointStore {
+	return &memoryCheckpointStore{
+		mux:         &sync.RWMutex{},
+		checkpoints: make(map[string]map[string]string),
+	}
+}
+
+type fileCheckpointStore struct {
+	*memoryCheckpointStore
+	filename string
+}
+
+func (s *fileCheckpointStore) Put(streamARN, shardID, sequenceNumber string) error {
+	if err := s.memoryCheckpointStore.Put(streamARN, shardID, sequenceNumber); err != nil {
+		return err
+	}
+	s.mux.RLock()
+	data, err := json.Marshal(s.checkpoints)
+	s.mux.RUnlock()
+	if err != nil {
+		return err
+	}
+	temp := s.filename + ".tmp"
+	if err = ioutil.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp, s.filename)
+}
+
+//NewFileCheckpointStore returns checkpoint store keeping progress in JSON file
+func NewFileCheckpointStore(filename string) (CheckpointStore, error) {
+	result := &fileCheckpointStore{
+		memoryCheckpointStore: NewMemoryCheckpointStore().(*memoryCheckpointStore),
+		filename:              filename,
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return result, nil
+	}
+	if err = json.Unmarshal(data, &result.checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints %v: %v", filename, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/adrianwit/dyndb"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+type field struct {
+	Name       string
+	Type       string
+	Column     string
+	PrimaryKey bool
+}
+
+type keyType struct {
+	Name       string
+	Comment    string
+	Index      string
+	IndexConst string
+	Fields     []*field
+}
+
+type structType struct {
+	Package   string
+	Name      string
+	Table     string
+	Fields    []*field
+	Key       *keyType
+	KeyMethod string //primary key method name, PrimaryKey if an attribute is named Key
+	Indexes   []*keyType
+	receiver  string
+}
+
+func (s *structType) Receiver() string {
+	return s.receiver
+}
+
+var codeTemplate = template.Must(template.New("struct").Parse(`// Code generated by dyndb-gen; DO NOT EDIT.
+
+package {{.Package}}
+
+import "strings"
+
+//{{.Name}}Table table name
+const {{.Name}}Table = "{{.Table}}"
+{{range .Indexes}}
+//{{.IndexConst}} secondary index name, use with /*+ INDEX({{.Index}}) */ hint
+const {{.IndexConst}} = "{{.Index}}"
+{{end}}
+//{{.Name}} represents {{.Table}} table item
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `column:"{{.Column}}"{{if .PrimaryKey}} primaryKey:"true"{{end}}` + "`" + `
+{{- end}}
+}
+{{with .Key}}
+//{{$.KeyMethod}} returns {{$.Table}} item primary key
+func ({{$.Receiver}} *{{$.Name}}) {{$.KeyMethod}}() {{.Name}} {
+	return {{.Name}}{
+	{{- range .Fields}}
+		{{.Name}}: {{$.Receiver}}.{{.Name}},
+	{{- end}}
+	}
+}
+{{end}}
+{{- range $key := .AllKeys}}
+//{{$key.Name}} {{$key.Comment}}
+type {{$key.Name}} struct {
+{{- range $key.Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+
+//Criteria returns WHERE clause criteria with bind parameters
+func (k {{$key.Name}}) Criteria() (string, []interface{}) {
+	var criteria = make([]string, 0, {{len $key.Fields}})
+	var parameters = make([]interface{}, 0, {{len $key.Fields}})
+{{- range $key.Fields}}
+	criteria = append(criteria, "{{.Column}} = ?")
+	parameters = append(parameters, k.{{.Name}})
+{{- end}}
+	return strings.Join(criteria, " AND "), parameters
+}
+{{end -}}
+`))
+
+//AllKeys returns primary key followed by index keys
+func (s *structType) AllKeys() []*keyType {
+	var result = make([]*keyType, 0)
+	if s.Key != nil {
+		result = append(result, s.Key)
+	}
+	return append(result, s.Indexes...)
+}
+
+//generate returns formatted Go source with table struct and typed key helpers
+func generate(schema *dyndb.TableSchema, pkg, name string) ([]byte, error) {
+	if name == "" {
+		name = exportedName(schema.Table)
+	}
+	result := &structType{
+		Package:  pkg,
+		Name:     name,
+		Table:    schema.Table,
+		Fields:   make([]*field, 0),
+		Indexes:  make([]*keyType, 0),
+		receiver: strings.ToLower(name[:1]),
+	}
+	var fields = make(map[string]*field)
+	var used = make(map[string]bool)
+	for _, attribute := range schema.Attributes {
+		item := &field{
+			Name:       uniqueName(exportedName(attribute.Name), used),
+			Type:       goType(attribute),
+			Column:     attribute.Name,
+			PrimaryKey: attribute.KeyType != "",
+		}
+		fields[attribute.Name] = item
+		result.Fields = append(result.Fields, item)
+	}
+	if len(schema.PkColumns) > 0 {
+		result.KeyMethod = "Key"
+		if used[result.KeyMethod] {
+			result.KeyMethod = uniqueName("PrimaryKey", used)
+		}
+		result.Key = &keyType{Name: name + "Key", Comment: "represents " + schema.Table + " table primary key", Fields: keyFields(schema.PkColumns, fields)}
+	}
+	for _, index := range schema.Indexes {
+		indexName := exportedName(index.Name)
+		kind := "local"
+		if index.Global {
+			kind = "global"
+		}
+		result.Indexes = append(result.Indexes, &keyType{
+			Name:       name + indexName + "Key",
+			Comment:    "represents " + index.Name + " " + kind + " secondary index key",
+			Index:      index.Name,
+			IndexConst: name + indexName,
+			Fields:     keyFields(index.Keys, fields),
+		})
+	}
+	buffer := new(bytes.Buffer)
+	if err := codeTemplate.Execute(buffer, result); err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %v\n%s", err, buffer.Bytes())
+	}
+	return source, nil
+}
+
+//keyFields returns non pointer fields for key attributes
+func keyFields(keys []string, fields map[string]*field) []*field {
+	var result = make([]*field, 0, len(keys))
+	for _, key := range keys {
+		item, ok := fields[key]
+		if !ok {
+			continue
+		}
+		result = append(result, &field{Name: item.Name, Type: strings.TrimPrefix(item.Type, "*"), Column: item.Column})
+	}
+	return result
+}
+
+//goType returns Go type for attribute, nullable non key scalars use pointers
+func goType(attribute *dyndb.AttributeSchema) string {
+	scanType := attribute.ScanType()
+	result := scanType.String()
+	if result == "" {
+		result = "interface{}"
+	}
+	switch result {
+	case "string", "int64", "float64", "bool":
+		if attribute.Nullable && attribute.KeyType == "" {
+			return "*" + result
+		}
+	}
+	return result
+}
+
+//exportedName returns exported Go identifier for attribute or table name i.e. release_year -> ReleaseYear
+func exportedName(name string) string {
+	var result = make([]rune, 0, len(name))
+	upper := true
+	for _, r := range name {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		result = append(result, r)
+	}
+	if len(result) == 0 || unicode.IsDigit(result[0]) {
+		result = append([]rune("X"), result...)
+	}
+	return string(result)
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	result := name
+	for i := 2; used[result]; i++ {
+		result = fmt.Sprintf("%v%d", name, i)
+	}
+	used[result] = true
+	return result
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"github.com/adrianwit/dyndb"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	schema := &dyndb.TableSchema{
+		Table:     "music",
+		PkColumns: []string{"Artist", "SongTitle"},
+		Indexes:   []*dyndb.IndexSchema{{Name: "genre-index", Global: true, Keys: []string{"Genre"}}},
+		Attributes: []*dyndb.AttributeSchema{
+			{Name: "Artist", Type: "S", KeyType: "HASH"},
+			{Name: "SongTitle", Type: "S", KeyType: "RANGE"},
+			{Name: "Genre", Type: "S", Nullable: true},
+			{Name: "release_year", Type: "N", Integer: true},
+			{Name: "Price", Type: "N", Nullable: true},
+			{Name: "Tags", Type: "SS", Nullable: true},
+			{Name: "Label", Type: "M"},
+		},
+	}
+	source, err := generate(schema, "model", "")
+	if !assert.Nil(t, err) {
+		return
+	}
+	code := string(source)
+	for _, expect := range []string{
+		"// Code generated by dyndb-gen; DO NOT EDIT.",
+		"package model",
+		`const MusicTable = "music"`,
+		`const MusicGenreIndex = "genre-index"`,
+		"type Music struct {",
+		"Artist      string                 `column:\"Artist\" primaryKey:\"true\"`",
+		"Genre       *string                `column:\"Genre\"`",
+		"ReleaseYear int64                  `column:\"release_year\"`",
+		"Price       *float64               `column:\"Price\"`",
+		"Tags        []string               `column:\"Tags\"`",
+		"Label       map[string]interface{} `column:\"Label\"`",
+		"func (m *Music) Key() MusicKey {",
+		"type MusicKey struct {",
+		"func (k MusicKey) Criteria() (string, []interface{}) {",
+		`criteria = append(criteria, "SongTitle = ?")`,
+		"type MusicGenreIndexKey struct {",
+		"\tGenre string\n",
+	} {
+		assert.True(t, strings.Contains(code, expect), expect+"\n"+code)
+	}
+}
+
+func TestGenerate_KeyAttribute(t *testing.T) {
+	var useCases = []struct {
+		description string
+		attributes  []string
+		expect      string
+		field       string
+	}{
+		{
+			description: "key attribute",
+			attributes:  []string{"Id", "Key"},
+			expect:      "func (s *Settings) PrimaryKey() SettingsKey {",
+			field:       "Key string `column:\"Key\"`",
+		},
+		{
+			description: "key and primary key attributes",
+			attributes:  []string{"Id", "Key", "primary_key"},
+			expect:      "func (s *Settings) PrimaryKey2() SettingsKey {",
+			field:       "PrimaryKey string `column:\"primary_key\"`",
+		},
+	}
+	for _, useCase := range useCases {
+		schema := &dyndb.TableSchema{Table: "settings", PkColumns: []string{"Id"}}
+		for i, name := range useCase.attributes {
+			attribute := &dyndb.AttributeSchema{Name: name, Type: "S"}
+			if i == 0 {
+				attribute.KeyType = "HASH"
+			}
+			schema.Attributes = append(schema.Attributes, attribute)
+		}
+		source, err := generate(schema, "model", "")
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		code := string(source)
+		assert.True(t, strings.Contains(code, useCase.expect), useCase.description+"\n"+code)
+		assert.True(t, strings.Contains(code, useCase.field), useCase.description+"\n"+code)
+		assert.False(t, strings.Contains(code, ") Key() "), useCase.description+"\n"+code)
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	assert.Equal(t, "ReleaseYear", exportedName("release_year"))
+	assert.Equal(t, "SongTitle", exportedName("SongTitle"))
+	assert.Equal(t, "X2fa", exportedName("2fa"))
+	assert.Equal(t, "UserProfile", exportedName("user-profile"))
+}
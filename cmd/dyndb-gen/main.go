@@ -0,0 +1,68 @@
+//dyndb-gen generates Go struct with primaryKey/column tags and typed key helpers from an existing DynamoDB table
+//
+//	dyndb-gen -endpoint=localhost -region=us-west-1 -key=dummy -secret=dummy -table=music -package=model -o music.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/adrianwit/dyndb"
+	"github.com/viant/dsc"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+var (
+	endpoint    = flag.String("endpoint", "", "DynamoDB endpoint i.e. localhost:8000")
+	region      = flag.String("region", "", "AWS region")
+	key         = flag.String("key", "", "AWS access key")
+	secret      = flag.String("secret", "", "AWS secret key")
+	credentials = flag.String("credentials", "", "credentials file location")
+	table       = flag.String("table", "", "table name")
+	pkg         = flag.String("package", "model", "generated code package")
+	name        = flag.String("type", "", "struct name, defaults to table name")
+	sampleSize  = flag.Int("sample", 100, "number of items sampled to infer non key attributes")
+	output      = flag.String("o", "", "output file, defaults to stdout")
+)
+
+func main() {
+	flag.Parse()
+	if *table == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	var parameters = make(map[string]interface{})
+	for k, v := range map[string]string{"endpoint": *endpoint, "region": *region, "key": *key, "secret": *secret} {
+		if v != "" {
+			parameters[k] = v
+		}
+	}
+	config, err := dsc.NewConfigWithParameters("dyndb", "", *credentials, parameters)
+	if err != nil {
+		return err
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if err != nil {
+		return err
+	}
+	schema, err := dyndb.InferSchema(manager, *table, *sampleSize)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %v: %v", *table, err)
+	}
+	source, err := generate(schema, *pkg, *name)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return ioutil.WriteFile(*output, source, 0644)
+}
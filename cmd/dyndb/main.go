@@ -0,0 +1,115 @@
+//dyndb is SQL shell for DynamoDB, statements are read from -e flag, stdin or interactive prompt and terminated with semicolon
+//
+//	dyndb -endpoint=localhost -region=us-west-1 -key=dummy -secret=dummy -format=table
+//	echo "SELECT * FROM music WHERE Artist = 'Artist1';" | dyndb -endpoint=localhost -format=json
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/viant/dsc"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+var (
+	endpoint    = flag.String("endpoint", "", "DynamoDB endpoint i.e. localhost:8000")
+	region      = flag.String("region", "", "AWS region")
+	key         = flag.String("key", "", "AWS access key")
+	secret      = flag.String("secret", "", "AWS secret key")
+	credentials = flag.String("credentials", "", "credentials file location")
+	format      = flag.String("format", tableFormat, "output format: table, json or csv")
+	execute     = flag.String("e", "", "statements to execute")
+)
+
+func main() {
+	flag.Parse()
+	manager, err := newManager()
+	if err != nil {
+		log.Fatal(err)
+	}
+	shell, err := newShell(manager, *format, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *execute != "" {
+		if err = shell.runAll(*execute); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if !isTerminal(os.Stdin) {
+		input, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = shell.runAll(string(input)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	interactive(shell, os.Stdin)
+}
+
+func newManager() (dsc.Manager, error) {
+	var parameters = make(map[string]interface{})
+	for k, v := range map[string]string{"endpoint": *endpoint, "region": *region, "key": *key, "secret": *secret} {
+		if v != "" {
+			parameters[k] = v
+		}
+	}
+	config, err := dsc.NewConfigWithParameters("dyndb", "", *credentials, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return dsc.NewManagerFactory().Create(config)
+}
+
+//interactive reads statements from prompt till EOF or exit, errors are reported without leaving the shell
+func interactive(shell *shell, input io.Reader) {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	var pending []string
+	prompt := "dyndb> "
+	fmt.Print(prompt)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(pending) == 0 {
+			switch strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), ";"))) {
+			case "exit", "quit", `\q`:
+				return
+			case "":
+				fmt.Print(prompt)
+				continue
+			}
+		}
+		pending = append(pending, line)
+		text := strings.Join(pending, "\n")
+		statements, rest := splitStatements(text)
+		for _, statement := range statements {
+			if err := shell.run(statement); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			}
+		}
+		pending = pending[:0]
+		if strings.TrimSpace(rest) != "" {
+			pending = append(pending, rest)
+			fmt.Print("    -> ")
+			continue
+		}
+		fmt.Print(prompt)
+	}
+	fmt.Println()
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
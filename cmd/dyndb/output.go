@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/toolbox"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+	csvFormat   = "csv"
+)
+
+//output writes records as a text table, new line delimited JSON or CSV
+type output struct {
+	format string
+	writer io.Writer
+}
+
+func (o *output) write(columns []string, records []map[string]interface{}) error {
+	switch o.format {
+	case jsonFormat:
+		encoder := json.NewEncoder(o.writer)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case csvFormat:
+		writer := csv.NewWriter(o.writer)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		for _, record := range records {
+			var row = make([]string, len(columns))
+			for i, column := range columns {
+				if value, ok := record[column]; ok && value != nil {
+					row[i] = formatValue(value)
+				}
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return o.writeTable(columns, records)
+}
+
+func (o *output) writeTable(columns []string, records []map[string]interface{}) error {
+	var widths = make([]int, len(columns))
+	var rows = make([][]string, 0, len(records))
+	for i, column := range columns {
+		widths[i] = utf8.RuneCountInString(column)
+	}
+	for _, record := range records {
+		var row = make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = "NULL"
+			if value, ok := record[column]; ok && value != nil {
+				row[i] = formatValue(value)
+			}
+			if width := utf8.RuneCountInString(row[i]); width > widths[i] {
+				widths[i] = width
+			}
+		}
+		rows = append(rows, row)
+	}
+	var separator = make([]string, len(columns))
+	for i, width := range widths {
+		separator[i] = strings.Repeat("-", width+2)
+	}
+	line := "+" + strings.Join(separator, "+") + "+\n"
+	formatRow := func(row []string) string {
+		var cells = make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = " " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " "
+		}
+		return "|" + strings.Join(cells, "|") + "|\n"
+	}
+	text := line + formatRow(columns) + line
+	for _, row := range rows {
+		text += formatRow(row)
+	}
+	if len(rows) > 0 {
+		text += line
+	}
+	text += fmt.Sprintf("%v row(s)\n", len(rows))
+	_, err := io.WriteString(o.writer, text)
+	return err
+}
+
+func (o *output) message(text string) error {
+	if o.format == jsonFormat {
+		return json.NewEncoder(o.writer).Encode(map[string]interface{}{"message": text})
+	}
+	if o.format == csvFormat {
+		return nil
+	}
+	_, err := fmt.Fprintln(o.writer, text)
+	return err
+}
+
+//formatValue returns text representation of a value, lists and maps are JSON encoded
+func formatValue(value interface{}) string {
+	switch actual := value.(type) {
+	case string:
+		return actual
+	case []byte:
+		return string(actual)
+	case []interface{}, map[string]interface{}, []string, []float64:
+		if data, err := json.Marshal(actual); err == nil {
+			return string(data)
+		}
+	}
+	return toolbox.AsString(value)
+}
+
+func newOutput(format string, writer io.Writer) (*output, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case tableFormat, jsonFormat, csvFormat:
+		return &output{format: format, writer: writer}, nil
+	}
+	return nil, fmt.Errorf("unsupported format: %v", format)
+}
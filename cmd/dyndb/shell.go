@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"github.com/adrianwit/dyndb"
+	"github.com/viant/dsc"
+	"io"
+	"sort"
+	"strings"
+)
+
+//shell executes statements with dyndb manager and writes results with configured format
+type shell struct {
+	manager dsc.Manager
+	dialect dsc.DatastoreDialect
+	output  *output
+}
+
+//runAll runs semicolon separated statements, it stops on the first error
+func (s *shell) runAll(text string) error {
+	statements, rest := splitStatements(text)
+	if strings.TrimSpace(rest) != "" {
+		statements = append(statements, rest)
+	}
+	for _, statement := range statements {
+		if err := s.run(statement); err != nil {
+			return fmt.Errorf("failed to run %v: %v", statement, err)
+		}
+	}
+	return nil
+}
+
+//run runs a single statement
+func (s *shell) run(statement string) error {
+	statement = strings.TrimSpace(statement)
+	words := strings.Fields(strings.ToUpper(statement))
+	if len(words) == 0 {
+		return nil
+	}
+	switch words[0] {
+	case "SHOW":
+		if len(words) == 2 && words[1] == "TABLES" {
+			return s.showTables()
+		}
+	case "DESCRIBE", "DESC":
+		if len(words) == 2 {
+			return s.describe(strings.Fields(statement)[1])
+		}
+		return fmt.Errorf("expected DESCRIBE <table>")
+	case "EXPLAIN":
+		return s.explain(strings.TrimSpace(statement[len("EXPLAIN"):]))
+	case "SELECT":
+		return s.query(statement)
+	case "PARTIQL":
+		if len(words) > 1 && words[1] == "SELECT" {
+			return s.query(statement)
+		}
+	}
+	return s.execute(statement)
+}
+
+func (s *shell) showTables() error {
+	tables, err := s.dialect.GetTables(s.manager, "")
+	if err != nil {
+		return err
+	}
+	sort.Strings(tables)
+	var records = make([]map[string]interface{}, 0, len(tables))
+	for _, table := range tables {
+		records = append(records, map[string]interface{}{"Table": table})
+	}
+	return s.output.write([]string{"Table"}, records)
+}
+
+func (s *shell) describe(table string) error {
+	columns, err := s.dialect.GetColumns(s.manager, "", table)
+	if err != nil {
+		return err
+	}
+	keys := strings.Split(s.dialect.GetKeyName(s.manager, "", table), ",")
+	var records = make([]map[string]interface{}, 0, len(columns))
+	for _, column := range columns {
+		record := map[string]interface{}{
+			"Column": column.Name(),
+			"Type":   column.DatabaseTypeName(),
+			"Key":    "",
+		}
+		if nullable, ok := column.Nullable(); ok {
+			record["Nullable"] = nullable
+		}
+		for i, key := range keys {
+			if key != column.Name() {
+				continue
+			}
+			record["Key"] = "HASH"
+			if i > 0 {
+				record["Key"] = "RANGE"
+			}
+		}
+		records = append(records, record)
+	}
+	return s.output.write([]string{"Column", "Type", "Nullable", "Key"}, records)
+}
+
+func (s *shell) explain(statement string) error {
+	plan, err := dyndb.Explain(s.manager, statement, nil)
+	if err != nil {
+		return err
+	}
+	record := map[string]interface{}{
+		"Operation":      plan.Operation,
+		"Write":          plan.Write,
+		"Table":          plan.Table,
+		"Index":          plan.Index,
+		"KeyCondition":   plan.KeyCondition,
+		"Filter":         plan.Filter,
+		"Projection":     plan.Projection,
+		"EstimatedPages": plan.EstimatedPages,
+	}
+	return s.output.write([]string{"Operation", "Write", "Table", "Index", "KeyCondition", "Filter", "Projection", "EstimatedPages"}, []map[string]interface{}{record})
+}
+
+func (s *shell) query(statement string) error {
+	var columns []string
+	var known = make(map[string]bool)
+	var records = make([]map[string]interface{}, 0)
+	err := s.manager.ReadAllWithHandler(statement, nil, func(scanner dsc.Scanner) (bool, error) {
+		if columns == nil {
+			scannerColumns, err := scanner.Columns()
+			if err != nil {
+				return false, err
+			}
+			columns = make([]string, 0, len(scannerColumns))
+			for _, column := range scannerColumns {
+				known[column] = true
+				columns = append(columns, column)
+			}
+		}
+		var record = make(map[string]interface{})
+		if err := scanner.Scan(record); err != nil {
+			return false, err
+		}
+		var extra = make([]string, 0)
+		for key := range record {
+			if !known[key] {
+				known[key] = true
+				extra = append(extra, key)
+			}
+		}
+		sort.Strings(extra)
+		columns = append(columns, extra...)
+		records = append(records, record)
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.output.write(columns, records)
+}
+
+func (s *shell) execute(statement string) error {
+	result, err := s.manager.Execute(statement)
+	if err != nil {
+		return err
+	}
+	affected, _ := result.RowsAffected()
+	return s.output.message(fmt.Sprintf("%v row(s) affected", affected))
+}
+
+//splitStatements returns semicolon terminated statements and unterminated remainder
+func splitStatements(text string) ([]string, string) {
+	var result = make([]string, 0)
+	var quote rune
+	begin := 0
+	for i, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ';':
+			if statement := strings.TrimSpace(text[begin:i]); statement != "" {
+				result = append(result, statement)
+			}
+			begin = i + 1
+		}
+	}
+	return result, text[begin:]
+}
+
+func newShell(manager dsc.Manager, format string, writer io.Writer) (*shell, error) {
+	output, err := newOutput(format, writer)
+	if err != nil {
+		return nil, err
+	}
+	return &shell{
+		manager: manager,
+		dialect: dsc.GetDatastoreDialect(manager.Config().DriverName),
+		output:  output,
+	}, nil
+}
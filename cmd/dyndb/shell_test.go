@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	statements, rest := splitStatements("SELECT * FROM music WHERE Artist = 'a;b';\nSHOW TABLES;  DESCRIBE music")
+	assert.EqualValues(t, []string{"SELECT * FROM music WHERE Artist = 'a;b'", "SHOW TABLES"}, statements)
+	assert.EqualValues(t, "  DESCRIBE music", rest)
+}
+
+func TestOutput(t *testing.T) {
+	columns := []string{"Artist", "Price", "Tags"}
+	records := []map[string]interface{}{
+		{"Artist": "Artist1", "Price": 1.5, "Tags": []interface{}{"pop", "rock"}},
+		{"Artist": "Artist2"},
+	}
+	var useCases = []struct {
+		format string
+		expect string
+	}{
+		{
+			format: tableFormat,
+			expect: `+---------+-------+----------------+
+| Artist  | Price | Tags           |
++---------+-------+----------------+
+| Artist1 | 1.5   | ["pop","rock"] |
+| Artist2 | NULL  | NULL           |
++---------+-------+----------------+
+2 row(s)
+`,
+		},
+		{
+			format: csvFormat,
+			expect: "Artist,Price,Tags\nArtist1,1.5,\"[\"\"pop\"\",\"\"rock\"\"]\"\nArtist2,,\n",
+		},
+		{
+			format: jsonFormat,
+			expect: `{"Artist":"Artist1","Price":1.5,"Tags":["pop","rock"]}` + "\n" + `{"Artist":"Artist2"}` + "\n",
+		},
+	}
+	for _, useCase := range useCases {
+		buffer := new(bytes.Buffer)
+		output, err := newOutput(useCase.format, buffer)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Nil(t, output.write(columns, records))
+		assert.EqualValues(t, useCase.expect, buffer.String(), useCase.format)
+	}
+}
@@ -1,16 +1,22 @@
 package dyndb
 
 import (
+	"context"
 	"fmt"
+	dynendpoint "github.com/adrianwit/dyndb/endpoint"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
 	"github.com/viant/dsc"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/cred"
 	"github.com/viant/toolbox/secret"
 	"strings"
+	"sync"
 )
 
 const (
@@ -19,19 +25,73 @@ const (
 	regionKey   = "region"
 	endpointKey = "endpoint"
 
+	//memoryScheme endpoint prefix selects in-memory DynamoDB shared by name i.e. memory://test, it is registered by memdb package
+	memoryScheme = "memory://"
+
 	dbnameKey = "dbname"
+
+	//sdkKey selects AWS SDK backing default client: v1 or v2, build tag sdkv2 changes the default
+	sdkKey = "sdk"
+	sdkV1  = "v1"
+	sdkV2  = "v2"
 )
 
 var DbPointer = (*dynamodb.DynamoDB)(nil)
 
-func asDatabase(connection dsc.Connection) (*dynamodb.DynamoDB, error) {
-	db := connection.Unwrap(DbPointer).(*dynamodb.DynamoDB)
+//DbAPIPointer unwraps connection client as dynamodbiface.DynamoDBAPI, it works with any client factory
+var DbAPIPointer = (*dynamodbiface.DynamoDBAPI)(nil)
+
+//ClientFactory creates DynamoDB client for the connection session and config, it allows mocks, wrappers or alternate implementations
+type ClientFactory func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error)
+
+//streamsClientFactory creates DynamoDB Streams client for the connection session and config
+type streamsClientFactory func(sess *session.Session, awsConfig *aws.Config) (dynamodbstreamsiface.DynamoDBStreamsAPI, error)
+
+//newClient is default client factory
+func newClient(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+	return dynamodb.New(sess, awsConfig), nil
+}
+
+//newStreamsClient is default streams client factory
+func newStreamsClient(sess *session.Session, awsConfig *aws.Config) (dynamodbstreamsiface.DynamoDBStreamsAPI, error) {
+	return dynamodbstreams.New(sess, awsConfig), nil
+}
+
+func asDatabase(connection dsc.Connection) (dynamodbiface.DynamoDBAPI, error) {
+	db, ok := connection.Unwrap(DbAPIPointer).(dynamodbiface.DynamoDBAPI)
+	if !ok {
+		return nil, fmt.Errorf("unsupported connection: %T", connection)
+	}
 	return db, nil
 }
 
 type connection struct {
 	*dsc.AbstractConnection
-	db *dynamodb.DynamoDB
+	db  dynamodbiface.DynamoDBAPI
+	ctx context.Context
+}
+
+//withContext returns connection handle sharing the client, its client calls use supplied context
+func withContext(source dsc.Connection, ctx context.Context) dsc.Connection {
+	if actual, ok := source.(*connection); ok {
+		result := *actual
+		result.ctx = ctx
+		return &result
+	}
+	return source
+}
+
+//connectionContext returns connection context or background context
+func connectionContext(source dsc.Connection) context.Context {
+	if actual, ok := source.(*connection); ok && actual.ctx != nil {
+		return actual.ctx
+	}
+	return context.Background()
+}
+
+//Close does nothing, connection is a lightweight handle of the provider shared client
+func (c *connection) Close() error {
+	return nil
 }
 
 func (c *connection) CloseNow() error {
@@ -39,39 +99,162 @@ func (c *connection) CloseNow() error {
 }
 
 func (c *connection) Unwrap(targetType interface{}) interface{} {
-	if targetType == DbPointer {
+	switch targetType {
+	case DbAPIPointer:
 		return c.db
+	case DbPointer:
+		if db, ok := c.db.(*dynamodb.DynamoDB); ok {
+			return db
+		}
+		panic(fmt.Sprintf("connection client %T is not %T, use DbAPIPointer", c.db, DbPointer))
 	}
 	panic(fmt.Sprintf("unsupported targetType type %v", targetType))
 }
 
+//connectionProvider creates connections sharing a single goroutine safe client created with the first connection
 type connectionProvider struct {
 	*dsc.AbstractConnectionProvider
+	clientFactory ClientFactory
+	mutex         sync.Mutex
+	session       *session.Session
+	config        *aws.Config
+	db            dynamodbiface.DynamoDBAPI
+}
+
+//Get returns a new connection handle, it does not wait for pooled connections as all connections share the same client
+func (p *connectionProvider) Get() (dsc.Connection, error) {
+	return p.NewConnection()
 }
 
 func (p *connectionProvider) NewConnection() (dsc.Connection, error) {
 	config := p.ConnectionProvider.Config()
-	credConfig, err := getCredConfig(config)
+	db, err := p.client()
 	if err != nil {
 		return nil, err
 	}
-	awsConfig := getAWSConfig(credConfig)
-	if awsConfig.Region == nil {
-		return nil, fmt.Errorf("region was empty")
-	}
-	awsConfig = p.applyOptions(awsConfig)
-	sess := session.Must(session.NewSession())
-	db := dynamodb.New(sess, awsConfig)
 	var connection = &connection{db: db}
 	var super = dsc.NewAbstractConnection(config, p.ConnectionProvider.ConnectionPool(), connection)
 	connection.AbstractConnection = super
 	return connection, nil
 }
 
-func (p *connectionProvider) applyOptions(awsConfig *aws.Config) *aws.Config {
+//client returns shared client, session, aws config and HTTP client are created once per provider
+func (p *connectionProvider) client() (dynamodbiface.DynamoDBAPI, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.db != nil {
+		return p.db, nil
+	}
+	if err := p.initSession(); err != nil {
+		return nil, err
+	}
+	clientFactory, err := p.getClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	var db dynamodbiface.DynamoDBAPI
+	if primary, _ := getRegions(p.Config()); primary != "" {
+		db, err = newMultiRegionClient(p.Config(), p.session, p.config, clientFactory)
+	} else if db, err = clientFactory(p.session, p.config); err != nil {
+		err = fmt.Errorf("failed to create DynamoDB client, %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.db = db
+	return db, nil
+}
+
+//initSession creates session and aws config with shared HTTP client if needed
+func (p *connectionProvider) initSession() error {
+	if p.session != nil {
+		return nil
+	}
+	awsConfig, err := p.awsConfig()
+	if err != nil {
+		return err
+	}
+	p.config = awsConfig.WithHTTPClient(newHTTPClient(p.Config()))
+	p.session, err = session.NewSession()
+	return err
+}
+
+//getClientFactory returns custom client factory or default one for configured SDK version
+func (p *connectionProvider) getClientFactory() (ClientFactory, error) {
+	if p.clientFactory != nil {
+		return p.clientFactory, nil
+	}
+	sdk := p.sdk()
+	if clientFactory, ok := sdkClientFactories[sdk]; ok {
+		return clientFactory, nil
+	}
+	return nil, unsupportedSDK(sdk)
+}
+
+//sdk returns configured AWS SDK version
+func (p *connectionProvider) sdk() string {
+	if p.Config().Has(sdkKey) {
+		return strings.ToLower(p.Config().Get(sdkKey))
+	}
+	return defaultSDK
+}
+
+//unsupportedSDK returns error for SDK version not compiled in
+func unsupportedSDK(sdk string) error {
+	if sdk == sdkV2 {
+		return fmt.Errorf("%v: %v requires build with sdkv2 tag", sdkKey, sdk)
+	}
+	return fmt.Errorf("unsupported %v: %v, expected %v or %v", sdkKey, sdk, sdkV1, sdkV2)
+}
+
+//awsConfig returns aws config for the provider config
+func (p *connectionProvider) awsConfig() (*aws.Config, error) {
+	p.updateParameters()
+	credConfig, err := getCredConfig(p.ConnectionProvider.Config())
+	if err != nil {
+		return nil, err
+	}
+	if credConfig.Region == "" {
+		credConfig.Region, _ = getRegions(p.Config())
+	}
+	awsConfig := getAWSConfig(credConfig)
+	if awsConfig.Region == nil {
+		return nil, fmt.Errorf("region was empty")
+	}
+	return p.applyOptions(awsConfig)
+}
+
+//newStreamsClient returns DynamoDB Streams client for configured SDK version sharing connection config
+func (p *connectionProvider) newStreamsClient() (dynamodbstreamsiface.DynamoDBStreamsAPI, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.initSession(); err != nil {
+		return nil, err
+	}
+	sdk := p.sdk()
+	clientFactory, ok := sdkStreamsClientFactories[sdk]
+	if !ok {
+		return nil, unsupportedSDK(sdk)
+	}
+	return clientFactory(p.session, p.config)
+}
+
+func (p *connectionProvider) applyOptions(awsConfig *aws.Config) (*aws.Config, error) {
 	p.updateParameters()
 	if p.Config().Has(endpointKey) {
 		endpoint := p.Config().Get(endpointKey)
+		resolved, ok, err := dynendpoint.Resolve(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			endpoint = resolved
+			if !p.Config().Has(keyKey) {
+				awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(memoryScheme, memoryScheme, ""))
+			}
+		} else if strings.HasPrefix(endpoint, memoryScheme) {
+			return nil, fmt.Errorf("%v endpoint requires importing github.com/adrianwit/dyndb/memdb package", endpoint)
+		}
 		if !strings.Contains(endpoint, ":") {
 			endpoint = endpoint + ":8000"
 		}
@@ -91,7 +274,7 @@ func (p *connectionProvider) applyOptions(awsConfig *aws.Config) *aws.Config {
 		c := credentials.NewStaticCredentials(key, secret, "")
 		awsConfig = awsConfig.WithCredentials(c)
 	}
-	return awsConfig
+	return awsConfig, nil
 }
 
 func (p *connectionProvider) updateParameters() {
@@ -104,11 +287,8 @@ func (p *connectionProvider) updateParameters() {
 	}
 }
 
-func newConnectionProvider(config *dsc.Config) dsc.ConnectionProvider {
-	if config.MaxPoolSize == 0 {
-		config.MaxPoolSize = 1
-	}
-	aerospikeConnectionProvider := &connectionProvider{}
+func newConnectionProvider(config *dsc.Config, clientFactory ClientFactory) dsc.ConnectionProvider {
+	aerospikeConnectionProvider := &connectionProvider{clientFactory: clientFactory}
 	var connectionProvider dsc.ConnectionProvider = aerospikeConnectionProvider
 	var super = dsc.NewAbstractConnectionProvider(config, make(chan dsc.Connection, config.MaxPoolSize), connectionProvider)
 	aerospikeConnectionProvider.AbstractConnectionProvider = super
@@ -1,22 +1,150 @@
 package dyndb_test
 
 import (
+	"github.com/adrianwit/dyndb"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/dsc"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewConnection(t *testing.T) {
-	config, err := dsc.NewConfigWithParameters("dyndbc", "", "", nil)
+	var useCases = []struct {
+		description string
+		sdk         string
+		hasError    bool
+	}{
+		{
+			description: "SDK v1 client",
+			sdk:         "v1",
+		},
+		{
+			description: "unsupported SDK",
+			sdk:         "v3",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		connection, err := newSDKConnection(useCase.sdk)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.NotNil(t, connection.Unwrap(dyndb.DbPointer).(*dynamodb.DynamoDB), useCase.description)
+	}
+}
+
+//newSDKConnection creates memory backed connection for supplied sdk
+func newSDKConnection(sdk string) (dsc.Connection, error) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://connection",
+		"region":   "us-west-1",
+		"sdk":      sdk,
+	})
+	if err != nil {
+		return nil, err
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if err != nil {
+		return nil, err
+	}
+	return manager.ConnectionProvider().NewConnection()
+}
+
+//countingClient wraps DynamoDB client counting ListTables calls
+type countingClient struct {
+	dynamodbiface.DynamoDBAPI
+	listTables int
+}
+
+func (c *countingClient) ListTables(input *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+	c.listTables++
+	return c.DynamoDBAPI.ListTables(input)
+}
+
+func TestNewManagerFactory(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://client",
+		"region":   "us-west-1",
+	})
 	if !assert.Nil(t, err) {
 		return
 	}
-	factory := dsc.NewManagerFactory()
+	client := &countingClient{}
+	factory := dyndb.NewManagerFactory(func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+		client.DynamoDBAPI = dynamodb.New(sess, awsConfig)
+		return client, nil
+	})
 	manager, err := factory.Create(config)
 	if !assert.Nil(t, err) {
 		return
 	}
-	provider := manager.ConnectionProvider()
-	_, err = provider.NewConnection()
+	dialect := dsc.GetDatastoreDialect("dyndb")
+	_, err = dialect.GetTables(manager, "")
 	assert.Nil(t, err)
+	assert.EqualValues(t, 1, client.listTables)
+
+	connection, err := manager.ConnectionProvider().NewConnection()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, client, connection.Unwrap(dyndb.DbAPIPointer))
+	assert.Panics(t, func() {
+		connection.Unwrap(dyndb.DbPointer)
+	})
+}
+
+func TestConnectionProvider_Get(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint":         "memory://provider",
+		"region":           "us-west-1",
+		"requestTimeoutMs": 5000,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var created int32
+	factory := dyndb.NewManagerFactory(func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+		atomic.AddInt32(&created, 1)
+		assert.EqualValues(t, 5*time.Second, awsConfig.HTTPClient.Timeout)
+		return dynamodb.New(sess, awsConfig), nil
+	})
+	manager, err := factory.Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	//connections are handles of the shared client
+	for i := 0; i < 5; i++ {
+		_, err := manager.ConnectionProvider().Get()
+		assert.Nil(t, err)
+	}
+
+	var clients = make([]interface{}, 20)
+	var group sync.WaitGroup
+	for i := range clients {
+		group.Add(1)
+		go func(i int) {
+			defer group.Done()
+			connection, err := manager.ConnectionProvider().Get()
+			if !assert.Nil(t, err) {
+				return
+			}
+			clients[i] = connection.Unwrap(dyndb.DbAPIPointer)
+			assert.Nil(t, connection.Close())
+		}(i)
+	}
+	group.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&created))
+	for _, client := range clients {
+		assert.True(t, client == clients[0])
+	}
 }
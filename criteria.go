@@ -2,10 +2,9 @@ package dyndb
 
 import (
 	"fmt"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/viant/dsc"
 	"github.com/viant/toolbox"
+	"sort"
 	"strings"
 )
 
@@ -62,53 +61,37 @@ func getKeyCriteriaMap(sqlCriteria *dsc.SQLCriteria, paramIterator toolbox.Itera
 	return result, nil
 }
 
-//processes (key1,key2) IN((), ()) with multi handler call, otherwise calls handler with input getCriteriaExpression values
+//processes (key1,key2) IN((), ()) and key IN () with handler call per each values combination, otherwise calls handler with input getCriteriaExpression values
 func processCriteria(criteriaValues map[string]interface{}, handler func(values map[string]interface{}) (bool, error)) (bool, error) {
-	multiKey := ""
-	var multiValues = make([]interface{}, 0)
+	var multiKeys = make([]string, 0)
 	var values = make(map[string]interface{})
 	for k, v := range criteriaValues {
-		if strings.Count(k, ",") > 0 && toolbox.IsSlice(v) {
-			multiKey = k
-			multiValues = toolbox.AsSlice(v)
+		if _, ok := v.([]interface{}); ok || (strings.Count(k, ",") > 0 && toolbox.IsSlice(v)) {
+			multiKeys = append(multiKeys, k)
 			continue
 		}
 		values[k] = v
 	}
-	if multiKey == "" {
+	if len(multiKeys) == 0 {
 		return handler(criteriaValues)
 	}
-	multiKeys := strings.Split(strings.Trim(multiKey, "()"), ",")
-	for i := 0; i < len(multiValues); i += len(multiKeys) {
-		for j := 0; j < len(multiKeys); j++ {
-			values[strings.TrimSpace(multiKeys[j])] = multiValues[i+j]
+	sort.Strings(multiKeys)
+	var expand func(index int) (bool, error)
+	expand = func(index int) (bool, error) {
+		if index == len(multiKeys) {
+			return handler(values)
 		}
-		if ok, err := handler(values); err != nil || !ok {
-			return ok, err
-		}
-
-	}
-	return true, nil
-}
-
-//getCriteriaExpression returns expression with expression attributes or error
-func getCriteriaExpression(sqlCriteria *dsc.SQLCriteria, paramIterator toolbox.Iterator) (*string, map[string]*dynamodb.AttributeValue, error) {
-	criteriaExpression := sqlCriteria.Expression()
-	values := make(map[string]interface{})
-	var i = 1
-	for paramIterator.HasNext() {
-		var value interface{}
-		if err := paramIterator.Next(&value); err != nil {
-			return nil, nil, err
+		keys := strings.Split(strings.Trim(multiKeys[index], "()"), ",")
+		multiValues := toolbox.AsSlice(criteriaValues[multiKeys[index]])
+		for i := 0; i+len(keys) <= len(multiValues); i += len(keys) {
+			for j := 0; j < len(keys); j++ {
+				values[strings.TrimSpace(keys[j])] = multiValues[i+j]
+			}
+			if ok, err := expand(index + 1); err != nil || !ok {
+				return ok, err
+			}
 		}
-		namedParam := fmt.Sprintf(":p%v", i)
-		criteriaExpression = strings.Replace(criteriaExpression, "?", namedParam, 1)
-		values[namedParam] = value
-		i++
-	}
-	criteriaAttributes, err := dynamodbattribute.MarshalMap(values)
-	if err != nil {
-		return nil, nil, err
+		return true, nil
 	}
-	return &criteriaExpression, criteriaAttributes, nil
+	return expand(0)
 }
@@ -0,0 +1,300 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"strings"
+)
+
+const (
+	batchWriteItemOperation = "BatchWriteItem"
+
+	//truncateKeyword statement deleting all table items i.e. TRUNCATE TABLE music
+	truncateKeyword = "TRUNCATE"
+	//recreateHint truncates table by dropping and creating it with the same description i.e. TRUNCATE /*+ RECREATE */ TABLE music
+	recreateHint = "RECREATE"
+)
+
+//planDelete returns DeleteItem plan if criteria matches primary keys, otherwise Query or Scan plan reading keys of items to delete with BatchWriteItem
+func (m *manager) planDelete(db dynamodbiface.DynamoDBAPI, table, where string, queryHints hints, parameters []interface{}) (*Plan, error) {
+	result, err := m.planKeys(db, table, where, queryHints, parameters, deleteItemOperation)
+	if err != nil {
+		return nil, err
+	}
+	if result.Write != "" {
+		result.Write = batchWriteItemOperation
+	}
+	return result, nil
+}
+
+//runDelete deletes items matching criteria, it returns number of deleted items
+func (m *manager) runDelete(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string, statement *dsc.DmlStatement, sqlParameters []interface{}, queryHints hints, returned *returnedItems) (affected int, err error) {
+	_, where := splitCriteria(SQL)
+	plan, err := m.planDelete(db, statement.Table, where, queryHints, sqlParameters)
+	if err != nil {
+		return 0, err
+	}
+	if err = m.checkScan(SQL, plan); err != nil {
+		return 0, err
+	}
+	if plan.Operation == deleteItemOperation {
+		return deleteItems(ctx, db, plan.Table, plan.keys, returned)
+	}
+	return m.deleteMatched(ctx, db, plan, returned)
+}
+
+//deleteMatched deletes items read page by page by Query or Scan plan, items are deleted one by one if their images are returned, it returns number of deleted items
+func (m *manager) deleteMatched(ctx context.Context, db dynamodbiface.DynamoDBAPI, plan *Plan, returned *returnedItems) (int, error) {
+	var affected int
+	err := plan.readPages(ctx, db, func(items []map[string]*dynamodb.AttributeValue, _ int64) (bool, error) {
+		var deleted int
+		var err error
+		if returned != nil {
+			deleted, err = deleteItems(ctx, db, plan.Table, items, returned)
+		} else {
+			deleted, err = deleteKeys(ctx, db, plan.Table, items)
+		}
+		affected += deleted
+		return err == nil, err
+	})
+	return affected, err
+}
+
+//deleteItems deletes items by keys with DeleteItem returning old images, it returns number of items that existed
+func deleteItems(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string, keys []map[string]*dynamodb.AttributeValue, returned *returnedItems) (int, error) {
+	var affected int
+	var deleted = make(map[string]bool)
+	for _, key := range keys {
+		described := describeKey(key)
+		if deleted[described] {
+			continue
+		}
+		deleted[described] = true
+		output, err := db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+			TableName:    aws.String(table),
+			Key:          key,
+			ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+		})
+		if err != nil {
+			return affected, err
+		}
+		if len(output.Attributes) == 0 {
+			continue
+		}
+		affected++
+		if err = returned.add(output.Attributes); err != nil {
+			return affected, err
+		}
+	}
+	return affected, nil
+}
+
+//deleteKeys deletes items by keys with BatchWriteItem, duplicated keys are deleted once, it returns number of deleted keys
+func deleteKeys(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string, keys []map[string]*dynamodb.AttributeValue) (int, error) {
+	var affected int
+	var requests = make([]*dynamodb.WriteRequest, 0, maxBatchWriteItems)
+	flush := func() error {
+		if len(requests) == 0 {
+			return nil
+		}
+		if err := batchWriteItems(ctx, db, table, requests); err != nil {
+			return err
+		}
+		affected += len(requests)
+		requests = make([]*dynamodb.WriteRequest, 0, maxBatchWriteItems)
+		return nil
+	}
+	var deleted = make(map[string]bool)
+	for _, key := range keys {
+		described := describeKey(key)
+		if deleted[described] {
+			continue
+		}
+		deleted[described] = true
+		requests = append(requests, &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: key}})
+		if len(requests) == maxBatchWriteItems {
+			if err := flush(); err != nil {
+				return affected, err
+			}
+		}
+	}
+	return affected, flush()
+}
+
+//truncateTableExecution deletes all table items in batches, RECREATE hint drops and creates the table with the same description instead
+func (m *manager) truncateTableExecution(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string) (sql.Result, error) {
+	SQL, queryHints := parseHints(SQL)
+	fragments := strings.Fields(SQL)
+	if len(fragments) == 3 && strings.ToUpper(fragments[1]) == "TABLE" {
+		fragments = append(fragments[:1], fragments[2])
+	}
+	if len(fragments) != 2 || strings.ToUpper(fragments[0]) != truncateKeyword {
+		return nil, fmt.Errorf("invalid truncate statement: %v, expected TRUNCATE TABLE name", SQL)
+	}
+	table := m.tableName(strings.Trim(fragments[1], "`\";"))
+	if queryHints.Has(recreateHint) {
+		defer m.tables.invalidate(table)
+		return dsc.NewSQLResult(0, 0), m.recreateTable(ctx, primaryClient(db), table)
+	}
+	plan, err := m.planDelete(db, table, "", queryHints, nil)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := m.deleteMatched(ctx, db, plan, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to truncate %v, %v", table, err)
+	}
+	return dsc.NewSQLResult(int64(affected), 0), nil
+}
+
+//recreateTable drops and creates table preserving keys, indexes, billing mode, throughput, streams, encryption, table class, TTL,
+//point in time recovery and tags, global tables are not recreated as dropping the table would delete its replicas,
+//cached description is refreshed so that the table is recreated as it is now
+func (m *manager) recreateTable(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string) error {
+	m.tables.invalidate(table)
+	description, err := m.describe(db, table)
+	if err != nil {
+		return err
+	}
+	if len(description.Replicas) > 0 {
+		var regions = make([]string, 0, len(description.Replicas))
+		for _, replica := range description.Replicas {
+			regions = append(regions, aws.StringValue(replica.RegionName))
+		}
+		return fmt.Errorf("failed to recreate %v, global table with replicas in %v can not be recreated, drop the replicas or truncate without %v hint", table, strings.Join(regions, ", "), recreateHint)
+	}
+	input := createTableInput(description)
+	timeToLive, err := db.DescribeTimeToLiveWithContext(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(table)})
+	if err != nil {
+		return fmt.Errorf("failed to describe time to live of %v, %v", table, err)
+	}
+	backups, err := db.DescribeContinuousBackupsWithContext(ctx, &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String(table)})
+	if err != nil {
+		return fmt.Errorf("failed to describe continuous backups of %v, %v", table, err)
+	}
+	tagsInput := &dynamodb.ListTagsOfResourceInput{ResourceArn: description.TableArn}
+	for {
+		tags, err := db.ListTagsOfResourceWithContext(ctx, tagsInput)
+		if err != nil {
+			return fmt.Errorf("failed to list tags of %v, %v", table, err)
+		}
+		input.Tags = append(input.Tags, tags.Tags...)
+		if tags.NextToken == nil {
+			break
+		}
+		tagsInput.NextToken = tags.NextToken
+	}
+	if _, err = db.DeleteTableWithContext(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(table)}); err != nil {
+		return err
+	}
+	if err = waitForTableDeletion(ctx, db, table); err != nil {
+		return err
+	}
+	if _, err = db.CreateTableWithContext(ctx, input); err != nil {
+		return fmt.Errorf("failed to recreate %v, %v", table, err)
+	}
+	if err = waitForCreateCompletion(ctx, db, table); err != nil {
+		return err
+	}
+	if ttl := timeToLive.TimeToLiveDescription; ttl != nil && ttl.AttributeName != nil {
+		switch aws.StringValue(ttl.TimeToLiveStatus) {
+		case dynamodb.TimeToLiveStatusEnabled, dynamodb.TimeToLiveStatusEnabling:
+			_, err = db.UpdateTimeToLiveWithContext(ctx, &dynamodb.UpdateTimeToLiveInput{
+				TableName: aws.String(table),
+				TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+					AttributeName: ttl.AttributeName,
+					Enabled:       aws.Bool(true),
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to restore time to live of %v, %v", table, err)
+			}
+		}
+	}
+	if isPointInTimeRecoveryEnabled(backups.ContinuousBackupsDescription) {
+		if err = alterPointInTimeRecovery(ctx, db, table, "ON"); err != nil {
+			return fmt.Errorf("failed to restore point in time recovery of %v, %v", table, err)
+		}
+	}
+	return nil
+}
+
+//isPointInTimeRecoveryEnabled returns true if point in time recovery is enabled
+func isPointInTimeRecoveryEnabled(description *dynamodb.ContinuousBackupsDescription) bool {
+	if description == nil || description.PointInTimeRecoveryDescription == nil {
+		return false
+	}
+	return aws.StringValue(description.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus) == dynamodb.PointInTimeRecoveryStatusEnabled
+}
+
+//createTableInput returns create table input matching table description
+func createTableInput(description *dynamodb.TableDescription) *dynamodb.CreateTableInput {
+	result := &dynamodb.CreateTableInput{
+		TableName:            description.TableName,
+		AttributeDefinitions: description.AttributeDefinitions,
+		KeySchema:            description.KeySchema,
+		BillingMode:          aws.String(dynamodb.BillingModeProvisioned),
+	}
+	if summary := description.BillingModeSummary; summary != nil && aws.StringValue(summary.BillingMode) == dynamodb.BillingModePayPerRequest {
+		result.BillingMode = summary.BillingMode
+	}
+	provisioned := aws.StringValue(result.BillingMode) == dynamodb.BillingModeProvisioned
+	if provisioned {
+		result.ProvisionedThroughput = provisionedThroughput(description.ProvisionedThroughput)
+	}
+	for _, index := range description.GlobalSecondaryIndexes {
+		globalIndex := &dynamodb.GlobalSecondaryIndex{
+			IndexName:  index.IndexName,
+			KeySchema:  index.KeySchema,
+			Projection: index.Projection,
+		}
+		if provisioned {
+			globalIndex.ProvisionedThroughput = provisionedThroughput(index.ProvisionedThroughput)
+		}
+		result.GlobalSecondaryIndexes = append(result.GlobalSecondaryIndexes, globalIndex)
+	}
+	for _, index := range description.LocalSecondaryIndexes {
+		result.LocalSecondaryIndexes = append(result.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndex{
+			IndexName:  index.IndexName,
+			KeySchema:  index.KeySchema,
+			Projection: index.Projection,
+		})
+	}
+	if stream := description.StreamSpecification; stream != nil && aws.BoolValue(stream.StreamEnabled) {
+		result.StreamSpecification = stream
+	}
+	if sse := description.SSEDescription; sse != nil {
+		switch aws.StringValue(sse.Status) {
+		case dynamodb.SSEStatusEnabled, dynamodb.SSEStatusEnabling, dynamodb.SSEStatusUpdating:
+			result.SSESpecification = &dynamodb.SSESpecification{
+				Enabled:        aws.Bool(true),
+				SSEType:        sse.SSEType,
+				KMSMasterKeyId: sse.KMSMasterKeyArn,
+			}
+		}
+	}
+	if summary := description.TableClassSummary; summary != nil {
+		result.TableClass = summary.TableClass
+	}
+	return result
+}
+
+//provisionedThroughput returns provisioned throughput with at least one capacity unit
+func provisionedThroughput(description *dynamodb.ProvisionedThroughputDescription) *dynamodb.ProvisionedThroughput {
+	result := &dynamodb.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(1), WriteCapacityUnits: aws.Int64(1)}
+	if description == nil {
+		return result
+	}
+	if units := aws.Int64Value(description.ReadCapacityUnits); units > 0 {
+		result.ReadCapacityUnits = aws.Int64(units)
+	}
+	if units := aws.Int64Value(description.WriteCapacityUnits); units > 0 {
+		result.WriteCapacityUnits = aws.Int64(units)
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"strconv"
+	"testing"
+)
+
+func TestCreateTableInput(t *testing.T) {
+	keySchema := []*dynamodb.KeySchemaElement{
+		{AttributeName: aws.String("Artist"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+		{AttributeName: aws.String("SongTitle"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+	}
+	indexKeySchema := []*dynamodb.KeySchemaElement{
+		{AttributeName: aws.String("Genre"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+	}
+	projection := &dynamodb.Projection{ProjectionType: aws.String(dynamodb.ProjectionTypeKeysOnly)}
+	description := &dynamodb.TableDescription{
+		TableName: aws.String("music"),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("Artist"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("SongTitle"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("Genre"), AttributeType: aws.String("S")},
+		},
+		KeySchema:             keySchema,
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(0)},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndexDescription{
+			{IndexName: aws.String("GenreIndex"), KeySchema: indexKeySchema, Projection: projection},
+		},
+		StreamSpecification: &dynamodb.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: aws.String(dynamodb.StreamViewTypeNewImage)},
+		SSEDescription:      &dynamodb.SSEDescription{Status: aws.String(dynamodb.SSEStatusEnabled), SSEType: aws.String(dynamodb.SSETypeKms), KMSMasterKeyArn: aws.String("arn:key")},
+	}
+	input := createTableInput(description)
+	assert.EqualValues(t, "music", *input.TableName)
+	assert.EqualValues(t, keySchema, input.KeySchema)
+	assert.EqualValues(t, 3, len(input.AttributeDefinitions))
+	assert.EqualValues(t, dynamodb.BillingModeProvisioned, *input.BillingMode)
+	assert.EqualValues(t, 5, *input.ProvisionedThroughput.ReadCapacityUnits)
+	assert.EqualValues(t, 1, *input.ProvisionedThroughput.WriteCapacityUnits)
+	if assert.EqualValues(t, 1, len(input.GlobalSecondaryIndexes)) {
+		assert.EqualValues(t, "GenreIndex", *input.GlobalSecondaryIndexes[0].IndexName)
+		assert.EqualValues(t, projection, input.GlobalSecondaryIndexes[0].Projection)
+		assert.EqualValues(t, 1, *input.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits)
+	}
+	assert.EqualValues(t, dynamodb.StreamViewTypeNewImage, *input.StreamSpecification.StreamViewType)
+	assert.EqualValues(t, "arn:key", *input.SSESpecification.KMSMasterKeyId)
+
+	description.BillingModeSummary = &dynamodb.BillingModeSummary{BillingMode: aws.String(dynamodb.BillingModePayPerRequest)}
+	description.StreamSpecification = &dynamodb.StreamSpecification{StreamEnabled: aws.Bool(false)}
+	description.SSEDescription = nil
+	input = createTableInput(description)
+	assert.EqualValues(t, dynamodb.BillingModePayPerRequest, *input.BillingMode)
+	assert.Nil(t, input.ProvisionedThroughput)
+	assert.Nil(t, input.GlobalSecondaryIndexes[0].ProvisionedThroughput)
+	assert.Nil(t, input.StreamSpecification)
+	assert.Nil(t, input.SSESpecification)
+}
+
+//pagedTags wraps DynamoDB client returning one tag per ListTagsOfResource page
+type pagedTags struct {
+	dynamodbiface.DynamoDBAPI
+}
+
+func (p *pagedTags) ListTagsOfResourceWithContext(ctx aws.Context, input *dynamodb.ListTagsOfResourceInput, options ...request.Option) (*dynamodb.ListTagsOfResourceOutput, error) {
+	output, err := p.DynamoDBAPI.ListTagsOfResourceWithContext(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: input.ResourceArn}, options...)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	if input.NextToken != nil {
+		index, _ = strconv.Atoi(*input.NextToken)
+	}
+	result := &dynamodb.ListTagsOfResourceOutput{Tags: output.Tags[index : index+1]}
+	if index+1 < len(output.Tags) {
+		result.NextToken = aws.String(strconv.Itoa(index + 1))
+	}
+	return result, nil
+}
+
+func TestRecreateTable(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://recreate",
+		"region":   "us-west-1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	datastoreManager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS events", "CREATE TABLE events(Id INT HASH KEY) WITH (tags = 'team=payments,env=prod,owner=ops')", "ALTER TABLE events SET PITR ON"} {
+		_, err = datastoreManager.Execute(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	connection, err := datastoreManager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db, _ := asDatabase(connection)
+	if !assert.Nil(t, datastoreManager.(*manager).recreateTable(context.Background(), &pagedTags{DynamoDBAPI: db}, "events")) {
+		return
+	}
+	tags, err := dsc.GetDatastoreDialect("dyndb").(TableTagsDialect).GetTableTags(datastoreManager, "", "events")
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, map[string]string{"team": "payments", "env": "prod", "owner": "ops"}, tags)
+	}
+	backups, err := db.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{TableName: aws.String("events")})
+	if assert.Nil(t, err) {
+		assert.True(t, isPointInTimeRecoveryEnabled(backups.ContinuousBackupsDescription))
+	}
+
+	_, err = datastoreManager.Execute("ALTER TABLE events ADD REPLICA 'eu-west-1'")
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = datastoreManager.(*manager).recreateTable(context.Background(), db, "events")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "eu-west-1")
+	}
+	described, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("events")})
+	if assert.Nil(t, err) {
+		assert.Len(t, described.Table.Replicas, 1)
+	}
+}
@@ -1,8 +1,11 @@
 package dyndb
 
 import (
+	"context"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/viant/dsc"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/url"
@@ -14,6 +17,14 @@ type dialect struct{ dsc.DatastoreDialect }
 
 var maxWaitTime = 2 * time.Minute
 
+const waiterDelay = time.Second
+
+//waiterOptions bound table waiters by maxWaitTime
+var waiterOptions = []request.WaiterOption{
+	request.WithWaiterDelay(request.ConstantWaiterDelay(waiterDelay)),
+	request.WithWaiterMaxAttempts(int(maxWaitTime / waiterDelay)),
+}
+
 //GetKeyName returns a name of column name that is a key, or coma separated list if complex key
 func (d *dialect) GetKeyName(manager dsc.Manager, datastore, table string) string {
 	var result = make([]string, 0)
@@ -37,52 +48,24 @@ func (d *dialect) getKeyAttributes(manager dsc.Manager, table string) ([]*dynamo
 	if err != nil {
 		return nil, err
 	}
-	output, err := db.DescribeTable(&dynamodb.DescribeTableInput{
-		TableName: &table,
-	})
+	description, err := describeCached(manager, db, tableName(manager.Config(), table))
 	if err != nil {
 		return nil, err
 	}
-	return output.Table.KeySchema, nil
+	return description.KeySchema, nil
 }
 
+//GetColumns returns key attributes followed by attributes inferred from sampled items, see sampleSize config parameter
 func (d *dialect) GetColumns(manager dsc.Manager, datastore, table string) ([]dsc.Column, error) {
-	connection, err := manager.ConnectionProvider().Get()
+	schema, err := InferSchema(manager, table, 0)
 	if err != nil {
 		return nil, err
 	}
-	defer connection.Close()
-	db, err := asDatabase(connection)
-	if err != nil {
-		return nil, err
-	}
-	output, err := db.DescribeTable(&dynamodb.DescribeTableInput{
-		TableName: &table,
-	})
-	if err != nil {
-		return nil, err
-	}
-	keys := make(map[string]bool)
-	var result = make([]dsc.Column, 0)
-	for _, item := range output.Table.AttributeDefinitions {
-		keys[*item.AttributeName] = true
-		result = append(result, dsc.NewSimpleColumn(*item.AttributeName, *item.AttributeType))
-	}
-	if queryOutput, err := db.Query(&dynamodb.QueryInput{
-		TableName: aws.String(table),
-		Limit:     aws.Int64(1),
-	}); err == nil && len(queryOutput.Items) > 0 {
-		for k, v := range queryOutput.Items[0] {
-			if keys[k] {
-				continue
-			}
-			result = append(result, dsc.NewSimpleColumn(k, getAttributeType(v)))
-		}
-	}
-	return result, nil
+	return schema.Columns(), nil
 }
 
 func (d *dialect) DropTable(manager dsc.Manager, datastore string, table string) error {
+	table = tableName(manager.Config(), table)
 	connection, err := manager.ConnectionProvider().Get()
 	if err != nil {
 		return err
@@ -92,24 +75,20 @@ func (d *dialect) DropTable(manager dsc.Manager, datastore string, table string)
 	if err != nil {
 		return err
 	}
-	_, err = db.DeleteTable(&dynamodb.DeleteTableInput{
+	db = primaryClient(db)
+	if _, err = db.DeleteTable(&dynamodb.DeleteTableInput{
 		TableName: &table,
-	})
-	waitForTableDeletion(db, table)
+	}); err != nil {
+		return err
+	}
+	err = waitForTableDeletion(context.Background(), db, table)
+	invalidateTable(manager, table)
 	return err
 }
 
-func waitForTableDeletion(db *dynamodb.DynamoDB, table string) {
-	startTime := time.Now()
-	for time.Now().Sub(startTime) < maxWaitTime {
-		_, err := db.DescribeTable(&dynamodb.DescribeTableInput{
-			TableName: aws.String(table),
-		})
-		if err != nil {
-			break
-		}
-		time.Sleep(time.Duration(100) * time.Millisecond)
-	}
+//waitForTableDeletion waits till table is deleted with SDK table not exists waiter
+func waitForTableDeletion(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string) error {
+	return db.WaitUntilTableNotExistsWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table)}, waiterOptions...)
 }
 
 func (d *dialect) CreateTable(manager dsc.Manager, datastore string, table string, specification interface{}) error {
@@ -121,15 +100,36 @@ func (d *dialect) CreateTable(manager dsc.Manager, datastore string, table strin
 			return err
 		}
 	}
+	var tags []*dynamodb.Tag
+	if toolbox.IsMap(specification) { //tags can be specified as map or key=value pairs i.e. {"tags": "team=payments,env=prod"}
+		var err error
+		var options = make(map[string]interface{})
+		for key, value := range toolbox.AsMap(specification) {
+			if key != tagsOption {
+				options[key] = value
+			} else if tags, err = parseTags(value); err != nil {
+				return err
+			}
+		}
+		specification = options
+	}
 	if specification != nil && toolbox.AsString(specification) != "" {
 		if err := toolbox.DefaultConverter.AssignConverted(&input, specification); err != nil {
 			return err
 		}
 	}
+	tags, err := tableTags(manager.Config(), append(input.Tags, tags...))
+	if err != nil {
+		return err
+	}
+	input.Tags = tags
 
 	if table != "" {
 		input.TableName = &table
 	}
+	if input.TableName != nil {
+		input.TableName = aws.String(tableName(manager.Config(), *input.TableName))
+	}
 	connection, err := manager.ConnectionProvider().Get()
 	if err != nil {
 		return err
@@ -140,30 +140,21 @@ func (d *dialect) CreateTable(manager dsc.Manager, datastore string, table strin
 		return err
 	}
 
+	db = primaryClient(db)
 	//TODO create only if table key is different, and drop all data instead for testing
 	_, err = db.CreateTable(input)
 	if err != nil {
 		return err
 	}
 
-	waitForCreateCompletion(db, table)
+	err = waitForCreateCompletion(context.Background(), db, aws.StringValue(input.TableName))
+	invalidateTable(manager, aws.StringValue(input.TableName))
 	return err
 }
 
-func waitForCreateCompletion(db *dynamodb.DynamoDB, table string) {
-	startTime := time.Now()
-	for time.Now().Sub(startTime) < maxWaitTime {
-		output, err := db.DescribeTable(&dynamodb.DescribeTableInput{
-			TableName: aws.String(table),
-		})
-		if err != nil {
-			break
-		}
-		if *output.Table.TableStatus != "CREATING" {
-			break
-		}
-		time.Sleep(time.Duration(100) * time.Millisecond)
-	}
+//waitForCreateCompletion waits till table is active with SDK table exists waiter
+func waitForCreateCompletion(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string) error {
+	return db.WaitUntilTableExistsWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table)}, waiterOptions...)
 }
 
 func (d *dialect) GetDatastores(manager dsc.Manager) ([]string, error) {
@@ -186,18 +177,24 @@ func (d *dialect) GetTables(manager dsc.Manager, datastore string) ([]string, er
 	if err != nil {
 		return nil, err
 	}
-	output, err := db.ListTables(&dynamodb.ListTablesInput{})
-	if err != nil {
-		return nil, err
-	}
+	namespace := newTableNamespace(manager.Config())
 	var result = make([]string, 0)
-	if len(output.TableNames) == 0 {
-		return result, nil
-	}
-	for _, table := range output.TableNames {
-		result = append(result, *table)
+	input := &dynamodb.ListTablesInput{}
+	for {
+		output, err := db.ListTables(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range output.TableNames {
+			if logical, ok := namespace.logical(*table); ok {
+				result = append(result, logical)
+			}
+		}
+		if output.LastEvaluatedTableName == nil {
+			return result, nil
+		}
+		input.ExclusiveStartTableName = output.LastEvaluatedTableName
 	}
-	return result, nil
 }
 
 func (d *dialect) CanCreateDatastore(manager dsc.Manager) bool {
@@ -1,12 +1,14 @@
 package dyndb
 
 import (
+	"database/sql"
 	"github.com/viant/dsc"
 )
 
 func register() {
 	dsc.RegisterManagerFactory("dyndb", newManagerFactory())
 	dsc.RegisterDatastoreDialect("dyndb", newDialect())
+	sql.Register(driverName, &sqlDriver{})
 }
 
 func init() {
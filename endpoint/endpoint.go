@@ -0,0 +1,36 @@
+//Package endpoint registers resolvers of custom endpoint schemes used with dyndb endpoint config parameter,
+//i.e. memdb registers memory:// scheme when imported.
+package endpoint
+
+import (
+	"strings"
+	"sync"
+)
+
+//Resolver returns URL of named endpoint
+type Resolver func(name string) (string, error)
+
+var registry = struct {
+	sync.RWMutex
+	resolvers map[string]Resolver
+}{resolvers: make(map[string]Resolver)}
+
+//Register registers resolver for endpoint scheme i.e. memory://
+func Register(scheme string, resolver Resolver) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.resolvers[scheme] = resolver
+}
+
+//Resolve returns URL of endpoint with registered scheme, ok is false if endpoint scheme is not registered
+func Resolve(endpoint string) (URL string, ok bool, err error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for scheme, resolver := range registry.resolvers {
+		if strings.HasPrefix(endpoint, scheme) {
+			URL, err = resolver(strings.TrimPrefix(endpoint, scheme))
+			return URL, true, err
+		}
+	}
+	return "", false, nil
+}
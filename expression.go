@@ -0,0 +1,542 @@
+package dyndb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//expr represents WHERE clause expression node
+type expr interface{}
+
+type (
+	//pathExpr represents attribute document path i.e. Artist, Address.City, Tags[0]
+	pathExpr struct {
+		Path string
+	}
+	//placeholderExpr represents '?' bind parameter, index is zero based order of occurrence
+	placeholderExpr struct {
+		Index int
+	}
+	//literalExpr represents string, numeric, bool or null literal
+	literalExpr struct {
+		Value interface{}
+	}
+	//callExpr represents function call i.e. size(Tags)
+	callExpr struct {
+		Name string
+		Args []expr
+	}
+	//tupleExpr represents (x, y) operand
+	tupleExpr struct {
+		Items []expr
+	}
+	//logicalExpr represents AND, OR expression
+	logicalExpr struct {
+		Op   string
+		X, Y expr
+	}
+	//notExpr represents NOT expression
+	notExpr struct {
+		X expr
+	}
+	//compareExpr represents =, <>, <, <=, >, >= expression
+	compareExpr struct {
+		Op   string
+		X, Y expr
+	}
+	//betweenExpr represents BETWEEN min AND max expression
+	betweenExpr struct {
+		X, Min, Max expr
+	}
+	//inExpr represents [NOT] IN (values) expression
+	inExpr struct {
+		X      expr
+		Values []expr
+		Not    bool
+	}
+	//likeExpr represents [NOT] LIKE pattern expression
+	likeExpr struct {
+		X, Pattern expr
+		Not        bool
+	}
+	//isNullExpr represents IS [NOT] NULL expression
+	isNullExpr struct {
+		X   expr
+		Not bool
+	}
+)
+
+const (
+	eofToken = iota
+	identToken
+	stringToken
+	numberToken
+	placeholderToken
+	symbolToken
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+//keyword returns upper case token text for identifiers
+func (t *token) keyword() string {
+	if t.kind != identToken {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+//criteriaParser represents recursive descent WHERE clause parser with standard SQL operator precedence
+type criteriaParser struct {
+	tokens       []*token
+	index        int
+	placeholders int
+}
+
+func (p *criteriaParser) peek() *token {
+	return p.tokens[p.index]
+}
+
+func (p *criteriaParser) next() *token {
+	result := p.tokens[p.index]
+	if result.kind != eofToken {
+		p.index++
+	}
+	return result
+}
+
+func (p *criteriaParser) acceptKeyword(keyword string) bool {
+	if p.peek().keyword() == keyword {
+		p.index++
+		return true
+	}
+	return false
+}
+
+func (p *criteriaParser) acceptSymbol(symbol string) bool {
+	if candidate := p.peek(); candidate.kind == symbolToken && candidate.text == symbol {
+		p.index++
+		return true
+	}
+	return false
+}
+
+func (p *criteriaParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.unexpected(symbol)
+	}
+	return nil
+}
+
+func (p *criteriaParser) unexpected(expected string) error {
+	actual := p.peek()
+	if actual.kind == eofToken {
+		return fmt.Errorf("expected %v, but had end of criteria", expected)
+	}
+	return fmt.Errorf("expected %v, but had %v at %v", expected, actual.text, actual.pos)
+}
+
+func (p *criteriaParser) parseOr() (expr, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &logicalExpr{Op: "OR", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *criteriaParser) parseAnd() (expr, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = &logicalExpr{Op: "AND", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *criteriaParser) parseNot() (expr, error) {
+	if p.acceptKeyword("NOT") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{X: x}, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *criteriaParser) parsePredicate() (expr, error) {
+	x, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	candidate := p.peek()
+	if candidate.kind == symbolToken {
+		switch candidate.text {
+		case "=", "<>", "!=", "<", "<=", ">", ">=":
+			p.next()
+			y, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			op := candidate.text
+			if op == "!=" {
+				op = "<>"
+			}
+			return &compareExpr{Op: op, X: x, Y: y}, nil
+		}
+		return x, nil
+	}
+	switch candidate.keyword() {
+	case "IS":
+		p.next()
+		not := p.acceptKeyword("NOT")
+		if !p.acceptKeyword("NULL") {
+			return nil, p.unexpected("NULL")
+		}
+		return &isNullExpr{X: x, Not: not}, nil
+	case "BETWEEN":
+		p.next()
+		return p.parseBetween(x)
+	case "NOT", "IN", "LIKE":
+		not := p.acceptKeyword("NOT")
+		switch {
+		case p.acceptKeyword("IN"):
+			values, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			return &inExpr{X: x, Values: values, Not: not}, nil
+		case p.acceptKeyword("LIKE"):
+			pattern, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &likeExpr{X: x, Pattern: pattern, Not: not}, nil
+		case p.acceptKeyword("BETWEEN"):
+			between, err := p.parseBetween(x)
+			if err != nil {
+				return nil, err
+			}
+			return &notExpr{X: between}, nil
+		}
+		return nil, p.unexpected("IN, LIKE or BETWEEN")
+	}
+	return x, nil
+}
+
+func (p *criteriaParser) parseBetween(x expr) (expr, error) {
+	min, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptKeyword("AND") {
+		return nil, p.unexpected("AND")
+	}
+	max, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &betweenExpr{X: x, Min: min, Max: max}, nil
+}
+
+//parseList parses (operand [, operand]) list
+func (p *criteriaParser) parseList() ([]expr, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var result = make([]expr, 0)
+	for {
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+		if p.acceptSymbol(",") {
+			continue
+		}
+		return result, p.expectSymbol(")")
+	}
+}
+
+func (p *criteriaParser) parseOperand() (expr, error) {
+	candidate := p.next()
+	switch candidate.kind {
+	case placeholderToken:
+		result := &placeholderExpr{Index: p.placeholders}
+		p.placeholders++
+		return result, nil
+	case stringToken:
+		return &literalExpr{Value: candidate.text}, nil
+	case numberToken:
+		if value, err := strconv.ParseInt(candidate.text, 10, 64); err == nil {
+			return &literalExpr{Value: value}, nil
+		}
+		value, err := strconv.ParseFloat(candidate.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %v at %v", candidate.text, candidate.pos)
+		}
+		return &literalExpr{Value: value}, nil
+	case symbolToken:
+		if candidate.text != "(" {
+			break
+		}
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptSymbol(",") {
+			return x, p.expectSymbol(")")
+		}
+		tuple := &tupleExpr{Items: []expr{x}}
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			tuple.Items = append(tuple.Items, item)
+			if !p.acceptSymbol(",") {
+				return tuple, p.expectSymbol(")")
+			}
+		}
+	case identToken:
+		switch candidate.keyword() {
+		case "NULL":
+			return &literalExpr{}, nil
+		case "TRUE", "FALSE":
+			return &literalExpr{Value: candidate.keyword() == "TRUE"}, nil
+		case "AND", "OR", "NOT", "IN", "LIKE", "IS", "BETWEEN":
+			p.index--
+			return nil, p.unexpected("operand")
+		}
+		if p.peek().kind == symbolToken && p.peek().text == "(" && !strings.ContainsAny(candidate.text, ".[") {
+			p.next()
+			call := &callExpr{Name: candidate.text}
+			if p.acceptSymbol(")") {
+				return call, nil
+			}
+			for {
+				if p.acceptSymbol("*") { //i.e. COUNT(*)
+					call.Args = append(call.Args, &pathExpr{Path: "*"})
+					return call, p.expectSymbol(")")
+				}
+				arg, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				call.Args = append(call.Args, arg)
+				if !p.acceptSymbol(",") {
+					return call, p.expectSymbol(")")
+				}
+			}
+		}
+		return &pathExpr{Path: candidate.text}, nil
+	}
+	if candidate.kind != eofToken {
+		p.index--
+	}
+	return nil, p.unexpected("operand")
+}
+
+//tokenize splits criteria into tokens, identifiers include document path selectors i.e. Address.City, Tags[0]
+func tokenize(criteria string) ([]*token, error) {
+	var result = make([]*token, 0)
+	runes := []rune(criteria)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'':
+			value := ""
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == '\'' {
+					if j+1 < len(runes) && runes[j+1] == '\'' {
+						value += "'"
+						j++
+						continue
+					}
+					break
+				}
+				value += string(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal at %v", i)
+			}
+			result = append(result, &token{kind: stringToken, text: value, pos: i})
+			i = j + 1
+		case r == '?':
+			result = append(result, &token{kind: placeholderToken, text: "?", pos: i})
+			i++
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || strings.ContainsRune(".eE", runes[j]) || (strings.ContainsRune("eE", runes[j-1]) && strings.ContainsRune("+-", runes[j]))) {
+				j++
+			}
+			result = append(result, &token{kind: numberToken, text: string(runes[i:j]), pos: i})
+			i = j
+		case isIdentRune(r) || r == '"' || r == '`':
+			j := i
+			path := ""
+			for j < len(runes) {
+				switch {
+				case runes[j] == '"' || runes[j] == '`':
+					end := indexRune(runes, j+1, runes[j])
+					if end == -1 {
+						return nil, fmt.Errorf("unterminated quoted identifier at %v", j)
+					}
+					path += string(runes[j+1 : end])
+					j = end + 1
+				case isIdentRune(runes[j]):
+					start := j
+					for j < len(runes) && (isIdentRune(runes[j]) || unicode.IsDigit(runes[j])) {
+						j++
+					}
+					path += string(runes[start:j])
+				default:
+					return nil, fmt.Errorf("invalid identifier at %v", j)
+				}
+				for j < len(runes) && runes[j] == '[' {
+					end := indexRune(runes, j+1, ']')
+					if end == -1 {
+						return nil, fmt.Errorf("unterminated list index at %v", j)
+					}
+					if _, err := strconv.Atoi(string(runes[j+1 : end])); err != nil {
+						return nil, fmt.Errorf("invalid list index %v at %v", string(runes[j+1:end]), j)
+					}
+					path += string(runes[j : end+1])
+					j = end + 1
+				}
+				if j < len(runes) && runes[j] == '.' {
+					path += "."
+					j++
+					continue
+				}
+				break
+			}
+			result = append(result, &token{kind: identToken, text: path, pos: i})
+			i = j
+		default:
+			symbol := string(r)
+			if i+1 < len(runes) {
+				if pair := string(runes[i : i+2]); pair == "<>" || pair == "!=" || pair == "<=" || pair == ">=" {
+					symbol = pair
+				}
+			}
+			switch symbol {
+			case "=", "<>", "!=", "<", "<=", ">", ">=", "(", ")", ",", "*":
+			default:
+				return nil, fmt.Errorf("unexpected character %v at %v", symbol, i)
+			}
+			result = append(result, &token{kind: symbolToken, text: symbol, pos: i})
+			i += len(symbol)
+		}
+	}
+	return append(result, &token{kind: eofToken, pos: len(runes)}), nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+//parseCriteria parses WHERE clause criteria
+func parseCriteria(criteria string) (expr, error) {
+	tokens, err := tokenize(criteria)
+	if err != nil {
+		return nil, err
+	}
+	parser := &criteriaParser{tokens: tokens}
+	result, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.peek().kind != eofToken {
+		return nil, parser.unexpected("end of criteria")
+	}
+	return result, nil
+}
+
+//splitCriteria splits SQL into statement without WHERE clause and WHERE clause criteria
+func splitCriteria(SQL string) (string, string) {
+	whereIndex, endIndex := -1, len(SQL)
+	depth := 0
+	var quote rune
+	for i, r := range SQL {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			continue
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			continue
+		case r == '(':
+			depth++
+			continue
+		case r == ')':
+			depth--
+			continue
+		}
+		if depth > 0 || !isKeywordAt(SQL, i) {
+			continue
+		}
+		if whereIndex == -1 {
+			if hasKeywordPrefix(SQL[i:], "WHERE") {
+				whereIndex = i
+			}
+			continue
+		}
+		if hasKeywordPrefix(SQL[i:], "GROUP") || hasKeywordPrefix(SQL[i:], "ORDER") || hasKeywordPrefix(SQL[i:], "LIMIT") {
+			endIndex = i
+			break
+		}
+	}
+	if whereIndex == -1 {
+		return SQL, ""
+	}
+	statement := strings.TrimSpace(SQL[:whereIndex])
+	if tail := strings.TrimSpace(SQL[endIndex:]); tail != "" {
+		statement += " " + tail
+	}
+	return statement, strings.TrimSpace(SQL[whereIndex+len("WHERE") : endIndex])
+}
+
+//isKeywordAt returns true if position starts a new word
+func isKeywordAt(SQL string, index int) bool {
+	return index == 0 || unicode.IsSpace(rune(SQL[index-1])) || SQL[index-1] == ')'
+}
+
+func hasKeywordPrefix(text, keyword string) bool {
+	if len(text) < len(keyword) || !strings.EqualFold(text[:len(keyword)], keyword) {
+		return false
+	}
+	return len(text) == len(keyword) || !(isIdentRune(rune(text[len(keyword)])) || unicode.IsDigit(rune(text[len(keyword)])))
+}
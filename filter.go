@@ -0,0 +1,441 @@
+package dyndb
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/viant/toolbox"
+	"strings"
+)
+
+//expressionBuilder translates criteria into DynamoDB expressions, attribute names and values are always bound
+type expressionBuilder struct {
+	parameters []interface{}
+	aliases    map[string]string
+	names      map[string]*string
+	values     map[string]interface{}
+}
+
+//name returns expression attribute name for supplied attribute
+func (b *expressionBuilder) name(attribute string) string {
+	if alias, ok := b.aliases[attribute]; ok {
+		return alias
+	}
+	alias := fmt.Sprintf("#n%d", len(b.aliases)+1)
+	b.aliases[attribute] = alias
+	b.names[alias] = aws.String(attribute)
+	return alias
+}
+
+//path returns document path with expression attribute names i.e. Address.City[0] -> #n1.#n2[0]
+func (b *expressionBuilder) path(path string) string {
+	var result = make([]string, 0)
+	for _, element := range strings.Split(path, ".") {
+		index := ""
+		if position := strings.Index(element, "["); position != -1 {
+			element, index = element[:position], element[position:]
+		}
+		result = append(result, b.name(element)+index)
+	}
+	return strings.Join(result, ".")
+}
+
+//value returns expression attribute value placeholder for supplied value
+func (b *expressionBuilder) value(value interface{}) string {
+	placeholder := fmt.Sprintf(":p%d", len(b.values)+1)
+	b.values[placeholder] = value
+	return placeholder
+}
+
+func (b *expressionBuilder) parameter(placeholder *placeholderExpr) (interface{}, error) {
+	if placeholder.Index >= len(b.parameters) {
+		return nil, fmt.Errorf("missing bind param: %v", placeholder.Index+1)
+	}
+	return b.parameters[placeholder.Index], nil
+}
+
+//condition returns condition expression for supplied criteria
+func (b *expressionBuilder) condition(criteria expr) (string, error) {
+	switch actual := criteria.(type) {
+	case *logicalExpr:
+		x, err := b.condition(actual.X)
+		if err != nil {
+			return "", err
+		}
+		y, err := b.condition(actual.Y)
+		if err != nil {
+			return "", err
+		}
+		return "(" + x + " " + actual.Op + " " + y + ")", nil
+	case *notExpr:
+		x, err := b.condition(actual.X)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + x + ")", nil
+	case *compareExpr:
+		x, err := b.operand(actual.X)
+		if err != nil {
+			return "", err
+		}
+		y, err := b.operand(actual.Y)
+		if err != nil {
+			return "", err
+		}
+		return x + " " + actual.Op + " " + y, nil
+	case *betweenExpr:
+		x, err := b.operand(actual.X)
+		if err != nil {
+			return "", err
+		}
+		min, err := b.operand(actual.Min)
+		if err != nil {
+			return "", err
+		}
+		max, err := b.operand(actual.Max)
+		if err != nil {
+			return "", err
+		}
+		return x + " BETWEEN " + min + " AND " + max, nil
+	case *inExpr:
+		return b.in(actual)
+	case *likeExpr:
+		return b.like(actual)
+	case *isNullExpr:
+		path, ok := actual.X.(*pathExpr)
+		if !ok {
+			return "", fmt.Errorf("unsupported IS NULL operand: %T", actual.X)
+		}
+		x := b.path(path.Path)
+		nullType := b.value("NULL")
+		if actual.Not {
+			return "(attribute_exists(" + x + ") AND NOT attribute_type(" + x + ", " + nullType + "))", nil
+		}
+		return "(attribute_not_exists(" + x + ") OR attribute_type(" + x + ", " + nullType + "))", nil
+	case *callExpr:
+		if strings.ToLower(actual.Name) == "size" {
+			return "", fmt.Errorf("size() is not a condition, compare it with a value")
+		}
+		return b.function(actual)
+	}
+	return "", fmt.Errorf("unsupported condition: %T", criteria)
+}
+
+//operand returns path, value or size() function operand
+func (b *expressionBuilder) operand(operand expr) (string, error) {
+	switch actual := operand.(type) {
+	case *pathExpr:
+		return b.path(actual.Path), nil
+	case *placeholderExpr:
+		value, err := b.parameter(actual)
+		if err != nil {
+			return "", err
+		}
+		return b.value(value), nil
+	case *literalExpr:
+		return b.value(actual.Value), nil
+	case *callExpr:
+		if strings.ToLower(actual.Name) != "size" {
+			return "", fmt.Errorf("unsupported function operand: %v, only size() can be compared", actual.Name)
+		}
+		return b.function(actual)
+	}
+	return "", fmt.Errorf("unsupported operand: %T", operand)
+}
+
+//function returns DynamoDB function expression
+func (b *expressionBuilder) function(call *callExpr) (string, error) {
+	name := strings.ToLower(call.Name)
+	expectedArgs := 1
+	switch name {
+	case "attribute_exists", "attribute_not_exists", "size":
+	case "attribute_type", "begins_with", "contains":
+		expectedArgs = 2
+	default:
+		return "", fmt.Errorf("unsupported function: %v", call.Name)
+	}
+	if len(call.Args) != expectedArgs {
+		return "", fmt.Errorf("invalid %v arguments count: %v, expected: %v", name, len(call.Args), expectedArgs)
+	}
+	path, ok := call.Args[0].(*pathExpr)
+	if !ok {
+		return "", fmt.Errorf("invalid %v first argument: expected attribute path", name)
+	}
+	args := []string{b.path(path.Path)}
+	if expectedArgs == 2 {
+		arg, err := b.operand(call.Args[1])
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+	}
+	return name + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+//in returns IN expression, tuple operand is expanded to OR-ed equality groups, a single placeholder bound to a slice is expanded to IN values
+func (b *expressionBuilder) in(in *inExpr) (string, error) {
+	values, err := b.inValues(in.Values)
+	if err != nil {
+		return "", err
+	}
+	var result string
+	if tuple, ok := in.X.(*tupleExpr); ok {
+		var groups = make([]string, 0)
+		for _, value := range values {
+			items, ok := value.(*tupleExpr)
+			if !ok || len(items.Items) != len(tuple.Items) {
+				return "", fmt.Errorf("invalid IN value, expected tuple with %v items", len(tuple.Items))
+			}
+			var conditions = make([]string, 0)
+			for i := range tuple.Items {
+				condition, err := b.condition(&compareExpr{Op: "=", X: tuple.Items[i], Y: items.Items[i]})
+				if err != nil {
+					return "", err
+				}
+				conditions = append(conditions, condition)
+			}
+			groups = append(groups, "("+strings.Join(conditions, " AND ")+")")
+		}
+		result = "(" + strings.Join(groups, " OR ") + ")"
+	} else {
+		x, err := b.operand(in.X)
+		if err != nil {
+			return "", err
+		}
+		var operands = make([]string, 0)
+		for _, value := range values {
+			operand, err := b.operand(value)
+			if err != nil {
+				return "", err
+			}
+			operands = append(operands, operand)
+		}
+		result = x + " IN (" + strings.Join(operands, ", ") + ")"
+	}
+	if in.Not {
+		return "NOT (" + result + ")", nil
+	}
+	return result, nil
+}
+
+func (b *expressionBuilder) inValues(values []expr) ([]expr, error) {
+	if len(values) != 1 {
+		return values, nil
+	}
+	placeholder, ok := values[0].(*placeholderExpr)
+	if !ok {
+		return values, nil
+	}
+	value, err := b.parameter(placeholder)
+	if err != nil {
+		return nil, err
+	}
+	if _, isBinary := value.([]byte); isBinary || !toolbox.IsSlice(value) {
+		return values, nil
+	}
+	var result = make([]expr, 0)
+	for _, item := range toolbox.AsSlice(value) {
+		result = append(result, &literalExpr{Value: item})
+	}
+	return result, nil
+}
+
+//like returns LIKE expression: 'x%' -> begins_with, '%x%' -> contains, '%' -> attribute_exists, 'x' -> equality, _ wildcard is not supported
+func (b *expressionBuilder) like(like *likeExpr) (string, error) {
+	path, ok := like.X.(*pathExpr)
+	if !ok {
+		return "", fmt.Errorf("unsupported LIKE operand: %T", like.X)
+	}
+	var pattern interface{}
+	switch actual := like.Pattern.(type) {
+	case *literalExpr:
+		pattern = actual.Value
+	case *placeholderExpr:
+		value, err := b.parameter(actual)
+		if err != nil {
+			return "", err
+		}
+		pattern = value
+	}
+	text, ok := pattern.(string)
+	if !ok {
+		return "", fmt.Errorf("unsupported LIKE pattern: %v, expected text", pattern)
+	}
+	hasPrefix := strings.HasPrefix(text, "%")
+	hasSuffix := len(text) > 1 && strings.HasSuffix(text, "%")
+	body := text
+	if hasPrefix {
+		body = body[1:]
+	}
+	if hasSuffix {
+		body = body[:len(body)-1]
+	}
+	if strings.Contains(body, "%") {
+		return "", fmt.Errorf("unsupported LIKE pattern: %v, only leading and trailing %% are supported", text)
+	}
+	if strings.Contains(body, "_") {
+		return "", fmt.Errorf("unsupported LIKE pattern: %v, _ wildcard is not supported", text)
+	}
+	x := b.path(path.Path)
+	var result string
+	switch {
+	case body == "" && (hasPrefix || hasSuffix):
+		result = "attribute_exists(" + x + ")"
+	case hasPrefix && hasSuffix:
+		result = "contains(" + x + ", " + b.value(body) + ")"
+	case hasSuffix:
+		result = "begins_with(" + x + ", " + b.value(body) + ")"
+	case hasPrefix:
+		return "", fmt.Errorf("unsupported LIKE pattern: %v, DynamoDB does not support ends with", text)
+	default:
+		result = x + " = " + b.value(body)
+	}
+	if like.Not {
+		return "NOT (" + result + ")", nil
+	}
+	return result, nil
+}
+
+//attributeNames returns expression attribute names or nil if empty
+func (b *expressionBuilder) attributeNames() map[string]*string {
+	if len(b.names) == 0 {
+		return nil
+	}
+	return b.names
+}
+
+//attributeValues returns marshaled expression attribute values or nil if empty
+func (b *expressionBuilder) attributeValues() (map[string]*dynamodb.AttributeValue, error) {
+	if len(b.values) == 0 {
+		return nil, nil
+	}
+	return marshalMap(b.values)
+}
+
+func newExpressionBuilder(parameters []interface{}) *expressionBuilder {
+	return &expressionBuilder{
+		parameters: parameters,
+		aliases:    make(map[string]string),
+		names:      make(map[string]*string),
+		values:     make(map[string]interface{}),
+	}
+}
+
+//keyCriteria returns criteria values if criteria uses only equality and IN predicates joined with AND,
+//tuple IN predicate values are returned with coma separated column key as expected by processCriteria
+func keyCriteria(criteria expr, parameters []interface{}) (map[string]interface{}, bool, error) {
+	var result = make(map[string]interface{})
+	builder := newExpressionBuilder(parameters)
+	valueOf := func(operand expr) (interface{}, bool, error) {
+		switch actual := operand.(type) {
+		case *literalExpr:
+			return actual.Value, true, nil
+		case *placeholderExpr:
+			value, err := builder.parameter(actual)
+			return value, err == nil, err
+		}
+		return nil, false, nil
+	}
+	columnOf := func(operand expr) (string, bool) {
+		path, ok := operand.(*pathExpr)
+		if !ok || strings.ContainsAny(path.Path, ".[") {
+			return "", false
+		}
+		return path.Path, true
+	}
+	var visit func(criteria expr) (bool, error)
+	visit = func(criteria expr) (bool, error) {
+		switch actual := criteria.(type) {
+		case *logicalExpr:
+			if actual.Op != "AND" {
+				return false, nil
+			}
+			if ok, err := visit(actual.X); !ok || err != nil {
+				return ok, err
+			}
+			return visit(actual.Y)
+		case *compareExpr:
+			if actual.Op != "=" {
+				return false, nil
+			}
+			column, ok := columnOf(actual.X)
+			operand := actual.Y
+			if !ok {
+				column, ok = columnOf(actual.Y)
+				operand = actual.X
+			}
+			if !ok {
+				return false, nil
+			}
+			value, ok, err := valueOf(operand)
+			if !ok || err != nil {
+				return ok, err
+			}
+			if _, has := result[column]; has {
+				return false, nil
+			}
+			result[column] = value
+			return true, nil
+		case *inExpr:
+			if actual.Not {
+				return false, nil
+			}
+			inValues, err := builder.inValues(actual.Values)
+			if err != nil {
+				return false, err
+			}
+			var columns = make([]string, 0)
+			var values = make([]interface{}, 0)
+			if tuple, ok := actual.X.(*tupleExpr); ok {
+				for _, item := range tuple.Items {
+					column, ok := columnOf(item)
+					if !ok {
+						return false, nil
+					}
+					columns = append(columns, column)
+				}
+				for _, inValue := range inValues {
+					items, ok := inValue.(*tupleExpr)
+					if !ok || len(items.Items) != len(columns) {
+						return false, fmt.Errorf("invalid IN value, expected tuple with %v items", len(columns))
+					}
+					for _, item := range items.Items {
+						value, ok, err := valueOf(item)
+						if !ok || err != nil {
+							return ok, err
+						}
+						values = append(values, value)
+					}
+				}
+			} else {
+				column, ok := columnOf(actual.X)
+				if !ok {
+					return false, nil
+				}
+				columns = append(columns, column)
+				for _, inValue := range inValues {
+					value, ok, err := valueOf(inValue)
+					if !ok || err != nil {
+						return ok, err
+					}
+					values = append(values, value)
+				}
+			}
+			key := strings.Join(columns, ",")
+			if _, has := result[key]; has {
+				return false, nil
+			}
+			result[key] = values
+			if len(columns) == 1 && len(values) == 1 {
+				result[key] = values[0]
+			}
+			return true, nil
+		}
+		return false, nil
+	}
+	ok, err := visit(criteria)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
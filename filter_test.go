@@ -0,0 +1,235 @@
+package dyndb
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExpressionBuilder_Condition(t *testing.T) {
+	var useCases = []struct {
+		description string
+		criteria    string
+		parameters  []interface{}
+		expect      string
+		names       map[string]string
+		values      map[string]interface{}
+		hasError    bool
+	}{
+		{
+			description: "equality with placeholder",
+			criteria:    "Artist = ?",
+			parameters:  []interface{}{"Artist0"},
+			expect:      "#n1 = :p1",
+			names:       map[string]string{"#n1": "Artist"},
+			values:      map[string]interface{}{":p1": "Artist0"},
+		},
+		{
+			description: "IN with literals and not equal",
+			criteria:    "Artist IN ('a', 'b') AND Price != 3",
+			expect:      "(#n1 IN (:p1, :p2) AND #n2 <> :p3)",
+			names:       map[string]string{"#n1": "Artist", "#n2": "Price"},
+			values:      map[string]interface{}{":p1": "a", ":p2": "b", ":p3": int64(3)},
+		},
+		{
+			description: "OR precedence with parentheses",
+			criteria:    "Genre = ? AND (Price < ? OR Price > ?) OR Year = 2000",
+			parameters:  []interface{}{"rock", 1, 10},
+			expect:      "((#n1 = :p1 AND (#n2 < :p2 OR #n2 > :p3)) OR #n3 = :p4)",
+			names:       map[string]string{"#n1": "Genre", "#n2": "Price", "#n3": "Year"},
+			values:      map[string]interface{}{":p1": "rock", ":p2": 1, ":p3": 10, ":p4": int64(2000)},
+		},
+		{
+			description: "LIKE translation",
+			criteria:    "Title LIKE 'Love%' AND Tags LIKE ? AND Genre NOT LIKE '%pop%'",
+			parameters:  []interface{}{"%rock%"},
+			expect:      "((begins_with(#n1, :p1) AND contains(#n2, :p2)) AND NOT (contains(#n3, :p3)))",
+			names:       map[string]string{"#n1": "Title", "#n2": "Tags", "#n3": "Genre"},
+			values:      map[string]interface{}{":p1": "Love", ":p2": "rock", ":p3": "pop"},
+		},
+		{
+			description: "IS NULL and IS NOT NULL",
+			criteria:    "Genre IS NULL AND NOT Price IS NOT NULL",
+			expect:      "((attribute_not_exists(#n1) OR attribute_type(#n1, :p1)) AND NOT ((attribute_exists(#n2) AND NOT attribute_type(#n2, :p2))))",
+			names:       map[string]string{"#n1": "Genre", "#n2": "Price"},
+			values:      map[string]interface{}{":p1": "NULL", ":p2": "NULL"},
+		},
+		{
+			description: "functions, between and nested path",
+			criteria:    "size(Tags) > 2 AND attribute_type(Price, 'N') AND Address.Zip BETWEEN ? AND ? AND Tags[0] = 'rock'",
+			parameters:  []interface{}{"10000", "20000"},
+			expect:      "(((size(#n1) > :p1 AND attribute_type(#n2, :p2)) AND #n3.#n4 BETWEEN :p3 AND :p4) AND #n1[0] = :p5)",
+			names:       map[string]string{"#n1": "Tags", "#n2": "Price", "#n3": "Address", "#n4": "Zip"},
+			values:      map[string]interface{}{":p1": int64(2), ":p2": "N", ":p3": "10000", ":p4": "20000", ":p5": "rock"},
+		},
+		{
+			description: "tuple IN and slice placeholder",
+			criteria:    "(Artist, SongTitle) IN ((?, ?), ('a', 'b')) AND Genre IN (?)",
+			parameters:  []interface{}{"x", "y", []string{"rock", "pop"}},
+			expect:      "(((#n1 = :p1 AND #n2 = :p2) OR (#n1 = :p3 AND #n2 = :p4)) AND #n3 IN (:p5, :p6))",
+			names:       map[string]string{"#n1": "Artist", "#n2": "SongTitle", "#n3": "Genre"},
+			values:      map[string]interface{}{":p1": "x", ":p2": "y", ":p3": "a", ":p4": "b", ":p5": "rock", ":p6": "pop"},
+		},
+		{
+			description: "LIKE any",
+			criteria:    "Title LIKE '%' AND Genre NOT LIKE '%%'",
+			expect:      "(attribute_exists(#n1) AND NOT (attribute_exists(#n2)))",
+			names:       map[string]string{"#n1": "Title", "#n2": "Genre"},
+			values:      map[string]interface{}{},
+		},
+		{
+			description: "unsupported single character wildcard",
+			criteria:    "Title LIKE 'Lov_%'",
+			hasError:    true,
+		},
+		{
+			description: "unsupported ends with",
+			criteria:    "Title LIKE '%Love'",
+			hasError:    true,
+		},
+		{
+			description: "missing parameter",
+			criteria:    "Title = ?",
+			hasError:    true,
+		},
+		{
+			description: "invalid syntax",
+			criteria:    "Title = 'a' AND",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		criteria, err := parseCriteria(useCase.criteria)
+		var actual string
+		builder := newExpressionBuilder(useCase.parameters)
+		if err == nil {
+			actual, err = builder.condition(criteria)
+		}
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+		var names = make(map[string]string)
+		for k, v := range builder.attributeNames() {
+			names[k] = *v
+		}
+		assert.EqualValues(t, useCase.names, names, useCase.description)
+		assert.EqualValues(t, useCase.values, builder.values, useCase.description)
+	}
+}
+
+func TestKeyCriteria(t *testing.T) {
+	var useCases = []struct {
+		description string
+		criteria    string
+		parameters  []interface{}
+		expect      map[string]interface{}
+		isKey       bool
+	}{
+		{
+			description: "composite key",
+			criteria:    "Artist = ? AND SongTitle = 'Title0'",
+			parameters:  []interface{}{"Artist0"},
+			expect:      map[string]interface{}{"Artist": "Artist0", "SongTitle": "Title0"},
+			isKey:       true,
+		},
+		{
+			description: "tuple IN",
+			criteria:    "(Artist, SongTitle) IN ((?, ?), (?, ?))",
+			parameters:  []interface{}{"a", "b", "c", "d"},
+			expect:      map[string]interface{}{"Artist,SongTitle": []interface{}{"a", "b", "c", "d"}},
+			isKey:       true,
+		},
+		{
+			description: "single value IN",
+			criteria:    "Artist IN (?)",
+			parameters:  []interface{}{"a"},
+			expect:      map[string]interface{}{"Artist": "a"},
+			isKey:       true,
+		},
+		{
+			description: "OR criteria",
+			criteria:    "Artist = ? OR Artist = ?",
+			parameters:  []interface{}{"a", "b"},
+		},
+		{
+			description: "non equality criteria",
+			criteria:    "Artist = ? AND Price > 3",
+			parameters:  []interface{}{"a"},
+		},
+	}
+	for _, useCase := range useCases {
+		criteria, err := parseCriteria(useCase.criteria)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		actual, ok, err := keyCriteria(criteria, useCase.parameters)
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.isKey, ok, useCase.description)
+		if useCase.isKey {
+			assert.EqualValues(t, useCase.expect, actual, useCase.description)
+		}
+	}
+}
+
+func TestSplitCriteria(t *testing.T) {
+	statement, criteria := splitCriteria("SELECT Artist FROM music WHERE (Artist, SongTitle) IN ((?, ?)) AND Title = 'a where b' GROUP BY Artist")
+	assert.EqualValues(t, "SELECT Artist FROM music GROUP BY Artist", statement)
+	assert.EqualValues(t, "(Artist, SongTitle) IN ((?, ?)) AND Title = 'a where b'", criteria)
+
+	statement, criteria = splitCriteria("SELECT Artist FROM music")
+	assert.EqualValues(t, "SELECT Artist FROM music", statement)
+	assert.EqualValues(t, "", criteria)
+}
+
+func TestProcessCriteria(t *testing.T) {
+	var useCases = []struct {
+		description string
+		values      map[string]interface{}
+		expect      []map[string]interface{}
+	}{
+		{
+			description: "single values",
+			values:      map[string]interface{}{"Artist": "a", "SongTitle": "t"},
+			expect:      []map[string]interface{}{{"Artist": "a", "SongTitle": "t"}},
+		},
+		{
+			description: "single column IN",
+			values:      map[string]interface{}{"Artist": []interface{}{"a", "b"}, "SongTitle": "t"},
+			expect: []map[string]interface{}{
+				{"Artist": "a", "SongTitle": "t"},
+				{"Artist": "b", "SongTitle": "t"},
+			},
+		},
+		{
+			description: "tuple IN",
+			values:      map[string]interface{}{"Artist,SongTitle": []interface{}{"a", "t1", "b", "t2"}},
+			expect: []map[string]interface{}{
+				{"Artist": "a", "SongTitle": "t1"},
+				{"Artist": "b", "SongTitle": "t2"},
+			},
+		},
+		{
+			description: "binary value",
+			values:      map[string]interface{}{"Id": []byte("ab")},
+			expect:      []map[string]interface{}{{"Id": []byte("ab")}},
+		},
+	}
+	for _, useCase := range useCases {
+		var actual = make([]map[string]interface{}, 0)
+		ok, err := processCriteria(useCase.values, func(values map[string]interface{}) (bool, error) {
+			var clone = make(map[string]interface{})
+			for k, v := range values {
+				clone[k] = v
+			}
+			actual = append(actual, clone)
+			return true, nil
+		})
+		assert.Nil(t, err, useCase.description)
+		assert.True(t, ok, useCase.description)
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"strings"
+	"unicode"
 )
 
 func getAttributeType(attributeValue *dynamodb.AttributeValue) string {
@@ -20,10 +21,28 @@ func getAttributeType(attributeValue *dynamodb.AttributeValue) string {
 	if attributeValue.B != nil {
 		return "B"
 	}
+	if attributeValue.L != nil {
+		return "L"
+	}
+	if attributeValue.M != nil {
+		return "M"
+	}
+	if attributeValue.SS != nil {
+		return "SS"
+	}
+	if attributeValue.NS != nil {
+		return "NS"
+	}
+	if attributeValue.BS != nil {
+		return "BS"
+	}
 	return "S"
 }
 
 func databaseAttributeType(databaseType string) (string, error) {
+	if index := strings.Index(databaseType, "("); index != -1 { //i.e. VARCHAR(255)
+		databaseType = strings.TrimSpace(databaseType[:index])
+	}
 	switch strings.ToLower(databaseType) {
 	case "int", "numeric", "decimal":
 		return "N", nil
@@ -34,3 +53,50 @@ func databaseAttributeType(databaseType string) (string, error) {
 	}
 	return "", fmt.Errorf("unsupported key type: %v", databaseType)
 }
+
+//splitQuoted splits text on separator runes outside of quoted strings, quotes are kept and empty parts are skipped
+func splitQuoted(text string, isSeparator func(r rune) bool) []string {
+	var result = make([]string, 0)
+	var quote rune
+	var current = strings.Builder{}
+	flush := func() {
+		if part := strings.TrimSpace(current.String()); part != "" {
+			result = append(result, part)
+		}
+		current.Reset()
+	}
+	for _, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case isSeparator(r):
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	return result
+}
+
+//splitFields splits statement into words, quoted strings are kept as one word
+func splitFields(text string) []string {
+	return splitQuoted(text, unicode.IsSpace)
+}
+
+//statementFragments returns statement words without trailing semicolon, optional TABLE keyword following the leading keyword is removed
+func statementFragments(SQL string) []string {
+	fragments := splitFields(strings.TrimSuffix(strings.TrimSpace(SQL), ";"))
+	if len(fragments) > 1 && strings.ToUpper(fragments[1]) == "TABLE" {
+		fragments = append(fragments[:1], fragments[2:]...)
+	}
+	return fragments
+}
+
+func unquote(text string) string {
+	return strings.Trim(text, "`\"'")
+}
@@ -0,0 +1,100 @@
+package dyndb
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/viant/toolbox"
+	"strings"
+)
+
+const (
+	//consistentReadKey config parameter enables strongly consistent reads
+	consistentReadKey = "consistentRead"
+
+	//consistentHint enables strongly consistent read for a query i.e. SELECT /*+ CONSISTENT */ * FROM music
+	consistentHint = "CONSISTENT"
+	//indexHint reads from secondary index i.e. SELECT /*+ INDEX(GenreIndex) */ * FROM music
+	indexHint = "INDEX"
+	//allowScanHint permits a statement to scan a table when scans are disabled i.e. DELETE /*+ ALLOW_SCAN */ FROM music
+	allowScanHint = "ALLOW_SCAN"
+)
+
+//hints represents optimizer style hints /*+ HINT HINT(arg) */ keyed by upper case hint name
+type hints map[string]string
+
+//Has returns true if hint is present
+func (h hints) Has(name string) bool {
+	_, ok := h[name]
+	return ok
+}
+
+//parseHints removes all /*+ ... */ comments from SQL and returns hints they define,
+//hint argument missing closing parenthesis takes the rest of the comment
+func parseHints(SQL string) (string, hints) {
+	var result = make(hints)
+	for {
+		begin := strings.Index(SQL, "/*+")
+		if begin == -1 {
+			return SQL, result
+		}
+		end := strings.Index(SQL[begin:], "*/")
+		if end == -1 {
+			return SQL, result
+		}
+		end += begin
+		body := SQL[begin+3 : end]
+		SQL = SQL[:begin] + " " + SQL[end+2:]
+		for len(body) > 0 {
+			body = strings.TrimLeft(body, " \t\n\r,")
+			if body == "" {
+				break
+			}
+			index := strings.IndexAny(body, " \t\n\r,(")
+			if index == -1 {
+				result[strings.ToUpper(body)] = ""
+				break
+			}
+			name := strings.ToUpper(body[:index])
+			body = body[index:]
+			argument := ""
+			if strings.HasPrefix(body, "(") {
+				closing := strings.Index(body, ")")
+				if closing == -1 {
+					result[name] = strings.TrimSpace(body[1:])
+					break
+				}
+				argument = strings.TrimSpace(body[1:closing])
+				body = body[closing+1:]
+			}
+			result[name] = argument
+		}
+	}
+}
+
+//readOptions represents read options controlled by config parameters and query hints
+type readOptions struct {
+	consistent bool
+	index      string
+}
+
+//readOptions returns read options for described table, consistent read configured with consistentRead does not apply to global secondary index,
+//it returns error if consistent read is requested with a hint on global secondary index
+func (m *manager) readOptions(description *dynamodb.TableDescription, queryHints hints) (*readOptions, error) {
+	result := &readOptions{
+		consistent: queryHints.Has(consistentHint) || toolbox.AsBoolean(m.Config().Get(consistentReadKey)),
+		index:      queryHints[indexHint],
+	}
+	if result.index == "" || !result.consistent {
+		return result, nil
+	}
+	for _, index := range description.GlobalSecondaryIndexes {
+		if index.IndexName == nil || *index.IndexName != result.index {
+			continue
+		}
+		if queryHints.Has(consistentHint) {
+			return nil, fmt.Errorf("consistent read is not supported on global secondary index: %v.%v", *description.TableName, result.index)
+		}
+		result.consistent = false
+	}
+	return result, nil
+}
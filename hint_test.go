@@ -0,0 +1,101 @@
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"testing"
+)
+
+func TestParseHints(t *testing.T) {
+	var useCases = []struct {
+		description string
+		SQL         string
+		expectSQL   string
+		expect      hints
+	}{
+		{
+			description: "no hints",
+			SQL:         "SELECT * FROM music",
+			expectSQL:   "SELECT * FROM music",
+			expect:      hints{},
+		},
+		{
+			description: "consistent hint",
+			SQL:         "SELECT /*+ CONSISTENT */ * FROM music",
+			expectSQL:   "SELECT   * FROM music",
+			expect:      hints{"CONSISTENT": ""},
+		},
+		{
+			description: "hint with argument",
+			SQL:         "SELECT /*+ consistent, INDEX( GenreIndex ) */ Artist FROM music",
+			expectSQL:   "SELECT   Artist FROM music",
+			expect:      hints{"CONSISTENT": "", "INDEX": "GenreIndex"},
+		},
+		{
+			description: "unterminated hint argument",
+			SQL:         "SELECT /*+ CONSISTENT INDEX(GenreIndex */ * FROM music",
+			expectSQL:   "SELECT   * FROM music",
+			expect:      hints{"CONSISTENT": "", "INDEX": "GenreIndex"},
+		},
+		{
+			description: "unterminated hint argument in string literal",
+			SQL:         "SELECT * FROM music WHERE Title = '/*+ x(' AND Artist = '*/'",
+			expectSQL:   "SELECT * FROM music WHERE Title = ' '",
+			expect:      hints{"X": "' AND Artist = '"},
+		},
+		{
+			description: "regular comment",
+			SQL:         "SELECT /* CONSISTENT */ * FROM music",
+			expectSQL:   "SELECT /* CONSISTENT */ * FROM music",
+			expect:      hints{},
+		},
+	}
+	for _, useCase := range useCases {
+		SQL, actual := parseHints(useCase.SQL)
+		assert.EqualValues(t, useCase.expectSQL, SQL, useCase.description)
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestManager_ReadOptions(t *testing.T) {
+	description := &dynamodb.TableDescription{
+		TableName:              aws.String("music"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndexDescription{{IndexName: aws.String("GenreIndex")}},
+		LocalSecondaryIndexes:  []*dynamodb.LocalSecondaryIndexDescription{{IndexName: aws.String("YearIndex")}},
+	}
+	var useCases = []struct {
+		description string
+		parameters  map[string]interface{}
+		hints       hints
+		expect      readOptions
+		hasError    bool
+	}{
+		{description: "default", hints: hints{}, expect: readOptions{}},
+		{description: "consistent hint", hints: hints{consistentHint: ""}, expect: readOptions{consistent: true}},
+		{description: "consistent config", parameters: map[string]interface{}{consistentReadKey: true}, hints: hints{}, expect: readOptions{consistent: true}},
+		{description: "consistent hint on local index", hints: hints{consistentHint: "", indexHint: "YearIndex"}, expect: readOptions{consistent: true, index: "YearIndex"}},
+		{description: "consistent hint on global index", hints: hints{consistentHint: "", indexHint: "GenreIndex"}, hasError: true},
+		{description: "consistent config on global index", parameters: map[string]interface{}{consistentReadKey: true}, hints: hints{indexHint: "GenreIndex"}, expect: readOptions{index: "GenreIndex"}},
+		{description: "consistent config on local index", parameters: map[string]interface{}{consistentReadKey: true}, hints: hints{indexHint: "YearIndex"}, expect: readOptions{consistent: true, index: "YearIndex"}},
+	}
+	for _, useCase := range useCases {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", useCase.parameters)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		datastoreManager, err := newManagerFactory().Create(config)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		options, err := datastoreManager.(*manager).readOptions(description, useCase.hints)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, useCase.expect, *options, useCase.description)
+		}
+	}
+}
@@ -6,128 +6,129 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/viant/dsc"
 	"github.com/viant/sqlparser"
 	"github.com/viant/toolbox"
 	"strings"
 )
 
+//maxBatchGetItems max number of keys supported by BatchGetItem
+const maxBatchGetItems = 100
+
 type manager struct {
 	*dsc.AbstractManager
+	tables *tableCache
 }
 
-func (m *manager) runInsert(db *dynamodb.DynamoDB, statement *dsc.DmlStatement, sqlParameters []interface{}) (err error) {
+func (m *manager) insertInput(statement *dsc.DmlStatement, sqlParameters []interface{}) (*dynamodb.PutItemInput, error) {
 	parameters := toolbox.NewSliceIterator(sqlParameters)
-	var record map[string]interface{}
-	if record, err = statement.ColumnValueMap(parameters); err != nil {
-		return err
+	record, err := statement.ColumnValueMap(parameters)
+	if err != nil {
+		return nil, err
 	}
-	attributeValues, err := dynamodbattribute.MarshalMap(record)
+	attributeValues, err := marshalMap(record)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	input := &dynamodb.PutItemInput{
+	return &dynamodb.PutItemInput{
 		Item:      attributeValues,
 		TableName: aws.String(statement.Table),
-	}
-	_, err = db.PutItem(input)
-	return err
+	}, nil
 }
 
-func (m *manager) runUpdate(db *dynamodb.DynamoDB, statement *dsc.DmlStatement, sqlParameters []interface{}) (err error) {
-	parameters := toolbox.NewSliceIterator(sqlParameters)
-	var record map[string]interface{}
-	if record, err = statement.ColumnValueMap(parameters); err != nil {
+func (m *manager) runInsert(ctx context.Context, db dynamodbiface.DynamoDBAPI, statement *dsc.DmlStatement, sqlParameters []interface{}, returned *returnedItems) (err error) {
+	input, err := m.insertInput(statement, sqlParameters)
+	if err != nil {
 		return err
 	}
-	if len(record) == 0 { //nothing to change
-		return nil
-	}
-	keyValues, err := getKeyCriteriaMap(statement.SQLCriteria, parameters)
+	input.ReturnValues = aws.String(returned.returnValues(nil, dynamodb.ReturnValueAllOld))
+	output, err := db.PutItemWithContext(ctx, input)
 	if err != nil {
 		return err
 	}
-	if len(keyValues) == 0 {
-		return fmt.Errorf("getCriteriaExpression was empty")
+	return returned.add(output.Attributes)
+}
+
+//updateInputs returns update item input for each existing item with key matching criteria or nil if there is nothing to change,
+//keys are expanded the same way as by runUpdate i.e. IN lists or tuples
+func (m *manager) updateInputs(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string, statement *dsc.DmlStatement, sqlParameters []interface{}) ([]*dynamodb.UpdateItemInput, error) {
+	record, err := statement.ColumnValueMap(toolbox.NewSliceIterator(sqlParameters))
+	if err != nil {
+		return nil, err
 	}
-	if statement.Criteria[0].Operator != "=" {
-		return fmt.Errorf("unsupported getCriteriaExpression operator %v", statement.SQLCriteria.Expression())
+	if len(record) == 0 { //nothing to change
+		return nil, nil
 	}
-	updateAttributes, err := dynamodbattribute.MarshalMap(record)
+	plan, err := m.planUpdate(db, SQL, statement, nil, sqlParameters)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if plan.Operation != updateItemOperation {
+		return nil, fmt.Errorf("unsupported update without key criteria: %v", SQL)
 	}
-	keyAttributes, err := dynamodbattribute.MarshalMap(keyValues)
+	keys, err := existingKeys(ctx, db, plan.Table, plan.keyNames, plan.keys)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	var result = make([]*dynamodb.UpdateItemInput, 0, len(keys))
+	for _, key := range keys {
+		expression, names, values, err := updateExpression(statement, record)
+		if err != nil {
+			return nil, err
+		}
+		names["#k"] = aws.String(plan.keyNames[0])
+		result = append(result, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(plan.Table),
+			Key:                       key,
+			UpdateExpression:          expression,
+			ConditionExpression:       aws.String("attribute_exists(#k)"),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		})
+	}
+	return result, nil
+}
 
-	input := &dynamodb.UpdateItemInput{
-		AttributeUpdates: make(map[string]*dynamodb.AttributeValueUpdate),
-		TableName:        aws.String(statement.Table),
-		Key:              keyAttributes,
-	}
-	for k, v := range updateAttributes {
-		input.AttributeUpdates[k] = &dynamodb.AttributeValueUpdate{
-			Action: aws.String("PUT"),
-			Value:  v,
+//updateExpression returns SET update expression with attribute names and values for updated columns
+func updateExpression(statement *dsc.DmlStatement, record map[string]interface{}) (*string, map[string]*string, map[string]*dynamodb.AttributeValue, error) {
+	var names = make(map[string]*string)
+	var values = make(map[string]*dynamodb.AttributeValue)
+	var assignments = make([]string, 0)
+	for i, column := range statement.Columns {
+		value, err := marshal(record[column.Name])
+		if err != nil {
+			return nil, nil, nil, err
 		}
+		name, placeholder := fmt.Sprintf("#u%d", i+1), fmt.Sprintf(":u%d", i+1)
+		names[name] = aws.String(column.Name)
+		values[placeholder] = value
+		assignments = append(assignments, name+" = "+placeholder)
 	}
-	_, err = db.UpdateItem(input)
-	return err
+	return aws.String("SET " + strings.Join(assignments, ", ")), names, values, nil
 }
 
-func (m *manager) runDelete(db *dynamodb.DynamoDB, statement *dsc.DmlStatement, sqlParameters []interface{}) (affected int, err error) {
+//deleteInput returns delete item input or nil if criteria has no key values
+func (m *manager) deleteInput(statement *dsc.DmlStatement, sqlParameters []interface{}) (*dynamodb.DeleteItemInput, error) {
 	parameters := toolbox.NewSliceIterator(sqlParameters)
 	keyValues, err := getKeyCriteriaMap(statement.SQLCriteria, parameters)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	if len(keyValues) == 0 {
-		return m.runDeleteAll(db, statement, sqlParameters)
+		return nil, nil
 	}
 	if statement.Criteria[0].Operator != "=" {
-		return 0, fmt.Errorf("unsupported getCriteriaExpression operator %v", statement.SQLCriteria.Expression())
-	}
-	keyAttributes, err := dynamodbattribute.MarshalMap(keyValues)
-	input := &dynamodb.DeleteItemInput{
-		TableName: aws.String(statement.Table),
-		Key:       keyAttributes,
-	}
-	_, err = db.DeleteItem(input)
-	return 1, err
-}
-
-// runDeleteAll - uses brute force scan and deletion one by one (testing only)
-func (m *manager) runDeleteAll(db *dynamodb.DynamoDB, statement *dsc.DmlStatement, sqlParameters []interface{}) (affected int, err error) {
-	output, err := db.Scan(&dynamodb.ScanInput{
-		TableName: aws.String(statement.Table),
-	})
-	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("unsupported getCriteriaExpression operator %v", statement.SQLCriteria.Expression())
 	}
-	dynamoDbDialect := &dialect{}
-	keys, err := dynamoDbDialect.getKeyAttributes(m, statement.Table)
+	keyAttributes, err := marshalMap(keyValues)
 	if err != nil {
-		return 0, err
-	}
-	affected = 0
-	for _, item := range output.Items {
-		keyAttributes := make(map[string]*dynamodb.AttributeValue)
-		for _, key := range keys {
-			keyAttributes[*key.AttributeName] = item[*key.AttributeName]
-		}
-		if _, err = db.DeleteItem(&dynamodb.DeleteItemInput{
-			Key:       keyAttributes,
-			TableName: aws.String(statement.Table),
-		}); err != nil {
-			return 0, err
-		}
-		affected++
-
+		return nil, err
 	}
-	return affected, nil
+	return &dynamodb.DeleteItemInput{
+		TableName: aws.String(statement.Table),
+		Key:       keyAttributes,
+	}, nil
 }
 
 func (m *manager) ExecuteOnConnection(connection dsc.Connection, sql string, sqlParameters []interface{}) (result sql.Result, err error) {
@@ -136,25 +137,47 @@ func (m *manager) ExecuteOnConnection(connection dsc.Connection, sql string, sql
 	if err != nil {
 		return nil, err
 	}
+	ctx := connectionContext(connection)
+	sqlParameters, returned, err := outParameter(sqlParameters)
+	if err != nil {
+		return nil, err
+	}
 	if strings.HasPrefix(strings.TrimSpace(strings.ToLower(sql)), "create") {
-		return m.createTableExecution(context.Background(), db, sql)
+		return m.createTableExecution(ctx, primaryClient(db), sql)
 	} else if strings.HasPrefix(strings.TrimSpace(strings.ToLower(sql)), "drop") {
-		return m.dropTableExecution(context.Background(), db, sql)
+		return m.dropTableExecution(ctx, primaryClient(db), sql)
+	} else if withoutHints, _ := parseHints(sql); hasKeywordPrefix(strings.TrimSpace(withoutHints), truncateKeyword) {
+		return m.truncateTableExecution(ctx, db, sql)
+	} else if hasKeywordPrefix(strings.TrimSpace(sql), alterKeyword) {
+		return m.alterTableExecution(ctx, primaryClient(db), sql)
+	} else if hasKeywordPrefix(strings.TrimSpace(sql), backupKeyword) {
+		return m.backupTableExecution(ctx, primaryClient(db), sql, returned)
+	} else if hasKeywordPrefix(strings.TrimSpace(sql), restoreKeyword) {
+		return m.restoreTableExecution(ctx, primaryClient(db), sql)
+	}
+	if statement, ok := m.asPartiQL(sql); ok {
+		affected, err := m.executePartiQL(ctx, db, statement, sqlParameters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute %v, %v", statement, err)
+		}
+		return dsc.NewSQLResult(int64(affected), 0), nil
 	}
 
+	withoutHints, queryHints := parseHints(sql)
 	parser := dsc.NewDmlParser()
-	statement, err := parser.Parse(sql)
+	statement, err := parser.Parse(strings.TrimSpace(withoutHints))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse %v due to %v", sql, err)
 	}
+	statement.Table = m.tableName(statement.Table)
 	var affectedRecords = 1
 	switch statement.Type {
 	case "INSERT":
-		err = m.runInsert(db, statement, sqlParameters)
+		err = m.runInsert(ctx, db, statement, sqlParameters, returned)
 	case "UPDATE":
-		err = m.runUpdate(db, statement, sqlParameters)
+		affectedRecords, err = m.runUpdate(ctx, db, strings.TrimSpace(withoutHints), statement, sqlParameters, queryHints, returned)
 	case "DELETE":
-		affectedRecords, err = m.runDelete(db, statement, sqlParameters)
+		affectedRecords, err = m.runDelete(ctx, db, strings.TrimSpace(withoutHints), statement, sqlParameters, queryHints, returned)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to modify %v, %v", statement.Table, err)
@@ -168,193 +191,134 @@ func (m *manager) ReadAllOnWithHandlerOnConnection(connection dsc.Connection, SQ
 	if err != nil {
 		return err
 	}
-	parser := dsc.NewQueryParser()
-	statement, err := parser.Parse(SQL)
-	if err != nil {
-		return fmt.Errorf("failed to parse statement %v, %v", SQL, err)
-	}
-
-	if statement.SQLCriteria != nil && len(statement.Criteria) > 0 {
-		ok, err := m.tryReadItem(db, statement, sqlParameters, readingHandler)
-		if ok {
-			return err
-		}
+	ctx := connectionContext(connection)
+	if trimmed := strings.TrimSpace(SQL); hasKeywordPrefix(trimmed, explainKeyword) {
+		return m.explain(db, strings.TrimSpace(trimmed[len(explainKeyword):]), sqlParameters, readingHandler)
 	}
-
-	sel, projection, mapped := normalizeExpr(statement)
-	input := &dynamodb.ScanInput{
-		TableName:                aws.String(statement.Table),
-		ProjectionExpression:     projection,
-		Select:                   sel,
-		ExpressionAttributeNames: mapped,
+	if statement, ok := m.asPartiQL(SQL); ok {
+		return m.readPartiQL(ctx, db, statement, sqlParameters, readingHandler)
 	}
-	parameters := toolbox.NewSliceIterator(sqlParameters)
-	criteria, criteriaAttributes, err := getCriteriaExpression(statement.SQLCriteria, parameters)
+	plan, err := m.planQuery(db, SQL, sqlParameters)
 	if err != nil {
 		return err
 	}
-	if len(criteriaAttributes) > 0 {
-		input.FilterExpression = criteria
-		input.ExpressionAttributeValues = criteriaAttributes
-	}
-
-	if sel != nil {
-		return m.handleAggregation(db, input, statement, readingHandler)
+	if err = m.checkScan(SQL, plan); err != nil {
+		return err
 	}
+	return m.readPlan(ctx, db, plan, readingHandler)
+}
 
-	var lastEvaluatedKey map[string]*dynamodb.AttributeValue
-	for {
-		output, err := db.Scan(input)
-		if err != nil {
-			return err
+//normalizeExpr returns COUNT select or projection expression with document paths, list elements project the whole list
+func normalizeExpr(statement *dsc.QueryStatement, builder *expressionBuilder) (*string, *string) {
+	if len(statement.Columns) > 0 {
+		if strings.HasPrefix(strings.ToLower(statement.Columns[0].Expression), "count") {
+			return aws.String("COUNT"), nil
 		}
-		if len(statement.Columns) == 0 && len(output.Items) > 0 {
-			for key := range output.Items[0] {
-				statement.Columns = append(statement.Columns, &dsc.SQLColumn{Name: key})
-			}
+	}
+	var result = make([]string, 0)
+	var projected = make(map[string]bool)
+	for _, name := range statement.ColumnNames() {
+		if position := strings.Index(name, "["); position != -1 {
+			name = name[:position]
 		}
-		if len(output.Items) == 0 {
-			return nil
-		}
-		for _, item := range output.Items {
-			scanner := dsc.NewSQLScanner(statement, m.Config(), nil)
-			scanner.Values = make(map[string]interface{})
-			if err := dynamodbattribute.UnmarshalMap(item, &scanner.Values); err != nil {
-				return err
-			}
-			toContinue, err := readingHandler(scanner)
-			if err != nil {
-				return err
-			}
-			if !toContinue {
-				break
-			}
+		if projected[name] {
+			continue
 		}
+		projected[name] = true
+		result = append(result, builder.path(name))
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return nil, aws.String(strings.Join(result, ","))
+}
 
-		lastEvaluatedKey = output.LastEvaluatedKey
-		if lastEvaluatedKey == nil {
-			return nil
-		}
+//getItem reads item by key, projected defines projection, attribute names and read consistency
+func (m *manager) getItem(ctx context.Context, db dynamodbiface.DynamoDBAPI, statement *dsc.QueryStatement, key map[string]*dynamodb.AttributeValue, projected *dynamodb.KeysAndAttributes, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
+	output, err := db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:                aws.String(statement.Table),
+		Key:                      key,
+		ProjectionExpression:     projected.ProjectionExpression,
+		ExpressionAttributeNames: projected.ExpressionAttributeNames,
+		ConsistentRead:           projected.ConsistentRead,
+	})
+	if err != nil || output.Item == nil {
+		return err
 	}
+	_, err = m.handleItem(statement, output.Item, readingHandler)
+	return err
 }
 
-func (m *manager) handleAggregation(db *dynamodb.DynamoDB, input *dynamodb.ScanInput, statement *dsc.QueryStatement, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
-	statement.Columns[0].Name = statement.Columns[0].Alias
-	var lastEvaluatedKey map[string]*dynamodb.AttributeValue
-	var count int
-	for {
-		output, err := db.Scan(input)
-		if err != nil {
-			return err
+//batchGetItems reads items by keys with BatchGetItem, it retries unprocessed keys
+func (m *manager) batchGetItems(ctx context.Context, db dynamodbiface.DynamoDBAPI, statement *dsc.QueryStatement, keys []map[string]*dynamodb.AttributeValue, projected *dynamodb.KeysAndAttributes, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
+	for i := 0; i < len(keys); i += maxBatchGetItems {
+		end := i + maxBatchGetItems
+		if end > len(keys) {
+			end = len(keys)
 		}
-		lastEvaluatedKey = output.LastEvaluatedKey
-		if output.Count != nil {
-			count += int(*output.Count)
+		requestItems := map[string]*dynamodb.KeysAndAttributes{
+			statement.Table: {
+				Keys:                     keys[i:end],
+				ProjectionExpression:     projected.ProjectionExpression,
+				ExpressionAttributeNames: projected.ExpressionAttributeNames,
+				ConsistentRead:           projected.ConsistentRead,
+			},
 		}
-		if lastEvaluatedKey == nil {
-			scanner := dsc.NewSQLScanner(statement, m.Config(), nil)
-			alias := statement.Columns[0].Alias
-			if alias == "" {
-				alias = statement.Columns[0].Expression
+		for len(requestItems) > 0 {
+			output, err := db.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return err
 			}
-			scanner.Values = map[string]interface{}{
-				alias: count,
+			for _, item := range output.Responses[statement.Table] {
+				toContinue, err := m.handleItem(statement, item, readingHandler)
+				if err != nil || !toContinue {
+					return err
+				}
 			}
-			_, err = readingHandler(scanner)
-			return err
+			requestItems = output.UnprocessedKeys
 		}
 	}
 	return nil
 }
 
-func normalizeExpr(statement *dsc.QueryStatement) (*string, *string, map[string]*string) {
-	var result = make([]string, 0)
-	var sel, proj *string
-	var mapped map[string]*string
-	columnNames := statement.ColumnNames()
-	if len(statement.Columns) > 0 {
-		if strings.HasPrefix(strings.ToLower(statement.Columns[0].Expression), "count") {
-			sel = aws.String("COUNT")
-		}
+//handleItem passes item to reading handler, document path and aliased columns values are surfaced under result column name
+func (m *manager) handleItem(statement *dsc.QueryStatement, item map[string]*dynamodb.AttributeValue, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) (bool, error) {
+	columns := resultColumns(statement)
+	scanner := dsc.NewSQLScanner(statement, m.Config(), columns)
+	scanner.Values = make(map[string]interface{})
+	if err := unmarshalMap(item, &scanner.Values); err != nil {
+		return false, err
 	}
-	if sel == nil {
-		for _, name := range columnNames {
-			switch name {
-			case "Date":
-				name = "#Date"
-				if len(mapped) == 0 {
-					mapped = make(map[string]*string)
-				}
-				mapped["#Date"] = aws.String("Date")
-			case "User":
-				name = "#User"
-				if len(mapped) == 0 {
-					mapped = make(map[string]*string)
-				}
-				mapped["#User"] = aws.String("User")
-			}
-			result = append(result, name)
+	for i, column := range statement.Columns {
+		if columns[i] == column.Name && !isDocumentPath(column.Name) {
+			continue
+		}
+		if value, ok := documentValue(scanner.Values, column.Name); ok {
+			scanner.Values[columns[i]] = value
 		}
 	}
-	if len(result) > 0 {
-		proj = aws.String(strings.Join(result, ","))
-	}
-	return sel, proj, mapped
+	return readingHandler(scanner)
 }
 
-func (m *manager) tryReadItem(db *dynamodb.DynamoDB, statement *dsc.QueryStatement, parameters []interface{}, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) (bool, error) {
-	valueMap, err := getKeyCriteriaMap(statement.SQLCriteria, toolbox.NewSliceIterator(parameters))
+func (m *manager) createTableExecution(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string) (sql.Result, error) {
+	SQL, options, err := createTableOptions(SQL)
 	if err != nil {
-		return false, err
-	}
-	dynamoDbDialect := &dialect{}
-	var indexedKeys = make(map[string]bool)
-	for _, key := range strings.Split(dynamoDbDialect.GetKeyName(m, "", statement.Table), ",") {
-		indexedKeys[key] = true
+		return nil, err
 	}
-	toContinue := true
-	return processCriteria(valueMap, func(keyValues map[string]interface{}) (bool, error) {
-		if !toContinue {
-			return true, nil
-		}
-		if len(indexedKeys) != len(keyValues) {
-			return false, nil
-		}
-		for criteriaKey := range keyValues {
-			if !indexedKeys[criteriaKey] {
-				return false, nil
-			}
-		}
-		keyAttributes, err := dynamodbattribute.MarshalMap(keyValues)
-		if err != nil {
-			return false, err
-		}
-		output, err := db.GetItem(&dynamodb.GetItemInput{
-			TableName: aws.String(statement.Table),
-			Key:       keyAttributes,
-		})
-		if output.Item == nil {
-			return true, nil
-		}
-		scanner := dsc.NewSQLScanner(statement, m.Config(), nil)
-		scanner.Values = make(map[string]interface{})
-		if err := dynamodbattribute.UnmarshalMap(output.Item, &scanner.Values); err != nil {
-			return true, err
-		}
-		toContinue, err = readingHandler(scanner)
-		if err != nil {
-			return true, err
-		}
-		return true, nil
-	})
-}
-
-func (m *manager) createTableExecution(ctx context.Context, db *dynamodb.DynamoDB, SQL string) (sql.Result, error) {
 	spec, err := sqlparser.ParseCreateTable(SQL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SQL: %w", err)
 	}
-	tableName := sqlparser.TableName(spec)
+	var tags []*dynamodb.Tag
+	for name, value := range options {
+		if name != tagsOption {
+			return nil, fmt.Errorf("unsupported create table option: %v", name)
+		}
+		if tags, err = parseTags(value); err != nil {
+			return nil, err
+		}
+	}
+	tableName := m.tableName(sqlparser.TableName(spec))
 	info := m.describeTable(db, tableName)
 	if spec.IfDoesExists {
 		if info != nil {
@@ -367,6 +331,9 @@ func (m *manager) createTableExecution(ctx context.Context, db *dynamodb.DynamoD
 		TableName:             &tableName,
 		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{ReadCapacityUnits: &capacityUnits, WriteCapacityUnits: &capacityUnits},
 	}
+	if input.Tags, err = tableTags(m.Config(), tags); err != nil {
+		return nil, err
+	}
 	for _, column := range spec.Columns {
 		attrType, err := databaseAttributeType(column.Type)
 		if err != nil {
@@ -386,19 +353,23 @@ func (m *manager) createTableExecution(ctx context.Context, db *dynamodb.DynamoD
 		}
 	}
 
-	if _, err = db.CreateTable(input); err != nil {
+	if _, err = db.CreateTableWithContext(ctx, input); err != nil {
+		return nil, err
+	}
+	err = waitForCreateCompletion(ctx, db, tableName)
+	m.tables.invalidate(tableName)
+	if err != nil {
 		return nil, err
 	}
-	waitForCreateCompletion(db, tableName)
 	return dsc.NewSQLResult(0, 0), nil
 }
 
-func (m *manager) dropTableExecution(ctx context.Context, db *dynamodb.DynamoDB, SQL string) (sql.Result, error) {
+func (m *manager) dropTableExecution(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string) (sql.Result, error) {
 	spec, err := sqlparser.ParseDropTable(SQL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SQL: %w", err)
 	}
-	tableName := sqlparser.TableName(spec)
+	tableName := m.tableName(sqlparser.TableName(spec))
 	info := m.describeTable(db, tableName)
 	if spec.IfExists {
 		if info == nil {
@@ -406,14 +377,18 @@ func (m *manager) dropTableExecution(ctx context.Context, db *dynamodb.DynamoDB,
 		}
 	}
 
-	if _, err = db.DeleteTable(&dynamodb.DeleteTableInput{TableName: &tableName}); err != nil {
+	if _, err = db.DeleteTableWithContext(ctx, &dynamodb.DeleteTableInput{TableName: &tableName}); err != nil {
+		return nil, err
+	}
+	err = waitForTableDeletion(ctx, db, tableName)
+	m.tables.invalidate(tableName)
+	if err != nil {
 		return nil, err
 	}
-	waitForTableDeletion(db, tableName)
 	return dsc.NewSQLResult(0, 0), nil
 }
 
-func (m *manager) describeTable(db *dynamodb.DynamoDB, tableName string) *dynamodb.TableDescription {
+func (m *manager) describeTable(db dynamodbiface.DynamoDBAPI, tableName string) *dynamodb.TableDescription {
 	var result *dynamodb.TableDescription
 	if output, _ := db.DescribeTable(&dynamodb.DescribeTableInput{
 		TableName: aws.String(tableName),
@@ -4,11 +4,13 @@ import (
 	"github.com/viant/dsc"
 )
 
-type managerFactory struct{}
+type managerFactory struct {
+	clientFactory ClientFactory
+}
 
 func (f *managerFactory) Create(config *dsc.Config) (dsc.Manager, error) {
-	var connectionProvider = newConnectionProvider(config)
-	manager := &manager{}
+	var connectionProvider = newConnectionProvider(config, f.clientFactory)
+	manager := &manager{tables: newTableCache()}
 	var self dsc.Manager = manager
 	super := dsc.NewAbstractManager(config, connectionProvider, self)
 	manager.AbstractManager = super
@@ -27,3 +29,9 @@ func newManagerFactory() dsc.ManagerFactory {
 	var result dsc.ManagerFactory = &managerFactory{}
 	return result
 }
+
+//NewManagerFactory returns manager factory creating connection clients with supplied factory i.e. to wrap, trace or mock DynamoDB
+func NewManagerFactory(clientFactory ClientFactory) dsc.ManagerFactory {
+	var result dsc.ManagerFactory = &managerFactory{clientFactory: clientFactory}
+	return result
+}
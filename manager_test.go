@@ -2,6 +2,7 @@ package dyndb_test
 
 import (
 	"fmt"
+	_ "github.com/adrianwit/dyndb/memdb"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/assertly"
 	"github.com/viant/dsc"
@@ -26,7 +27,7 @@ func TestManager(t *testing.T) {
 
 	//dsc.Logf = dsc.StdoutLogger
 	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
-		"endpoint": "localhost",
+		"endpoint": "memory://manager",
 		"region":   "us-west-1",
 		"key":      "dummy",
 		"secret":   "dummy",
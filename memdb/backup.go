@@ -0,0 +1,251 @@
+package memdb
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"sort"
+	"time"
+)
+
+//backup represents on-demand backup holding table snapshot
+type backup struct {
+	details *dynamodb.BackupDetails
+	source  *table
+}
+
+//snapshot returns table copy with its items, TTL, tags and point in time recovery settings are not copied as with DynamoDB restores
+func (t *table) snapshot() *table {
+	description := *t.description
+	description.Replicas, description.GlobalTableVersion = nil, nil
+	var items = make(map[string]item, len(t.items))
+	for key, value := range t.items {
+		items[key] = value
+	}
+	return &table{description: &description, attributeTypes: t.attributeTypes, primary: t.primary, indexes: t.indexes, items: items}
+}
+
+//restore creates ACTIVE table from snapshot
+func (s *Server) restore(name *string, source *table, summary *dynamodb.RestoreSummary) (*dynamodb.TableDescription, error) {
+	if name == nil || *name == "" {
+		return nil, validationError("target table name was empty")
+	}
+	if _, ok := s.tables[*name]; ok {
+		return nil, newError(errCodeTableExists, "table already exists: %v", *name)
+	}
+	result := source.snapshot()
+	now := time.Now()
+	result.description.TableName, result.description.TableId = name, name
+	result.description.TableArn = stringPointer(arnPrefix + *name)
+	result.description.TableStatus = stringPointer(dynamodb.TableStatusActive)
+	result.description.CreationDateTime = &now
+	result.description.RestoreSummary = summary
+	s.tables[*name] = result
+	return result.describe(), nil
+}
+
+//continuousBackups returns table continuous backups description
+func (t *table) continuousBackups() *dynamodb.ContinuousBackupsDescription {
+	recovery := &dynamodb.PointInTimeRecoveryDescription{PointInTimeRecoveryStatus: stringPointer(dynamodb.PointInTimeRecoveryStatusDisabled)}
+	if t.recovery != nil {
+		now := time.Now()
+		recovery = &dynamodb.PointInTimeRecoveryDescription{
+			PointInTimeRecoveryStatus:  t.recovery.PointInTimeRecoveryStatus,
+			EarliestRestorableDateTime: t.recovery.EarliestRestorableDateTime,
+			LatestRestorableDateTime:   &now,
+		}
+	}
+	return &dynamodb.ContinuousBackupsDescription{
+		ContinuousBackupsStatus:        stringPointer(dynamodb.ContinuousBackupsStatusEnabled),
+		PointInTimeRecoveryDescription: recovery,
+	}
+}
+
+//CreateBackup creates AVAILABLE backup with current table items
+func (s *Server) CreateBackup(input *dynamodb.CreateBackupInput) (*dynamodb.CreateBackupOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if input.BackupName == nil || *input.BackupName == "" {
+		return nil, validationError("backup name was empty")
+	}
+	source, ok := s.tables[stringValue(input.TableName)]
+	if !ok {
+		return nil, newError(errCodeTableNotFound, "table not found: %v", stringValue(input.TableName))
+	}
+	now := time.Now()
+	snapshot := source.snapshot()
+	var size int64
+	for _, candidate := range snapshot.items {
+		size += int64(itemSize(candidate))
+	}
+	details := &dynamodb.BackupDetails{
+		BackupArn:              stringPointer(fmt.Sprintf("%v/backup/%020d-%d", *source.description.TableArn, now.UnixNano(), len(s.backups))),
+		BackupName:             input.BackupName,
+		BackupStatus:           stringPointer(dynamodb.BackupStatusAvailable),
+		BackupType:             stringPointer(dynamodb.BackupTypeUser),
+		BackupCreationDateTime: &now,
+		BackupSizeBytes:        &size,
+	}
+	s.backups[*details.BackupArn] = &backup{details: details, source: snapshot}
+	return &dynamodb.CreateBackupOutput{BackupDetails: details}, nil
+}
+
+func (s *Server) backup(backupARN *string) (*backup, error) {
+	result, ok := s.backups[stringValue(backupARN)]
+	if !ok {
+		return nil, newError(errCodeBackupNotFound, "backup not found: %v", stringValue(backupARN))
+	}
+	return result, nil
+}
+
+//DescribeBackup returns backup details and source table details
+func (s *Server) DescribeBackup(input *dynamodb.DescribeBackupInput) (*dynamodb.DescribeBackupOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.backup(input.BackupArn)
+	if err != nil {
+		return nil, err
+	}
+	source := target.source.describe()
+	var throughput *dynamodb.ProvisionedThroughput
+	if source.ProvisionedThroughput != nil {
+		throughput = &dynamodb.ProvisionedThroughput{ReadCapacityUnits: source.ProvisionedThroughput.ReadCapacityUnits, WriteCapacityUnits: source.ProvisionedThroughput.WriteCapacityUnits}
+	}
+	var billingMode *string
+	if source.BillingModeSummary != nil {
+		billingMode = source.BillingModeSummary.BillingMode
+	}
+	return &dynamodb.DescribeBackupOutput{BackupDescription: &dynamodb.BackupDescription{
+		BackupDetails: target.details,
+		SourceTableDetails: &dynamodb.SourceTableDetails{
+			TableName:             source.TableName,
+			TableArn:              source.TableArn,
+			TableId:               source.TableId,
+			KeySchema:             source.KeySchema,
+			TableCreationDateTime: source.CreationDateTime,
+			ProvisionedThroughput: throughput,
+			BillingMode:           billingMode,
+			ItemCount:             source.ItemCount,
+			TableSizeBytes:        source.TableSizeBytes,
+		},
+	}}, nil
+}
+
+//ListBackups returns backups ordered by creation time, optionally filtered by table name, paging is not supported
+func (s *Server) ListBackups(input *dynamodb.ListBackupsInput) (*dynamodb.ListBackupsOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var summaries = make([]*dynamodb.BackupSummary, 0)
+	for _, candidate := range s.backups {
+		source := candidate.source.description
+		if input.TableName != nil && *input.TableName != *source.TableName {
+			continue
+		}
+		details := candidate.details
+		summaries = append(summaries, &dynamodb.BackupSummary{
+			BackupArn:              details.BackupArn,
+			BackupName:             details.BackupName,
+			BackupStatus:           details.BackupStatus,
+			BackupType:             details.BackupType,
+			BackupCreationDateTime: details.BackupCreationDateTime,
+			BackupSizeBytes:        details.BackupSizeBytes,
+			TableName:              source.TableName,
+			TableArn:               source.TableArn,
+			TableId:                source.TableId,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].BackupCreationDateTime.Before(*summaries[j].BackupCreationDateTime)
+	})
+	return &dynamodb.ListBackupsOutput{BackupSummaries: summaries}, nil
+}
+
+//RestoreTableFromBackup creates ACTIVE table with backup items
+func (s *Server) RestoreTableFromBackup(input *dynamodb.RestoreTableFromBackupInput) (*dynamodb.RestoreTableFromBackupOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	source, err := s.backup(input.BackupArn)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	description, err := s.restore(input.TargetTableName, source.source, &dynamodb.RestoreSummary{
+		SourceBackupArn:   input.BackupArn,
+		SourceTableArn:    source.source.description.TableArn,
+		RestoreDateTime:   &now,
+		RestoreInProgress: new(bool),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.RestoreTableFromBackupOutput{TableDescription: description}, nil
+}
+
+//RestoreTableToPointInTime creates ACTIVE table with source table items, items history is not kept so the latest items are restored for any valid time
+func (s *Server) RestoreTableToPointInTime(input *dynamodb.RestoreTableToPointInTimeInput) (*dynamodb.RestoreTableToPointInTimeOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var source *table
+	for _, candidate := range s.tables {
+		if *candidate.description.TableName == stringValue(input.SourceTableName) || *candidate.description.TableArn == stringValue(input.SourceTableArn) {
+			source = candidate
+		}
+	}
+	if source == nil {
+		return nil, newError(errCodeTableNotFound, "source table not found: %v", stringValue(input.SourceTableName)+stringValue(input.SourceTableArn))
+	}
+	if source.recovery == nil {
+		return nil, newError(errCodePITRUnavailable, "point in time recovery is not enabled for table %v", *source.description.TableName)
+	}
+	now := time.Now()
+	restoreTime := now
+	if input.UseLatestRestorableTime == nil || !*input.UseLatestRestorableTime {
+		if input.RestoreDateTime == nil {
+			return nil, validationError("either RestoreDateTime or UseLatestRestorableTime has to be specified")
+		}
+		restoreTime = *input.RestoreDateTime
+		if restoreTime.Before(*source.recovery.EarliestRestorableDateTime) || restoreTime.After(now) {
+			return nil, newError(errCodeRestoreTime, "restore time %v is outside of restorable window", restoreTime)
+		}
+	}
+	description, err := s.restore(input.TargetTableName, source, &dynamodb.RestoreSummary{
+		SourceTableArn:    source.description.TableArn,
+		RestoreDateTime:   &restoreTime,
+		RestoreInProgress: new(bool),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.RestoreTableToPointInTimeOutput{TableDescription: description}, nil
+}
+
+//UpdateContinuousBackups enables or disables point in time recovery, it is ENABLED or DISABLED immediately
+func (s *Server) UpdateContinuousBackups(input *dynamodb.UpdateContinuousBackupsInput) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, ok := s.tables[stringValue(input.TableName)]
+	if !ok {
+		return nil, newError(errCodeTableNotFound, "table not found: %v", stringValue(input.TableName))
+	}
+	specification := input.PointInTimeRecoverySpecification
+	if specification == nil || specification.PointInTimeRecoveryEnabled == nil {
+		return nil, validationError("PointInTimeRecoverySpecification was empty")
+	}
+	if !*specification.PointInTimeRecoveryEnabled {
+		target.recovery = nil
+	} else if target.recovery == nil {
+		now := time.Now()
+		target.recovery = &dynamodb.PointInTimeRecoveryDescription{PointInTimeRecoveryStatus: stringPointer(dynamodb.PointInTimeRecoveryStatusEnabled), EarliestRestorableDateTime: &now}
+	}
+	return &dynamodb.UpdateContinuousBackupsOutput{ContinuousBackupsDescription: target.continuousBackups()}, nil
+}
+
+//DescribeContinuousBackups returns point in time recovery status
+func (s *Server) DescribeContinuousBackups(input *dynamodb.DescribeContinuousBackupsInput) (*dynamodb.DescribeContinuousBackupsOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, ok := s.tables[stringValue(input.TableName)]
+	if !ok {
+		return nil, newError(errCodeTableNotFound, "table not found: %v", stringValue(input.TableName))
+	}
+	return &dynamodb.DescribeContinuousBackupsOutput{ContinuousBackupsDescription: target.continuousBackups()}, nil
+}
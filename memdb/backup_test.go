@@ -0,0 +1,77 @@
+package memdb_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestServer_Backup(t *testing.T) {
+	db := newClient(t)
+	_, err := db.CreateTable(&dynamodb.CreateTableInput{
+		TableName:            aws.String("users"),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{{AttributeName: aws.String("Id"), AttributeType: aws.String("S")}},
+		KeySchema:            []*dynamodb.KeySchemaElement{{AttributeName: aws.String("Id"), KeyType: aws.String("HASH")}},
+		BillingMode:          aws.String(dynamodb.BillingModePayPerRequest),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	put := func(id string) {
+		_, err := db.PutItem(&dynamodb.PutItemInput{TableName: aws.String("users"), Item: map[string]*dynamodb.AttributeValue{"Id": {S: aws.String(id)}}})
+		assert.Nil(t, err, id)
+	}
+	count := func(table string) int64 {
+		output, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(table)})
+		if !assert.Nil(t, err, table) {
+			return -1
+		}
+		return *output.Table.ItemCount
+	}
+	put("1")
+	created, err := db.CreateBackup(&dynamodb.CreateBackupInput{TableName: aws.String("users"), BackupName: aws.String("daily")})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, dynamodb.BackupStatusAvailable, *created.BackupDetails.BackupStatus)
+	put("2")
+
+	listed, err := db.ListBackups(&dynamodb.ListBackupsInput{TableName: aws.String("users")})
+	if assert.Nil(t, err) && assert.Len(t, listed.BackupSummaries, 1) {
+		assert.EqualValues(t, "daily", *listed.BackupSummaries[0].BackupName)
+		assert.EqualValues(t, *created.BackupDetails.BackupArn, *listed.BackupSummaries[0].BackupArn)
+	}
+	described, err := db.DescribeBackup(&dynamodb.DescribeBackupInput{BackupArn: created.BackupDetails.BackupArn})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, 1, *described.BackupDescription.SourceTableDetails.ItemCount)
+	}
+
+	restored, err := db.RestoreTableFromBackup(&dynamodb.RestoreTableFromBackupInput{TargetTableName: aws.String("users_restored"), BackupArn: created.BackupDetails.BackupArn})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, *created.BackupDetails.BackupArn, *restored.TableDescription.RestoreSummary.SourceBackupArn)
+	}
+	assert.EqualValues(t, 1, count("users_restored"))
+	_, err = db.RestoreTableFromBackup(&dynamodb.RestoreTableFromBackupInput{TargetTableName: aws.String("users_restored"), BackupArn: created.BackupDetails.BackupArn})
+	assert.EqualValues(t, "TableAlreadyExistsException", errorCode(err))
+	_, err = db.RestoreTableFromBackup(&dynamodb.RestoreTableFromBackupInput{TargetTableName: aws.String("users_missing"), BackupArn: aws.String("arn:aws:dynamodb:memory:000000000000:table/users/backup/missing")})
+	assert.EqualValues(t, "BackupNotFoundException", errorCode(err))
+
+	_, err = db.RestoreTableToPointInTime(&dynamodb.RestoreTableToPointInTimeInput{SourceTableName: aws.String("users"), TargetTableName: aws.String("users_pitr"), UseLatestRestorableTime: aws.Bool(true)})
+	assert.EqualValues(t, "PointInTimeRecoveryUnavailableException", errorCode(err))
+	_, err = db.UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String("users"),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	assert.Nil(t, err)
+	continuous, err := db.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{TableName: aws.String("users")})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, dynamodb.PointInTimeRecoveryStatusEnabled, *continuous.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus)
+	}
+	_, err = db.RestoreTableToPointInTime(&dynamodb.RestoreTableToPointInTimeInput{SourceTableName: aws.String("users"), TargetTableName: aws.String("users_pitr"), RestoreDateTime: aws.Time(time.Now().Add(-time.Hour))})
+	assert.EqualValues(t, "InvalidRestoreTimeException", errorCode(err))
+	_, err = db.RestoreTableToPointInTime(&dynamodb.RestoreTableToPointInTimeInput{SourceTableName: aws.String("users"), TargetTableName: aws.String("users_pitr"), UseLatestRestorableTime: aws.Bool(true)})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, count("users_pitr"))
+}
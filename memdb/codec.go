@@ -0,0 +1,212 @@
+package memdb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//marshalJSON encodes SDK shape as DynamoDB JSON 1.0 protocol body, nil fields are omitted and timestamps are encoded as epoch seconds
+func marshalJSON(value interface{}) ([]byte, error) {
+	return json.Marshal(encodeValue(reflect.ValueOf(value)))
+}
+
+//unmarshalJSON decodes DynamoDB JSON 1.0 protocol body into SDK shape pointed by target
+func unmarshalJSON(target interface{}, reader io.Reader) error {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	return decodeValue(reflect.ValueOf(target).Elem(), data)
+}
+
+//fieldName returns JSON name of SDK shape field
+func fieldName(field reflect.StructField) string {
+	if name := field.Tag.Get("locationName"); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return value.IsNil()
+	}
+	return false
+}
+
+func encodeValue(value reflect.Value) interface{} {
+	if !value.IsValid() || isNil(value) {
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return encodeValue(value.Elem())
+	case reflect.Struct:
+		if value.Type() == timeType {
+			seconds := float64(value.Interface().(time.Time).UnixNano()) / float64(time.Second)
+			return json.Number(strconv.FormatFloat(seconds, 'f', -1, 64))
+		}
+		var result = make(map[string]interface{})
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.PkgPath != "" || isNil(value.Field(i)) {
+				continue
+			}
+			result[fieldName(field)] = encodeValue(value.Field(i))
+		}
+		return result
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Bytes()
+		}
+		var result = make([]interface{}, value.Len())
+		for i := range result {
+			result[i] = encodeValue(value.Index(i))
+		}
+		return result
+	case reflect.Map:
+		var result = make(map[string]interface{}, value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			result[iterator.Key().String()] = encodeValue(iterator.Value())
+		}
+		return result
+	}
+	return value.Interface()
+}
+
+func decodeValue(target reflect.Value, data interface{}) error {
+	if data == nil {
+		return nil
+	}
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decodeValue(target.Elem(), data)
+	case reflect.Struct:
+		if target.Type() == timeType {
+			seconds, err := decodeNumber(data)
+			if err != nil {
+				return err
+			}
+			whole, fraction := math.Modf(seconds)
+			target.Set(reflect.ValueOf(time.Unix(int64(whole), int64(fraction*float64(time.Second))).UTC()))
+			return nil
+		}
+		fields, ok := data.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object for %v, but had %T", target.Type(), data)
+		}
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Type().Field(i)
+			value, ok := fields[fieldName(field)]
+			if field.PkgPath != "" || !ok {
+				continue
+			}
+			if err := decodeValue(target.Field(i), value); err != nil {
+				return fmt.Errorf("%v: %v", fieldName(field), err)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if target.Type().Elem().Kind() == reflect.Uint8 {
+			text, ok := data.(string)
+			if !ok {
+				return fmt.Errorf("expected base64 string, but had %T", data)
+			}
+			decoded, err := base64.StdEncoding.DecodeString(text)
+			if err != nil {
+				return err
+			}
+			target.SetBytes(decoded)
+			return nil
+		}
+		items, ok := data.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected array for %v, but had %T", target.Type(), data)
+		}
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Map:
+		entries, ok := data.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object for %v, but had %T", target.Type(), data)
+		}
+		result := reflect.MakeMapWithSize(target.Type(), len(entries))
+		for key, entry := range entries {
+			value := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeValue(value, entry); err != nil {
+				return fmt.Errorf("%v: %v", key, err)
+			}
+			result.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), value)
+		}
+		target.Set(result)
+		return nil
+	case reflect.String:
+		text, ok := data.(string)
+		if !ok {
+			return fmt.Errorf("expected string, but had %T", data)
+		}
+		target.SetString(text)
+		return nil
+	case reflect.Bool:
+		flag, ok := data.(bool)
+		if !ok {
+			return fmt.Errorf("expected boolean, but had %T", data)
+		}
+		target.SetBool(flag)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := data.(json.Number)
+		if !ok {
+			return fmt.Errorf("expected number, but had %T", data)
+		}
+		value, err := number.Int64()
+		if err != nil {
+			return err
+		}
+		target.SetInt(value)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		value, err := decodeNumber(data)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(value)
+		return nil
+	case reflect.Interface:
+		target.Set(reflect.ValueOf(data))
+		return nil
+	}
+	return fmt.Errorf("unsupported type: %v", target.Type())
+}
+
+func decodeNumber(data interface{}) (float64, error) {
+	number, ok := data.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("expected number, but had %T", data)
+	}
+	return number.Float64()
+}
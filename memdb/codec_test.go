@@ -0,0 +1,47 @@
+package memdb
+
+import (
+	"bytes"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCodec(t *testing.T) {
+	var useCases = []struct {
+		description string
+		value       interface{}
+		target      interface{}
+		expectJSON  string
+	}{
+		{
+			description: "attribute values",
+			value: &dynamodb.PutItemInput{TableName: aws.String("music"), Item: map[string]*dynamodb.AttributeValue{
+				"Artist": {S: aws.String("123")},
+				"Cover":  {B: []byte("png")},
+				"Tags":   {L: []*dynamodb.AttributeValue{{BOOL: aws.Bool(true)}, {NULL: aws.Bool(true)}}},
+			}},
+			target:     &dynamodb.PutItemInput{},
+			expectJSON: `{"Item":{"Artist":{"S":"123"},"Cover":{"B":"cG5n"},"Tags":{"L":[{"BOOL":true},{"NULL":true}]}},"TableName":"music"}`,
+		},
+		{
+			description: "epoch seconds timestamp",
+			value:       &dynamodb.ListBackupsInput{TimeRangeLowerBound: aws.Time(time.Unix(1500000000, 500000000).UTC()), Limit: aws.Int64(10)},
+			target:      &dynamodb.ListBackupsInput{},
+			expectJSON:  `{"Limit":10,"TimeRangeLowerBound":1500000000.5}`,
+		},
+	}
+	for _, useCase := range useCases {
+		body, err := marshalJSON(useCase.value)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expectJSON, string(body), useCase.description)
+		if !assert.Nil(t, unmarshalJSON(useCase.target, bytes.NewReader(body)), useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.value, useCase.target, useCase.description)
+	}
+}
@@ -0,0 +1,1054 @@
+package memdb
+
+import (
+	"bytes"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	tokenEOF = iota
+	tokenIdentifier
+	tokenName
+	tokenValue
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+//tokenize splits expression into identifiers, #name and :value placeholders, list indexes and symbols
+func tokenize(expression string) ([]token, error) {
+	var result = make([]token, 0)
+	runes := []rune(expression)
+	isWord := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	word := func(start int) int {
+		end := start
+		for end < len(runes) && isWord(runes[end]) {
+			end++
+		}
+		return end
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '#' || r == ':':
+			end := word(i + 1)
+			if end == i+1 {
+				return nil, validationError("invalid expression: %v, syntax error at %v", expression, i)
+			}
+			kind := tokenName
+			if r == ':' {
+				kind = tokenValue
+			}
+			result = append(result, token{kind: kind, text: string(runes[i:end])})
+			i = end
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && unicode.IsDigit(runes[end]) {
+				end++
+			}
+			result = append(result, token{kind: tokenNumber, text: string(runes[i:end])})
+			i = end
+		case isWord(r):
+			end := word(i)
+			result = append(result, token{kind: tokenIdentifier, text: string(runes[i:end])})
+			i = end
+		default:
+			if i+1 < len(runes) {
+				if pair := string(runes[i : i+2]); pair == "<>" || pair == "<=" || pair == ">=" {
+					result = append(result, token{kind: tokenSymbol, text: pair})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("=<>()[],.+-", r) {
+				return nil, validationError("invalid expression: %v, unexpected %q", expression, r)
+			}
+			result = append(result, token{kind: tokenSymbol, text: string(r)})
+			i++
+		}
+	}
+	return append(result, token{kind: tokenEOF}), nil
+}
+
+//pathElement represents document path attribute name or list index
+type pathElement struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+//path represents document path i.e. a.b[1].c
+type path []pathElement
+
+//resolve returns value at path or nil if path does not exist
+func (p path) resolve(values item) *dynamodb.AttributeValue {
+	current := &dynamodb.AttributeValue{M: values}
+	for _, element := range p {
+		if element.isIndex {
+			if current.L == nil || element.index >= len(current.L) {
+				return nil
+			}
+			current = current.L[element.index]
+		} else {
+			if current.M == nil {
+				return nil
+			}
+			current = current.M[element.name]
+		}
+		if current == nil {
+			return nil
+		}
+	}
+	return current
+}
+
+//set sets value at path, parent document has to exist, index past list end appends value
+func (p path) set(values item, value *dynamodb.AttributeValue) error {
+	if len(p) == 1 {
+		values[p[0].name] = value
+		return nil
+	}
+	parent := p[:len(p)-1].resolve(values)
+	last := p[len(p)-1]
+	switch {
+	case parent == nil:
+	case last.isIndex && parent.L != nil:
+		if last.index >= len(parent.L) {
+			parent.L = append(parent.L, value)
+		} else {
+			parent.L[last.index] = value
+		}
+		return nil
+	case !last.isIndex && parent.M != nil:
+		parent.M[last.name] = value
+		return nil
+	}
+	return validationError("the document path provided in the update expression is invalid for update: %v", p)
+}
+
+//remove removes value at path, list elements are shifted, missing path is ignored
+func (p path) remove(values item) {
+	if len(p) == 1 {
+		delete(values, p[0].name)
+		return
+	}
+	parent := p[:len(p)-1].resolve(values)
+	if parent == nil {
+		return
+	}
+	last := p[len(p)-1]
+	if last.isIndex {
+		if parent.L != nil && last.index < len(parent.L) {
+			parent.L = append(parent.L[:last.index], parent.L[last.index+1:]...)
+		}
+		return
+	}
+	if parent.M != nil {
+		delete(parent.M, last.name)
+	}
+}
+
+//String returns path text
+func (p path) String() string {
+	var result = new(bytes.Buffer)
+	for i, element := range p {
+		if element.isIndex {
+			result.WriteString("[" + strconv.Itoa(element.index) + "]")
+			continue
+		}
+		if i > 0 {
+			result.WriteString(".")
+		}
+		result.WriteString(element.name)
+	}
+	return result.String()
+}
+
+//operand represents expression operand
+type operand interface {
+	value(values item) (*dynamodb.AttributeValue, error)
+}
+
+func (p path) value(values item) (*dynamodb.AttributeValue, error) {
+	return p.resolve(values), nil
+}
+
+type literal struct {
+	attribute *dynamodb.AttributeValue
+}
+
+func (l *literal) value(values item) (*dynamodb.AttributeValue, error) {
+	return l.attribute, nil
+}
+
+type sizeFunction struct {
+	path path
+}
+
+func (f *sizeFunction) value(values item) (*dynamodb.AttributeValue, error) {
+	result, ok := size(f.path.resolve(values))
+	if !ok {
+		return nil, nil
+	}
+	return &dynamodb.AttributeValue{N: stringPointer(strconv.Itoa(result))}, nil
+}
+
+type ifNotExists struct {
+	path         path
+	defaultValue operand
+}
+
+func (f *ifNotExists) value(values item) (*dynamodb.AttributeValue, error) {
+	if result := f.path.resolve(values); result != nil {
+		return result, nil
+	}
+	return f.defaultValue.value(values)
+}
+
+type listAppend struct {
+	left, right operand
+}
+
+func (f *listAppend) value(values item) (*dynamodb.AttributeValue, error) {
+	left, err := f.left.value(values)
+	if err != nil {
+		return nil, err
+	}
+	right, err := f.right.value(values)
+	if err != nil {
+		return nil, err
+	}
+	if typeOf(left) != "L" || typeOf(right) != "L" {
+		return nil, validationError("an operand in the update expression has an incorrect data type: list_append expects lists")
+	}
+	var result = make([]*dynamodb.AttributeValue, 0, len(left.L)+len(right.L))
+	result = append(result, left.L...)
+	result = append(result, right.L...)
+	return &dynamodb.AttributeValue{L: result}, nil
+}
+
+type arithmetic struct {
+	operator    string
+	left, right operand
+}
+
+func (a *arithmetic) value(values item) (*dynamodb.AttributeValue, error) {
+	left, err := a.left.value(values)
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.right.value(values)
+	if err != nil {
+		return nil, err
+	}
+	if typeOf(left) != dynamodb.ScalarAttributeTypeN || typeOf(right) != dynamodb.ScalarAttributeTypeN {
+		return nil, validationError("an operand in the update expression has an incorrect data type: %v expects numbers", a.operator)
+	}
+	leftNumber, err := parseNumber(*left.N)
+	if err != nil {
+		return nil, err
+	}
+	rightNumber, err := parseNumber(*right.N)
+	if err != nil {
+		return nil, err
+	}
+	result := new(big.Rat)
+	if a.operator == "+" {
+		result.Add(leftNumber, rightNumber)
+	} else {
+		result.Sub(leftNumber, rightNumber)
+	}
+	return &dynamodb.AttributeValue{N: stringPointer(formatNumber(result))}, nil
+}
+
+//condition represents condition, filter or key condition expression
+type condition interface {
+	evaluate(values item) (bool, error)
+}
+
+type comparison struct {
+	operator    string
+	left, right operand
+}
+
+func (c *comparison) evaluate(values item) (bool, error) {
+	left, err := c.left.value(values)
+	if err != nil {
+		return false, err
+	}
+	right, err := c.right.value(values)
+	if err != nil {
+		return false, err
+	}
+	switch c.operator {
+	case "=":
+		return equal(left, right), nil
+	case "<>":
+		return !equal(left, right), nil
+	}
+	result, ok := compare(left, right)
+	if !ok {
+		return false, nil
+	}
+	switch c.operator {
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	}
+	return result >= 0, nil
+}
+
+type between struct {
+	operand   operand
+	low, high operand
+}
+
+func (b *between) evaluate(values item) (bool, error) {
+	candidate, err := b.operand.value(values)
+	if err != nil {
+		return false, err
+	}
+	low, err := b.low.value(values)
+	if err != nil {
+		return false, err
+	}
+	high, err := b.high.value(values)
+	if err != nil {
+		return false, err
+	}
+	lowResult, ok := compare(candidate, low)
+	if !ok {
+		return false, nil
+	}
+	highResult, ok := compare(candidate, high)
+	return ok && lowResult >= 0 && highResult <= 0, nil
+}
+
+type in struct {
+	operand    operand
+	candidates []operand
+}
+
+func (c *in) evaluate(values item) (bool, error) {
+	candidate, err := c.operand.value(values)
+	if err != nil || candidate == nil {
+		return false, err
+	}
+	for _, option := range c.candidates {
+		value, err := option.value(values)
+		if err != nil {
+			return false, err
+		}
+		if equal(candidate, value) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type logical struct {
+	operator    string
+	left, right condition
+}
+
+func (l *logical) evaluate(values item) (bool, error) {
+	left, err := l.left.evaluate(values)
+	if err != nil {
+		return false, err
+	}
+	if l.operator == "AND" && !left {
+		return false, nil
+	}
+	if l.operator == "OR" && left {
+		return true, nil
+	}
+	return l.right.evaluate(values)
+}
+
+type negation struct {
+	condition condition
+}
+
+func (n *negation) evaluate(values item) (bool, error) {
+	result, err := n.condition.evaluate(values)
+	return !result, err
+}
+
+type function struct {
+	name      string
+	path      path
+	arguments []operand
+}
+
+func (f *function) evaluate(values item) (bool, error) {
+	target := f.path.resolve(values)
+	switch f.name {
+	case "attribute_exists":
+		return target != nil, nil
+	case "attribute_not_exists":
+		return target == nil, nil
+	}
+	argument, err := f.arguments[0].value(values)
+	if err != nil || target == nil || argument == nil {
+		return false, err
+	}
+	switch f.name {
+	case "attribute_type":
+		if typeOf(argument) != dynamodb.ScalarAttributeTypeS {
+			return false, validationError("invalid attribute_type operand type: %v", typeOf(argument))
+		}
+		return typeOf(target) == *argument.S, nil
+	case "begins_with":
+		switch {
+		case typeOf(target) == dynamodb.ScalarAttributeTypeS && typeOf(argument) == dynamodb.ScalarAttributeTypeS:
+			return strings.HasPrefix(*target.S, *argument.S), nil
+		case typeOf(target) == dynamodb.ScalarAttributeTypeB && typeOf(argument) == dynamodb.ScalarAttributeTypeB:
+			return bytes.HasPrefix(target.B, argument.B), nil
+		}
+		return false, nil
+	}
+	switch typeOf(target) {
+	case dynamodb.ScalarAttributeTypeS:
+		return typeOf(argument) == dynamodb.ScalarAttributeTypeS && strings.Contains(*target.S, *argument.S), nil
+	case dynamodb.ScalarAttributeTypeB:
+		return typeOf(argument) == dynamodb.ScalarAttributeTypeB && bytes.Contains(target.B, argument.B), nil
+	case "SS", "NS", "BS":
+		return indexOf(setMembers(target), argument) != -1, nil
+	case "L":
+		return indexOf(target.L, argument) != -1, nil
+	}
+	return false, nil
+}
+
+//update represents parsed update expression
+type update struct {
+	set    []*assignment
+	remove []path
+	add    []*assignment
+	delete []*assignment
+}
+
+type assignment struct {
+	path  path
+	value operand
+}
+
+//apply applies update to an item copy, right hand side values are evaluated against original item, it returns updated top level attribute names
+func (u *update) apply(original item) (item, []string, error) {
+	var result = cloneItem(original)
+	if result == nil {
+		result = make(item)
+	}
+	var updated = make(map[string]bool)
+	var values = make([]*dynamodb.AttributeValue, len(u.set))
+	for i, action := range u.set {
+		value, err := action.value.value(original)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value == nil {
+			return nil, nil, validationError("the provided expression refers to an attribute that does not exist in the item")
+		}
+		values[i] = cloneValue(value)
+	}
+	for i, action := range u.set {
+		if err := action.path.set(result, values[i]); err != nil {
+			return nil, nil, err
+		}
+		updated[action.path[0].name] = true
+	}
+	for _, removed := range u.remove {
+		removed.remove(result)
+		updated[removed[0].name] = true
+	}
+	for _, action := range u.add {
+		value, err := action.value.value(original)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value, err = addValue(action.path.resolve(result), value); err != nil {
+			return nil, nil, err
+		}
+		if err := action.path.set(result, value); err != nil {
+			return nil, nil, err
+		}
+		updated[action.path[0].name] = true
+	}
+	for _, action := range u.delete {
+		value, err := action.value.value(original)
+		if err != nil {
+			return nil, nil, err
+		}
+		existing := action.path.resolve(result)
+		if existing == nil {
+			continue
+		}
+		if value, err = deleteValue(existing, value); err != nil {
+			return nil, nil, err
+		}
+		if value == nil {
+			action.path.remove(result)
+		} else if err := action.path.set(result, value); err != nil {
+			return nil, nil, err
+		}
+		updated[action.path[0].name] = true
+	}
+	var names = make([]string, 0, len(updated))
+	for name := range updated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return result, names, nil
+}
+
+//addValue returns ADD action result, numbers are summed and sets are merged
+func addValue(existing, value *dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+	valueType := typeOf(value)
+	if valueType != dynamodb.ScalarAttributeTypeN && valueType != "SS" && valueType != "NS" && valueType != "BS" {
+		return nil, validationError("incorrect operand type for operator or function; operator: ADD, operand type: %v", valueType)
+	}
+	if existing == nil {
+		return cloneValue(value), nil
+	}
+	if typeOf(existing) != valueType {
+		return nil, validationError("an operand in the update expression has an incorrect data type")
+	}
+	if valueType == dynamodb.ScalarAttributeTypeN {
+		return (&arithmetic{operator: "+", left: &literal{existing}, right: &literal{value}}).value(nil)
+	}
+	members := setMembers(existing)
+	for _, member := range setMembers(value) {
+		if indexOf(members, member) == -1 {
+			members = append(members, member)
+		}
+	}
+	return newSet(valueType, members), nil
+}
+
+//deleteValue returns DELETE action result or nil if set becomes empty
+func deleteValue(existing, value *dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+	valueType := typeOf(value)
+	if valueType != "SS" && valueType != "NS" && valueType != "BS" {
+		return nil, validationError("incorrect operand type for operator or function; operator: DELETE, operand type: %v", valueType)
+	}
+	if typeOf(existing) != valueType {
+		return nil, validationError("an operand in the update expression has an incorrect data type")
+	}
+	removed := setMembers(value)
+	var members = make([]*dynamodb.AttributeValue, 0)
+	for _, member := range setMembers(existing) {
+		if indexOf(removed, member) == -1 {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return newSet(valueType, members), nil
+}
+
+//projection returns item with projected paths only, selected list elements keep their order
+func projection(values item, paths []path) item {
+	type node struct {
+		value    *dynamodb.AttributeValue
+		fields   map[string]*node
+		elements map[int]*node
+	}
+	root := &node{fields: make(map[string]*node)}
+	for _, projected := range paths {
+		value := projected.resolve(values)
+		if value == nil {
+			continue
+		}
+		current := root
+		for _, element := range projected {
+			if current.value != nil {
+				break
+			}
+			var next *node
+			if element.isIndex {
+				if current.elements == nil {
+					current.elements = make(map[int]*node)
+				}
+				if next = current.elements[element.index]; next == nil {
+					next = &node{}
+					current.elements[element.index] = next
+				}
+			} else {
+				if current.fields == nil {
+					current.fields = make(map[string]*node)
+				}
+				if next = current.fields[element.name]; next == nil {
+					next = &node{}
+					current.fields[element.name] = next
+				}
+			}
+			current = next
+		}
+		if current.value == nil {
+			current.value = value
+			current.fields, current.elements = nil, nil
+		}
+	}
+	var build func(current *node) *dynamodb.AttributeValue
+	build = func(current *node) *dynamodb.AttributeValue {
+		if current.value != nil {
+			return cloneValue(current.value)
+		}
+		if current.fields != nil {
+			var result = make(item)
+			for name, field := range current.fields {
+				result[name] = build(field)
+			}
+			return &dynamodb.AttributeValue{M: result}
+		}
+		var indexes = make([]int, 0, len(current.elements))
+		for index := range current.elements {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		var result = make([]*dynamodb.AttributeValue, 0, len(indexes))
+		for _, index := range indexes {
+			result = append(result, build(current.elements[index]))
+		}
+		return &dynamodb.AttributeValue{L: result}
+	}
+	return build(root).M
+}
+
+//parser parses condition, update and projection expressions substituting #name and :value placeholders
+type parser struct {
+	expression string
+	tokens     []token
+	position   int
+	names      map[string]*string
+	values     map[string]*dynamodb.AttributeValue
+}
+
+func newParser(expression string, names map[string]*string, values map[string]*dynamodb.AttributeValue) (*parser, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{expression: expression, tokens: tokens, names: names, values: values}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	result := p.tokens[p.position]
+	if result.kind != tokenEOF {
+		p.position++
+	}
+	return result
+}
+
+//isKeyword returns true if current token is supplied keyword
+func (p *parser) isKeyword(keyword string) bool {
+	current := p.peek()
+	return current.kind == tokenIdentifier && strings.EqualFold(current.text, keyword)
+}
+
+func (p *parser) isSymbol(symbol string) bool {
+	current := p.peek()
+	return current.kind == tokenSymbol && current.text == symbol
+}
+
+func (p *parser) expect(symbol string) error {
+	if !p.isSymbol(symbol) {
+		return p.syntaxError()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) syntaxError() error {
+	current := p.peek()
+	if current.kind == tokenEOF {
+		return validationError("invalid expression: %v, unexpected end of expression", p.expression)
+	}
+	return validationError("invalid expression: %v, syntax error near %v", p.expression, current.text)
+}
+
+func (p *parser) done() error {
+	if p.peek().kind != tokenEOF {
+		return p.syntaxError()
+	}
+	return nil
+}
+
+//parseCondition parses condition expression
+func parseCondition(expression string, names map[string]*string, values map[string]*dynamodb.AttributeValue) (condition, error) {
+	p, err := newParser(expression, names, values)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.condition()
+	if err != nil {
+		return nil, err
+	}
+	return result, p.done()
+}
+
+//parseUpdate parses update expression
+func parseUpdate(expression string, names map[string]*string, values map[string]*dynamodb.AttributeValue) (*update, error) {
+	p, err := newParser(expression, names, values)
+	if err != nil {
+		return nil, err
+	}
+	result := &update{}
+	for p.peek().kind != tokenEOF {
+		clause := strings.ToUpper(p.next().text)
+		for {
+			target, err := p.path()
+			if err != nil {
+				return nil, err
+			}
+			switch clause {
+			case "SET":
+				if err = p.expect("="); err != nil {
+					return nil, err
+				}
+				value, err := p.setValue()
+				if err != nil {
+					return nil, err
+				}
+				result.set = append(result.set, &assignment{path: target, value: value})
+			case "REMOVE":
+				result.remove = append(result.remove, target)
+			case "ADD", "DELETE":
+				value, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				if clause == "ADD" {
+					result.add = append(result.add, &assignment{path: target, value: value})
+				} else {
+					result.delete = append(result.delete, &assignment{path: target, value: value})
+				}
+			default:
+				return nil, validationError("invalid update expression: %v, unsupported clause %v", expression, clause)
+			}
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if len(result.set)+len(result.remove)+len(result.add)+len(result.delete) == 0 {
+		return nil, validationError("invalid update expression: %v", expression)
+	}
+	return result, nil
+}
+
+//parseProjection parses projection expression
+func parseProjection(expression string, names map[string]*string) ([]path, error) {
+	p, err := newParser(expression, names, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]path, 0)
+	for {
+		projected, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, projected)
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return result, p.done()
+}
+
+func (p *parser) condition() (condition, error) {
+	left, err := p.conjunction()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.conjunction()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{operator: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) conjunction() (condition, error) {
+	left, err := p.negation()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.negation()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{operator: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) negation() (condition, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		result, err := p.negation()
+		if err != nil {
+			return nil, err
+		}
+		return &negation{condition: result}, nil
+	}
+	return p.predicate()
+}
+
+func (p *parser) predicate() (condition, error) {
+	if p.isSymbol("(") {
+		p.next()
+		result, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		return result, p.expect(")")
+	}
+	if current := p.peek(); current.kind == tokenIdentifier && p.tokens[p.position+1].text == "(" {
+		switch name := strings.ToLower(current.text); name {
+		case "attribute_exists", "attribute_not_exists", "attribute_type", "begins_with", "contains":
+			p.next()
+			p.next()
+			target, err := p.path()
+			if err != nil {
+				return nil, err
+			}
+			result := &function{name: name, path: target}
+			if name != "attribute_exists" && name != "attribute_not_exists" {
+				if err = p.expect(","); err != nil {
+					return nil, err
+				}
+				argument, err := p.operand()
+				if err != nil {
+					return nil, err
+				}
+				result.arguments = append(result.arguments, argument)
+			}
+			return result, p.expect(")")
+		}
+	}
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isKeyword("BETWEEN"):
+		p.next()
+		low, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("AND") {
+			return nil, p.syntaxError()
+		}
+		p.next()
+		high, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return &between{operand: left, low: low, high: high}, nil
+	case p.isKeyword("IN"):
+		p.next()
+		if err = p.expect("("); err != nil {
+			return nil, err
+		}
+		result := &in{operand: left}
+		for {
+			candidate, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			result.candidates = append(result.candidates, candidate)
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+		return result, p.expect(")")
+	}
+	current := p.peek()
+	switch current.text {
+	case "=", "<>", "<", "<=", ">", ">=":
+		if current.kind != tokenSymbol {
+			break
+		}
+		p.next()
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return &comparison{operator: current.text, left: left, right: right}, nil
+	}
+	return nil, p.syntaxError()
+}
+
+//operand parses condition operand: path, value or size function
+func (p *parser) operand() (operand, error) {
+	if current := p.peek(); current.kind == tokenIdentifier && strings.EqualFold(current.text, "size") && p.tokens[p.position+1].text == "(" {
+		p.next()
+		p.next()
+		target, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+		return &sizeFunction{path: target}, p.expect(")")
+	}
+	if p.peek().kind == tokenValue {
+		return p.value()
+	}
+	return p.path()
+}
+
+//setValue parses SET action value with optional + or - operator
+func (p *parser) setValue() (operand, error) {
+	left, err := p.setOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.isSymbol("+") || p.isSymbol("-") {
+		operator := p.next().text
+		right, err := p.setOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &arithmetic{operator: operator, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) setOperand() (operand, error) {
+	current := p.peek()
+	if current.kind == tokenIdentifier && p.tokens[p.position+1].text == "(" {
+		switch strings.ToLower(current.text) {
+		case "if_not_exists":
+			p.next()
+			p.next()
+			target, err := p.path()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(","); err != nil {
+				return nil, err
+			}
+			defaultValue, err := p.setValue()
+			if err != nil {
+				return nil, err
+			}
+			return &ifNotExists{path: target, defaultValue: defaultValue}, p.expect(")")
+		case "list_append":
+			p.next()
+			p.next()
+			left, err := p.setValue()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(","); err != nil {
+				return nil, err
+			}
+			right, err := p.setValue()
+			if err != nil {
+				return nil, err
+			}
+			return &listAppend{left: left, right: right}, p.expect(")")
+		}
+	}
+	if current.kind == tokenValue {
+		return p.value()
+	}
+	return p.path()
+}
+
+//value parses :value placeholder
+func (p *parser) value() (operand, error) {
+	if p.peek().kind != tokenValue {
+		return nil, p.syntaxError()
+	}
+	current := p.next()
+	value, ok := p.values[current.text]
+	if !ok {
+		return nil, validationError("invalid expression: %v, an expression attribute value used in expression is not defined: %v", p.expression, current.text)
+	}
+	if err := validateValue(value); err != nil {
+		return nil, err
+	}
+	return &literal{attribute: value}, nil
+}
+
+//path parses document path
+func (p *parser) path() (path, error) {
+	var result = make(path, 0)
+	element, err := p.pathName()
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, element)
+	for {
+		switch {
+		case p.isSymbol("."):
+			p.next()
+			if element, err = p.pathName(); err != nil {
+				return nil, err
+			}
+			result = append(result, element)
+		case p.isSymbol("["):
+			p.next()
+			if p.peek().kind != tokenNumber {
+				return nil, p.syntaxError()
+			}
+			index := p.next()
+			position, _ := strconv.Atoi(index.text)
+			result = append(result, pathElement{index: position, isIndex: true})
+			if err = p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return result, nil
+		}
+	}
+}
+
+func (p *parser) pathName() (pathElement, error) {
+	current := p.peek()
+	switch current.kind {
+	case tokenIdentifier:
+		p.next()
+		return pathElement{name: current.text}, nil
+	case tokenName:
+		p.next()
+		name, ok := p.names[current.text]
+		if !ok || name == nil {
+			return pathElement{}, validationError("invalid expression: %v, an expression attribute name used in expression is not defined: %v", p.expression, current.text)
+		}
+		return pathElement{name: *name}, nil
+	}
+	return pathElement{}, p.syntaxError()
+}
@@ -0,0 +1,198 @@
+package memdb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func marshalItem(t *testing.T, source map[string]interface{}) item {
+	result, err := dynamodbattribute.MarshalMap(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestParseCondition(t *testing.T) {
+	values := marshalItem(t, map[string]interface{}{
+		"Artist": "Artist0",
+		"Price":  2.5,
+		"Tags":   []string{"rock", "pop"},
+		"Address": map[string]interface{}{
+			"Zip": "10001",
+		},
+	})
+	var useCases = []struct {
+		description string
+		expression  string
+		names       map[string]string
+		values      map[string]interface{}
+		expect      bool
+		hasError    bool
+	}{
+		{
+			description: "equality with placeholders",
+			expression:  "#a = :a",
+			names:       map[string]string{"#a": "Artist"},
+			values:      map[string]interface{}{":a": "Artist0"},
+			expect:      true,
+		},
+		{
+			description: "number comparison is numeric",
+			expression:  "Price > :p AND Price <= :q",
+			values:      map[string]interface{}{":p": 2, ":q": 2.50},
+			expect:      true,
+		},
+		{
+			description: "not equal on missing attribute",
+			expression:  "Genre <> :g",
+			values:      map[string]interface{}{":g": "rock"},
+			expect:      true,
+		},
+		{
+			description: "OR precedence with NOT",
+			expression:  "NOT Price = :p OR Artist IN (:a, :b) AND attribute_not_exists(Genre)",
+			values:      map[string]interface{}{":p": 2.5, ":a": "x", ":b": "Artist0"},
+			expect:      true,
+		},
+		{
+			description: "functions and nested path",
+			expression:  "begins_with(Address.Zip, :z) AND contains(Tags, :t) AND size(Tags[1]) = :s AND attribute_type(Price, :n)",
+			values:      map[string]interface{}{":z": "100", ":t": "pop", ":s": 3, ":n": "N"},
+			expect:      true,
+		},
+		{
+			description: "between",
+			expression:  "Price BETWEEN :low AND :high",
+			values:      map[string]interface{}{":low": 3, ":high": 4},
+			expect:      false,
+		},
+		{
+			description: "undefined value",
+			expression:  "Artist = :missing",
+			hasError:    true,
+		},
+		{
+			description: "syntax error",
+			expression:  "Artist = = :a",
+			values:      map[string]interface{}{":a": "Artist0"},
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		expressionValues := marshalItem(t, useCase.values)
+		parsed, err := parseCondition(useCase.expression, aws.StringMap(useCase.names), expressionValues)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		actual, err := parsed.evaluate(values)
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestParseUpdate(t *testing.T) {
+	var useCases = []struct {
+		description string
+		expression  string
+		values      map[string]interface{}
+		sets        map[string][]string
+		expect      map[string]interface{}
+		updated     []string
+		hasError    bool
+	}{
+		{
+			description: "set arithmetic and if_not_exists",
+			expression:  "SET Price = Price + :p, Plays = if_not_exists(Plays, :zero) - :one",
+			values:      map[string]interface{}{":p": 0.1, ":zero": 0, ":one": 1},
+			expect:      map[string]interface{}{"Artist": "Artist0", "Price": 2.6, "Plays": -1, "Tags": []interface{}{"rock"}, "Genres": []string{"rock", "pop"}},
+			updated:     []string{"Plays", "Price"},
+		},
+		{
+			description: "list append, remove, add and delete",
+			expression:  "SET Tags = list_append(Tags, :tags) REMOVE Price ADD Genres :add, Plays :one DELETE Genres :delete",
+			values:      map[string]interface{}{":tags": []interface{}{"pop"}, ":one": 1},
+			sets:        map[string][]string{":add": {"jazz"}, ":delete": {"rock"}},
+			expect:      map[string]interface{}{"Artist": "Artist0", "Plays": 1, "Tags": []interface{}{"rock", "pop"}, "Genres": []string{"pop", "jazz"}},
+			updated:     []string{"Genres", "Plays", "Price", "Tags"},
+		},
+		{
+			description: "arithmetic on string",
+			expression:  "SET Artist = Artist + :one",
+			values:      map[string]interface{}{":one": 1},
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		original := marshalItem(t, map[string]interface{}{"Artist": "Artist0", "Price": 2.5, "Tags": []interface{}{"rock"}})
+		original["Genres"] = &dynamodb.AttributeValue{SS: aws.StringSlice([]string{"rock", "pop"})}
+		expressionValues := marshalItem(t, useCase.values)
+		for name, members := range useCase.sets {
+			expressionValues[name] = &dynamodb.AttributeValue{SS: aws.StringSlice(members)}
+		}
+		parsed, err := parseUpdate(useCase.expression, nil, expressionValues)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		actual, updated, err := parsed.apply(original)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		expect := marshalItem(t, useCase.expect)
+		if sets, ok := useCase.expect["Genres"].([]string); ok {
+			expect["Genres"] = &dynamodb.AttributeValue{SS: aws.StringSlice(sets)}
+		}
+		assert.True(t, equal(&dynamodb.AttributeValue{M: expect}, &dynamodb.AttributeValue{M: actual}), useCase.description)
+		assert.EqualValues(t, useCase.updated, updated, useCase.description)
+		assert.EqualValues(t, "2.5", *original["Price"].N, useCase.description)
+	}
+}
+
+func TestProjection(t *testing.T) {
+	values := marshalItem(t, map[string]interface{}{
+		"Artist": "Artist0",
+		"Tags":   []interface{}{"a", "b", "c"},
+		"Address": map[string]interface{}{
+			"Zip":  "10001",
+			"City": "NYC",
+		},
+	})
+	paths, err := parseProjection("Artist, Tags[2], Tags[0], #a.Zip, Missing", map[string]*string{"#a": aws.String("Address")})
+	if !assert.Nil(t, err) {
+		return
+	}
+	expect := marshalItem(t, map[string]interface{}{
+		"Artist":  "Artist0",
+		"Tags":    []interface{}{"a", "c"},
+		"Address": map[string]interface{}{"Zip": "10001"},
+	})
+	assert.True(t, equal(&dynamodb.AttributeValue{M: expect}, &dynamodb.AttributeValue{M: projection(values, paths)}))
+}
+
+func TestFormatNumber(t *testing.T) {
+	var useCases = []struct {
+		input  string
+		expect string
+	}{
+		{input: "1.0", expect: "1"},
+		{input: "1e2", expect: "100"},
+		{input: "0.10", expect: "0.1"},
+		{input: "-2.50", expect: "-2.5"},
+	}
+	for _, useCase := range useCases {
+		actual, err := normalizeNumber(useCase.input)
+		assert.Nil(t, err, useCase.input)
+		assert.EqualValues(t, useCase.expect, actual, useCase.input)
+	}
+}
@@ -0,0 +1,418 @@
+package memdb
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const (
+	maxBatchGetKeys    = 100
+	maxBatchWriteItems = 25
+	maxTransactItems   = 100
+)
+
+//change represents item change computed before it is applied, nil item removes stored item
+type change struct {
+	table *table
+	key   string
+	old   item
+	item  item
+}
+
+func (c *change) apply() {
+	if c.item == nil {
+		delete(c.table.items, c.key)
+		return
+	}
+	c.table.items[c.key] = c.item
+}
+
+//checkCondition evaluates condition expression against existing item or empty item
+func checkCondition(expression *string, names map[string]*string, values map[string]*dynamodb.AttributeValue, existing item) error {
+	if expression == nil || *expression == "" {
+		return nil
+	}
+	parsed, err := parseCondition(*expression, names, values)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		existing = make(item)
+	}
+	matched, err := parsed.evaluate(existing)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return newError(errCodeConditionFailed, "the conditional request failed")
+	}
+	return nil
+}
+
+//putChange returns change replacing item
+func (s *Server) putChange(tableName *string, values item, expression *string, names map[string]*string, expressionValues map[string]*dynamodb.AttributeValue) (*change, error) {
+	target, err := s.table(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, validationError("item was empty")
+	}
+	for _, value := range values {
+		if err = validateValue(value); err != nil {
+			return nil, err
+		}
+	}
+	key, err := target.keyOf(values)
+	if err != nil {
+		return nil, err
+	}
+	if err = target.checkIndexKeys(values); err != nil {
+		return nil, err
+	}
+	existing := target.items[key]
+	if err = checkCondition(expression, names, expressionValues, existing); err != nil {
+		return nil, err
+	}
+	return &change{table: target, key: key, old: existing, item: cloneItem(values)}, nil
+}
+
+//deleteChange returns change removing item
+func (s *Server) deleteChange(tableName *string, keyValues item, expression *string, names map[string]*string, expressionValues map[string]*dynamodb.AttributeValue) (*change, error) {
+	target, err := s.table(tableName)
+	if err != nil {
+		return nil, err
+	}
+	key, err := target.checkKey(keyValues)
+	if err != nil {
+		return nil, err
+	}
+	existing := target.items[key]
+	if err = checkCondition(expression, names, expressionValues, existing); err != nil {
+		return nil, err
+	}
+	return &change{table: target, key: key, old: existing}, nil
+}
+
+//updateChange returns change updating or creating item and updated top level attribute names
+func (s *Server) updateChange(tableName *string, keyValues item, updateExpression, conditionExpression *string, names map[string]*string, values map[string]*dynamodb.AttributeValue) (*change, []string, error) {
+	target, err := s.table(tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := target.checkKey(keyValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	existing := target.items[key]
+	if err = checkCondition(conditionExpression, names, values, existing); err != nil {
+		return nil, nil, err
+	}
+	var original = existing
+	if original == nil {
+		original = cloneItem(keyValues)
+	}
+	if updateExpression == nil || *updateExpression == "" {
+		return &change{table: target, key: key, old: existing, item: cloneItem(original)}, nil, nil
+	}
+	parsed, err := parseUpdate(*updateExpression, names, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, updatedNames, err := parsed.apply(original)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, name := range updatedNames {
+		for _, keyName := range target.primary.keyNames() {
+			if name == keyName {
+				return nil, nil, validationError("one or more parameter values were invalid: cannot update attribute %v, this attribute is part of the key", name)
+			}
+		}
+	}
+	for _, value := range updated {
+		if err = validateValue(value); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err = target.checkIndexKeys(updated); err != nil {
+		return nil, nil, err
+	}
+	return &change{table: target, key: key, old: existing, item: updated}, updatedNames, nil
+}
+
+//oldImage returns ALL_OLD image or nil
+func oldImage(returnValues *string, old item) item {
+	if returnValues == nil || *returnValues != dynamodb.ReturnValueAllOld {
+		return nil
+	}
+	return old
+}
+
+//checkReturnValues checks that ReturnValues is supported by operation
+func checkReturnValues(returnValues *string, supported ...string) error {
+	if returnValues == nil {
+		return nil
+	}
+	for _, candidate := range append(supported, dynamodb.ReturnValueNone) {
+		if *returnValues == candidate {
+			return nil
+		}
+	}
+	return validationError("return values set to invalid value: %v", *returnValues)
+}
+
+//GetItem returns item by key
+func (s *Server) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	found, err := s.getItem(input.TableName, input.Key, input.ProjectionExpression, input.ExpressionAttributeNames)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.GetItemOutput{Item: found}, nil
+}
+
+func (s *Server) getItem(tableName *string, keyValues item, projectionExpression *string, names map[string]*string) (item, error) {
+	target, err := s.table(tableName)
+	if err != nil {
+		return nil, err
+	}
+	key, err := target.checkKey(keyValues)
+	if err != nil {
+		return nil, err
+	}
+	var paths []path
+	if projectionExpression != nil && *projectionExpression != "" {
+		if paths, err = parseProjection(*projectionExpression, names); err != nil {
+			return nil, err
+		}
+	}
+	found, ok := target.items[key]
+	if !ok {
+		return nil, nil
+	}
+	if paths != nil {
+		return projection(found, paths), nil
+	}
+	return found, nil
+}
+
+//PutItem creates or replaces item
+func (s *Server) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := checkReturnValues(input.ReturnValues, dynamodb.ReturnValueAllOld); err != nil {
+		return nil, err
+	}
+	change, err := s.putChange(input.TableName, input.Item, input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	change.apply()
+	return &dynamodb.PutItemOutput{Attributes: oldImage(input.ReturnValues, change.old)}, nil
+}
+
+//DeleteItem deletes item, deleting missing item is not an error
+func (s *Server) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := checkReturnValues(input.ReturnValues, dynamodb.ReturnValueAllOld); err != nil {
+		return nil, err
+	}
+	change, err := s.deleteChange(input.TableName, input.Key, input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	change.apply()
+	return &dynamodb.DeleteItemOutput{Attributes: oldImage(input.ReturnValues, change.old)}, nil
+}
+
+//UpdateItem updates existing item or creates a new one
+func (s *Server) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := checkReturnValues(input.ReturnValues, dynamodb.ReturnValueAllOld, dynamodb.ReturnValueAllNew, dynamodb.ReturnValueUpdatedOld, dynamodb.ReturnValueUpdatedNew); err != nil {
+		return nil, err
+	}
+	change, updatedNames, err := s.updateChange(input.TableName, input.Key, input.UpdateExpression, input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	change.apply()
+	output := &dynamodb.UpdateItemOutput{}
+	if input.ReturnValues == nil {
+		return output, nil
+	}
+	selected := func(source item) item {
+		var result = make(item)
+		for _, name := range updatedNames {
+			if value, ok := source[name]; ok {
+				result[name] = value
+			}
+		}
+		return result
+	}
+	switch *input.ReturnValues {
+	case dynamodb.ReturnValueAllOld:
+		output.Attributes = change.old
+	case dynamodb.ReturnValueAllNew:
+		output.Attributes = change.item
+	case dynamodb.ReturnValueUpdatedOld:
+		output.Attributes = selected(change.old)
+	case dynamodb.ReturnValueUpdatedNew:
+		output.Attributes = selected(change.item)
+	}
+	if len(output.Attributes) == 0 {
+		output.Attributes = nil
+	}
+	return output, nil
+}
+
+//BatchGetItem returns items by keys, all keys are processed
+func (s *Server) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var count int
+	for _, request := range input.RequestItems {
+		count += len(request.Keys)
+	}
+	if count == 0 || count > maxBatchGetKeys {
+		return nil, validationError("too many items requested for the BatchGetItem call: %v", count)
+	}
+	output := &dynamodb.BatchGetItemOutput{Responses: make(map[string][]map[string]*dynamodb.AttributeValue), UnprocessedKeys: make(map[string]*dynamodb.KeysAndAttributes)}
+	for tableName, request := range input.RequestItems {
+		name := tableName
+		target, err := s.table(&name)
+		if err != nil {
+			return nil, err
+		}
+		var requested = make(map[string]bool)
+		var items = make([]map[string]*dynamodb.AttributeValue, 0)
+		for _, keyValues := range request.Keys {
+			key, err := target.checkKey(keyValues)
+			if err != nil {
+				return nil, err
+			}
+			if requested[key] {
+				return nil, validationError("provided list of item keys contains duplicates")
+			}
+			requested[key] = true
+			found, err := s.getItem(&name, keyValues, request.ProjectionExpression, request.ExpressionAttributeNames)
+			if err != nil {
+				return nil, err
+			}
+			if found != nil {
+				items = append(items, found)
+			}
+		}
+		output.Responses[name] = items
+	}
+	return output, nil
+}
+
+//BatchWriteItem puts and deletes items, all requests are validated before any change is applied
+func (s *Server) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var count int
+	for _, requests := range input.RequestItems {
+		count += len(requests)
+	}
+	if count == 0 || count > maxBatchWriteItems {
+		return nil, validationError("too many items requested for the BatchWriteItem call: %v", count)
+	}
+	var changes = make([]*change, 0, count)
+	var changed = make(map[*table]map[string]bool)
+	for tableName, requests := range input.RequestItems {
+		name := tableName
+		for _, request := range requests {
+			var next *change
+			var err error
+			switch {
+			case request.PutRequest != nil && request.DeleteRequest == nil:
+				next, err = s.putChange(&name, request.PutRequest.Item, nil, nil, nil)
+			case request.DeleteRequest != nil && request.PutRequest == nil:
+				next, err = s.deleteChange(&name, request.DeleteRequest.Key, nil, nil, nil)
+			default:
+				err = validationError("write request has to have either PutRequest or DeleteRequest")
+			}
+			if err != nil {
+				return nil, err
+			}
+			if changed[next.table] == nil {
+				changed[next.table] = make(map[string]bool)
+			}
+			if changed[next.table][next.key] {
+				return nil, validationError("provided list of item keys contains duplicates")
+			}
+			changed[next.table][next.key] = true
+			changes = append(changes, next)
+		}
+	}
+	for _, next := range changes {
+		next.apply()
+	}
+	return &dynamodb.BatchWriteItemOutput{UnprocessedItems: make(map[string][]*dynamodb.WriteRequest)}, nil
+}
+
+//TransactWriteItems applies all changes or none, failed conditions cancel transaction with per item reasons
+func (s *Server) TransactWriteItems(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(input.TransactItems) == 0 || len(input.TransactItems) > maxTransactItems {
+		return nil, validationError("transaction has to have between 1 and %v items: %v", maxTransactItems, len(input.TransactItems))
+	}
+	var changes = make([]*change, 0, len(input.TransactItems))
+	var reasons = make([]*dynamodb.CancellationReason, 0, len(input.TransactItems))
+	var changed = make(map[*table]map[string]bool)
+	var canceled bool
+	for _, transactItem := range input.TransactItems {
+		var next *change
+		var err error
+		switch {
+		case transactItem.Put != nil:
+			put := transactItem.Put
+			next, err = s.putChange(put.TableName, put.Item, put.ConditionExpression, put.ExpressionAttributeNames, put.ExpressionAttributeValues)
+		case transactItem.Delete != nil:
+			deleted := transactItem.Delete
+			next, err = s.deleteChange(deleted.TableName, deleted.Key, deleted.ConditionExpression, deleted.ExpressionAttributeNames, deleted.ExpressionAttributeValues)
+		case transactItem.Update != nil:
+			updated := transactItem.Update
+			next, _, err = s.updateChange(updated.TableName, updated.Key, updated.UpdateExpression, updated.ConditionExpression, updated.ExpressionAttributeNames, updated.ExpressionAttributeValues)
+		case transactItem.ConditionCheck != nil:
+			check := transactItem.ConditionCheck
+			if check.ConditionExpression == nil {
+				return nil, validationError("ConditionCheck requires ConditionExpression")
+			}
+			if next, err = s.deleteChange(check.TableName, check.Key, check.ConditionExpression, check.ExpressionAttributeNames, check.ExpressionAttributeValues); err == nil {
+				next.item = next.old
+			}
+		default:
+			return nil, validationError("transact item has to have one of Put, Delete, Update or ConditionCheck")
+		}
+		if err != nil {
+			if apiError, ok := err.(*Error); ok && apiError.code == errCodeConditionFailed {
+				canceled = true
+				reasons = append(reasons, &dynamodb.CancellationReason{Code: stringPointer("ConditionalCheckFailed"), Message: stringPointer(apiError.message)})
+				continue
+			}
+			return nil, err
+		}
+		reasons = append(reasons, &dynamodb.CancellationReason{Code: stringPointer("None")})
+		if changed[next.table] == nil {
+			changed[next.table] = make(map[string]bool)
+		}
+		if changed[next.table][next.key] {
+			return nil, validationError("transaction request cannot include multiple operations on one item")
+		}
+		changed[next.table][next.key] = true
+		changes = append(changes, next)
+	}
+	if canceled {
+		return nil, &Error{code: errCodeTransactCanceled, message: "transaction cancelled, please refer cancellation reasons for specific reasons", reasons: reasons}
+	}
+	for _, next := range changes {
+		next.apply()
+	}
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
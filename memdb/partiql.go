@@ -0,0 +1,585 @@
+package memdb
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	errCodeDuplicateItem = "DuplicateItemException"
+
+	//maxBatchStatements max number of statements supported by BatchExecuteStatement
+	maxBatchStatements = 25
+)
+
+const (
+	tokenQuoted = tokenSymbol + 1 + iota
+	tokenString
+	tokenParameter
+)
+
+//partiQLKeywords expression keywords kept when PartiQL clause is translated to DynamoDB expression
+var partiQLKeywords = map[string]bool{"AND": true, "OR": true, "NOT": true, "BETWEEN": true, "IN": true, "SET": true, "REMOVE": true}
+
+//tokenizePartiQL splits PartiQL statement into identifiers, "quoted" identifiers, 'string' literals, numbers, ? parameters and symbols
+func tokenizePartiQL(statement string) ([]token, error) {
+	var result = make([]token, 0)
+	runes := []rune(statement)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == r {
+					if j+1 < len(runes) && runes[j+1] == r { //escaped quote
+						text.WriteRune(r)
+						j++
+						continue
+					}
+					break
+				}
+				text.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, validationError("statement wasn't well formed: unterminated %c at %v", r, i)
+			}
+			kind := tokenString
+			if r == '"' {
+				kind = tokenQuoted
+			}
+			result = append(result, token{kind: kind, text: text.String()})
+			i = j + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E' ||
+				((runes[j] == '-' || runes[j] == '+') && (runes[j-1] == 'e' || runes[j-1] == 'E'))) {
+				j++
+			}
+			result = append(result, token{kind: tokenNumber, text: string(runes[i:j])})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			result = append(result, token{kind: tokenIdentifier, text: string(runes[i:j])})
+			i = j
+		case r == '?':
+			result = append(result, token{kind: tokenParameter, text: "?"})
+			i++
+		default:
+			if i+1 < len(runes) {
+				if pair := string(runes[i : i+2]); pair == "<>" || pair == "!=" || pair == "<=" || pair == ">=" {
+					result = append(result, token{kind: tokenSymbol, text: pair})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("=<>()[]{},.:+-*", r) {
+				return nil, validationError("statement wasn't well formed: unexpected %q", r)
+			}
+			result = append(result, token{kind: tokenSymbol, text: string(r)})
+			i++
+		}
+	}
+	return append(result, token{kind: tokenEOF}), nil
+}
+
+//partiQLStatement represents PartiQL statement translated to DynamoDB expressions
+type partiQLStatement struct {
+	operation  string //SELECT, INSERT, UPDATE or DELETE
+	table      string
+	index      string
+	item       item
+	update     string
+	condition  string
+	projection string
+	returning  string //ALL OLD, ALL NEW, MODIFIED OLD or MODIFIED NEW
+	names      map[string]*string
+	values     map[string]*dynamodb.AttributeValue
+}
+
+//partiQLParser parses PartiQL statement, attribute names, literals and parameters are replaced with #name and :value placeholders
+type partiQLParser struct {
+	*parser
+	parameters []*dynamodb.AttributeValue
+	bound      int
+	aliases    map[string]string
+}
+
+func newPartiQLParser(statement string, parameters []*dynamodb.AttributeValue) (*partiQLParser, error) {
+	tokens, err := tokenizePartiQL(statement)
+	if err != nil {
+		return nil, err
+	}
+	return &partiQLParser{
+		parser:     &parser{expression: statement, tokens: tokens, names: make(map[string]*string), values: make(map[string]*dynamodb.AttributeValue)},
+		parameters: parameters,
+		aliases:    make(map[string]string),
+	}, nil
+}
+
+//name returns #name placeholder for attribute name
+func (p *partiQLParser) name(name string) string {
+	if alias, ok := p.aliases[name]; ok {
+		return alias
+	}
+	alias := "#n" + strconv.Itoa(len(p.aliases)+1)
+	p.aliases[name] = alias
+	p.names[alias] = stringPointer(name)
+	return alias
+}
+
+//value returns :value placeholder for literal or parameter
+func (p *partiQLParser) value(value *dynamodb.AttributeValue) string {
+	placeholder := ":v" + strconv.Itoa(len(p.values)+1)
+	p.values[placeholder] = value
+	return placeholder
+}
+
+func (p *partiQLParser) parameter() (*dynamodb.AttributeValue, error) {
+	if p.bound == len(p.parameters) {
+		return nil, validationError("number of parameters in request and statement don't match")
+	}
+	p.bound++
+	return p.parameters[p.bound-1], nil
+}
+
+//literal parses ? parameter, string, number, boolean, null, {map} or [list] literal
+func (p *partiQLParser) literal() (*dynamodb.AttributeValue, error) {
+	current := p.next()
+	switch current.kind {
+	case tokenParameter:
+		return p.parameter()
+	case tokenString:
+		return &dynamodb.AttributeValue{S: stringPointer(current.text)}, nil
+	case tokenNumber:
+		return &dynamodb.AttributeValue{N: stringPointer(current.text)}, nil
+	case tokenIdentifier:
+		switch strings.ToUpper(current.text) {
+		case "TRUE", "FALSE":
+			value := strings.EqualFold(current.text, "TRUE")
+			return &dynamodb.AttributeValue{BOOL: &value}, nil
+		case "NULL":
+			value := true
+			return &dynamodb.AttributeValue{NULL: &value}, nil
+		}
+	case tokenSymbol:
+		switch current.text {
+		case "-":
+			if number := p.peek(); number.kind == tokenNumber {
+				p.next()
+				return &dynamodb.AttributeValue{N: stringPointer("-" + number.text)}, nil
+			}
+		case "{":
+			var result = make(item)
+			for !p.isSymbol("}") {
+				key := p.next()
+				if key.kind != tokenString && key.kind != tokenQuoted {
+					p.position--
+					return nil, p.syntaxError()
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				value, err := p.literal()
+				if err != nil {
+					return nil, err
+				}
+				result[key.text] = value
+				if !p.isSymbol(",") {
+					break
+				}
+				p.next()
+			}
+			if err := p.expect("}"); err != nil {
+				return nil, err
+			}
+			return &dynamodb.AttributeValue{M: result}, nil
+		case "[":
+			var result = make([]*dynamodb.AttributeValue, 0)
+			for !p.isSymbol("]") {
+				value, err := p.literal()
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, value)
+				if !p.isSymbol(",") {
+					break
+				}
+				p.next()
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			return &dynamodb.AttributeValue{L: result}, nil
+		}
+	}
+	p.position--
+	return nil, p.syntaxError()
+}
+
+//clause translates tokens till one of stop keywords into DynamoDB expression
+func (p *partiQLParser) clause(stop ...string) (string, error) {
+	var result = make([]string, 0)
+	var previous token
+	for {
+		current := p.peek()
+		if current.kind == tokenEOF {
+			break
+		}
+		if current.kind == tokenIdentifier && isOneOf(current.text, stop) {
+			break
+		}
+		switch {
+		case current.kind == tokenQuoted:
+			p.next()
+			result = append(result, p.name(current.text))
+		case current.kind == tokenIdentifier && partiQLKeywords[strings.ToUpper(current.text)]:
+			p.next()
+			result = append(result, strings.ToUpper(current.text))
+		case current.kind == tokenIdentifier && !isOneOf(current.text, []string{"TRUE", "FALSE", "NULL"}):
+			p.next()
+			if p.isSymbol("(") { //function
+				result = append(result, current.text)
+			} else {
+				result = append(result, p.name(current.text))
+			}
+		case current.kind == tokenNumber && previous.kind == tokenSymbol && previous.text == "[": //list index
+			p.next()
+			result = append(result, current.text)
+		case current.kind == tokenSymbol && current.text == "[" && (previous.kind == tokenIdentifier || previous.kind == tokenQuoted || previous.text == "]"):
+			p.next()
+			result = append(result, current.text)
+		case current.kind == tokenSymbol && current.text != "{" && current.text != "[" && !(current.text == "-" && previous.kind == tokenSymbol):
+			p.next()
+			text := current.text
+			if text == "!=" {
+				text = "<>"
+			}
+			result = append(result, text)
+		default:
+			value, err := p.literal()
+			if err != nil {
+				return "", err
+			}
+			result = append(result, p.value(value))
+		}
+		previous = current
+	}
+	if len(result) == 0 {
+		return "", p.syntaxError()
+	}
+	return strings.Join(result, " "), nil
+}
+
+//tableName parses table and optional index name i.e. "music"."GenreIndex"
+func (p *partiQLParser) tableName(statement *partiQLStatement) error {
+	current := p.next()
+	if current.kind != tokenQuoted && current.kind != tokenIdentifier {
+		p.position--
+		return p.syntaxError()
+	}
+	statement.table = current.text
+	if !p.isSymbol(".") {
+		return nil
+	}
+	p.next()
+	if current = p.next(); current.kind != tokenQuoted && current.kind != tokenIdentifier {
+		p.position--
+		return p.syntaxError()
+	}
+	statement.index = current.text
+	return nil
+}
+
+//returning parses RETURNING ALL|MODIFIED OLD|NEW *
+func (p *partiQLParser) returning(statement *partiQLStatement) error {
+	if !p.isKeyword("RETURNING") {
+		return nil
+	}
+	p.next()
+	scope, image := strings.ToUpper(p.next().text), strings.ToUpper(p.next().text)
+	if (scope != "ALL" && scope != "MODIFIED") || (image != "OLD" && image != "NEW") {
+		return validationError("statement wasn't well formed: expected RETURNING ALL|MODIFIED OLD|NEW *")
+	}
+	statement.returning = scope + " " + image
+	return p.expect("*")
+}
+
+//parsePartiQL parses SELECT, INSERT, UPDATE or DELETE PartiQL statement
+func parsePartiQL(text string, parameters []*dynamodb.AttributeValue) (*partiQLStatement, error) {
+	p, err := newPartiQLParser(text, parameters)
+	if err != nil {
+		return nil, err
+	}
+	result := &partiQLStatement{names: p.names, values: p.values}
+	result.operation = strings.ToUpper(p.next().text)
+	switch result.operation {
+	case "SELECT":
+		if p.isSymbol("*") {
+			p.next()
+		} else if result.projection, err = p.clause("FROM"); err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("FROM") {
+			return nil, p.syntaxError()
+		}
+		p.next()
+		if err = p.tableName(result); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("WHERE") {
+			p.next()
+			if result.condition, err = p.clause(); err != nil {
+				return nil, err
+			}
+		}
+	case "INSERT":
+		if !p.isKeyword("INTO") {
+			return nil, p.syntaxError()
+		}
+		p.next()
+		if err = p.tableName(result); err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("VALUE") {
+			return nil, p.syntaxError()
+		}
+		p.next()
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		if value.M == nil {
+			return nil, validationError("statement wasn't well formed: INSERT value has to be a map")
+		}
+		result.item = value.M
+	case "UPDATE", "DELETE":
+		if result.operation == "DELETE" {
+			if !p.isKeyword("FROM") {
+				return nil, p.syntaxError()
+			}
+			p.next()
+		}
+		if err = p.tableName(result); err != nil {
+			return nil, err
+		}
+		if result.operation == "UPDATE" {
+			if !p.isKeyword("SET") && !p.isKeyword("REMOVE") {
+				return nil, p.syntaxError()
+			}
+			if result.update, err = p.clause("WHERE", "RETURNING"); err != nil {
+				return nil, err
+			}
+		}
+		if !p.isKeyword("WHERE") {
+			return nil, validationError("statement wasn't well formed: %v requires WHERE clause with key attributes", result.operation)
+		}
+		p.next()
+		if result.condition, err = p.clause("RETURNING"); err != nil {
+			return nil, err
+		}
+		if err = p.returning(result); err != nil {
+			return nil, err
+		}
+	default:
+		p.position--
+		return nil, validationError("statement wasn't well formed: unsupported statement %v", result.operation)
+	}
+	if err = p.done(); err != nil {
+		return nil, err
+	}
+	if p.bound != len(parameters) {
+		return nil, validationError("number of parameters in request and statement don't match")
+	}
+	return result, nil
+}
+
+//statementKey returns primary key from top level WHERE equalities
+func (t *table) statementKey(statement *partiQLStatement) (item, error) {
+	parsed, err := parseCondition(statement.condition, statement.names, statement.values)
+	if err != nil {
+		return nil, err
+	}
+	var equalities = make(item)
+	var collect func(parsed condition)
+	collect = func(parsed condition) {
+		switch actual := parsed.(type) {
+		case *logical:
+			if actual.operator == "AND" {
+				collect(actual.left)
+				collect(actual.right)
+			}
+		case *comparison:
+			if actual.operator != "=" {
+				return
+			}
+			name, value := actual.left, actual.right
+			if _, ok := name.(path); !ok {
+				name, value = value, name
+			}
+			attribute, ok := name.(path)
+			constant, isLiteral := value.(*literal)
+			if ok && isLiteral && len(attribute) == 1 && !attribute[0].isIndex {
+				equalities[attribute[0].name] = constant.attribute
+			}
+		}
+	}
+	collect(parsed)
+	var result = make(item)
+	for _, name := range t.primary.keyNames() {
+		value, ok := equalities[name]
+		if !ok {
+			return nil, validationError("where clause does not contain a mandatory equality on all key attributes")
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+//executeStatement executes parsed PartiQL statement, writes require full primary key equality in WHERE clause
+func (s *Server) executeStatement(statement *partiQLStatement) ([]map[string]*dynamodb.AttributeValue, error) {
+	if statement.operation == "SELECT" {
+		var condition, projection *string
+		if statement.condition != "" {
+			condition = &statement.condition
+		}
+		if statement.projection != "" {
+			projection = &statement.projection
+		}
+		request, err := s.newRead(&statement.table, &statement.index, nil, nil, condition, projection, nil, nil, statement.names, statement.values)
+		if err != nil {
+			return nil, err
+		}
+		items, _, _, _, err := request.run(request.table.sorted(request.view))
+		return items, err
+	}
+	target, err := s.table(&statement.table)
+	if err != nil {
+		return nil, err
+	}
+	if statement.index != "" {
+		return nil, validationError("%v statement does not support index", statement.operation)
+	}
+	if statement.operation == "INSERT" {
+		change, err := s.putChange(&statement.table, statement.item, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if change.old != nil {
+			return nil, newError(errCodeDuplicateItem, "duplicate primary key exists in table")
+		}
+		change.apply()
+		return nil, nil
+	}
+	key, err := target.statementKey(statement)
+	if err != nil {
+		return nil, err
+	}
+	var next *change
+	var updatedNames []string
+	if statement.operation == "DELETE" {
+		if next, err = s.deleteChange(&statement.table, key, nil, nil, nil); err != nil || next.old == nil { //deleting missing item is not an error
+			return nil, err
+		}
+		if err = checkCondition(&statement.condition, statement.names, statement.values, next.old); err != nil {
+			return nil, err
+		}
+	} else if next, updatedNames, err = s.updateChange(&statement.table, key, &statement.update, &statement.condition, statement.names, statement.values); err != nil {
+		return nil, err
+	}
+	next.apply()
+	var image item
+	switch statement.returning {
+	case "ALL OLD":
+		image = next.old
+	case "ALL NEW":
+		image = next.item
+	case "MODIFIED OLD", "MODIFIED NEW":
+		source := next.old
+		if statement.returning == "MODIFIED NEW" {
+			source = next.item
+		}
+		image = make(item)
+		for _, name := range updatedNames {
+			if value, ok := source[name]; ok {
+				image[name] = value
+			}
+		}
+	}
+	if len(image) == 0 {
+		return nil, nil
+	}
+	return []map[string]*dynamodb.AttributeValue{image}, nil
+}
+
+//ExecuteStatement executes PartiQL SELECT, INSERT, UPDATE or DELETE statement, SELECT returns all matching items in a single page
+func (s *Server) ExecuteStatement(input *dynamodb.ExecuteStatementInput) (*dynamodb.ExecuteStatementOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if input.Statement == nil {
+		return nil, validationError("statement was empty")
+	}
+	statement, err := parsePartiQL(*input.Statement, input.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.executeStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = make([]map[string]*dynamodb.AttributeValue, 0)
+	}
+	return &dynamodb.ExecuteStatementOutput{Items: items}, nil
+}
+
+//BatchExecuteStatement executes each PartiQL statement independently, statement errors are returned in responses
+func (s *Server) BatchExecuteStatement(input *dynamodb.BatchExecuteStatementInput) (*dynamodb.BatchExecuteStatementOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(input.Statements) == 0 || len(input.Statements) > maxBatchStatements {
+		return nil, validationError("batch has to have between 1 and %v statements: %v", maxBatchStatements, len(input.Statements))
+	}
+	output := &dynamodb.BatchExecuteStatementOutput{}
+	for _, request := range input.Statements {
+		response := &dynamodb.BatchStatementResponse{}
+		statement, err := parsePartiQL(stringValue(request.Statement), request.Parameters)
+		if err == nil {
+			response.TableName = &statement.table
+			var items []map[string]*dynamodb.AttributeValue
+			if items, err = s.executeStatement(statement); err == nil && statement.operation == "SELECT" && len(items) > 0 {
+				response.Item = items[0]
+			}
+		}
+		if err != nil {
+			apiError, ok := err.(*Error)
+			if !ok {
+				return nil, err
+			}
+			code := strings.TrimSuffix(apiError.code, "Exception")
+			if apiError.code == errCodeValidation {
+				code = "ValidationError"
+			}
+			response.Error = &dynamodb.BatchStatementError{Code: stringPointer(code), Message: stringPointer(apiError.message)}
+		}
+		output.Responses = append(output.Responses, response)
+	}
+	return output, nil
+}
+
+func isOneOf(candidate string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.EqualFold(candidate, keyword) {
+			return true
+		}
+	}
+	return false
+}
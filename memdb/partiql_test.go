@@ -0,0 +1,159 @@
+package memdb_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestServer_ExecuteStatement(t *testing.T) {
+	db := newClient(t)
+	_, err := db.CreateTable(&dynamodb.CreateTableInput{
+		TableName: aws.String("music"),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("Artist"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("SongTitle"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("Artist"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("SongTitle"), KeyType: aws.String("RANGE")},
+		},
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var useCases = []struct {
+		description string
+		statement   string
+		parameters  []interface{}
+		expect      []map[string]interface{}
+		errorCode   string
+	}{
+		{
+			description: "insert",
+			statement:   `INSERT INTO "music" VALUE {'Artist': ?, 'SongTitle': 'Title0', 'Year': 2000, 'Tags': ['rock', 'pop'], 'Live': false}`,
+			parameters:  []interface{}{"Artist0"},
+		},
+		{
+			description: "insert second item",
+			statement:   `INSERT INTO music VALUE {'Artist': 'Artist0', 'SongTitle': ?, 'Year': 2001}`,
+			parameters:  []interface{}{"Title1"},
+		},
+		{
+			description: "insert duplicate",
+			statement:   `INSERT INTO "music" VALUE {'Artist': 'Artist0', 'SongTitle': 'Title0'}`,
+			errorCode:   "DuplicateItemException",
+		},
+		{
+			description: "select with criteria and projection",
+			statement:   `SELECT SongTitle, "Year" FROM "music" WHERE Artist = ? AND "Year" > 2000`,
+			parameters:  []interface{}{"Artist0"},
+			expect:      []map[string]interface{}{{"SongTitle": "Title1", "Year": float64(2001)}},
+		},
+		{
+			description: "update returning new image",
+			statement:   `UPDATE "music" SET "Year" = "Year" + 1 WHERE Artist = 'Artist0' AND SongTitle = ? RETURNING MODIFIED NEW *`,
+			parameters:  []interface{}{"Title0"},
+			expect:      []map[string]interface{}{{"Year": float64(2001)}},
+		},
+		{
+			description: "update missing item",
+			statement:   `UPDATE "music" SET "Year" = 1 WHERE Artist = 'Artist0' AND SongTitle = 'Title9'`,
+			errorCode:   "ConditionalCheckFailedException",
+		},
+		{
+			description: "update without key",
+			statement:   `UPDATE "music" SET "Year" = 1 WHERE Artist = 'Artist0'`,
+			errorCode:   "ValidationException",
+		},
+		{
+			description: "delete returning old image",
+			statement:   `DELETE FROM "music" WHERE Artist = 'Artist0' AND SongTitle = 'Title1' RETURNING ALL OLD *`,
+			expect:      []map[string]interface{}{{"Artist": "Artist0", "SongTitle": "Title1", "Year": float64(2001)}},
+		},
+		{
+			description: "delete missing item",
+			statement:   `DELETE FROM "music" WHERE Artist = 'Artist0' AND SongTitle = 'Title1' RETURNING ALL OLD *`,
+			expect:      []map[string]interface{}{},
+		},
+		{
+			description: "select all",
+			statement:   `SELECT * FROM "music"`,
+			expect:      []map[string]interface{}{{"Artist": "Artist0", "SongTitle": "Title0", "Year": float64(2001), "Tags": []interface{}{"rock", "pop"}, "Live": false}},
+		},
+		{
+			description: "parameters mismatch",
+			statement:   `SELECT * FROM "music" WHERE Artist = ?`,
+			errorCode:   "ValidationException",
+		},
+		{
+			description: "missing table",
+			statement:   `SELECT * FROM "songs"`,
+			errorCode:   "ResourceNotFoundException",
+		},
+	}
+	for _, useCase := range useCases {
+		input := &dynamodb.ExecuteStatementInput{Statement: aws.String(useCase.statement)}
+		for _, parameter := range useCase.parameters {
+			value, err := dynamodbattribute.Marshal(parameter)
+			if !assert.Nil(t, err, useCase.description) {
+				return
+			}
+			input.Parameters = append(input.Parameters, value)
+		}
+		output, err := db.ExecuteStatement(input)
+		if useCase.errorCode != "" {
+			assert.EqualValues(t, useCase.errorCode, errorCode(err), useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		if useCase.expect == nil {
+			continue
+		}
+		var actual = make([]map[string]interface{}, 0)
+		if assert.Nil(t, dynamodbattribute.UnmarshalListOfMaps(output.Items, &actual), useCase.description) {
+			assert.EqualValues(t, useCase.expect, actual, useCase.description)
+		}
+	}
+}
+
+func TestServer_BatchExecuteStatement(t *testing.T) {
+	db := newClient(t)
+	_, err := db.CreateTable(&dynamodb.CreateTableInput{
+		TableName:            aws.String("users"),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{{AttributeName: aws.String("Id"), AttributeType: aws.String("N")}},
+		KeySchema:            []*dynamodb.KeySchemaElement{{AttributeName: aws.String("Id"), KeyType: aws.String("HASH")}},
+		BillingMode:          aws.String(dynamodb.BillingModePayPerRequest),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	output, err := db.BatchExecuteStatement(&dynamodb.BatchExecuteStatementInput{Statements: []*dynamodb.BatchStatementRequest{
+		{Statement: aws.String(`INSERT INTO "users" VALUE {'Id': 1, 'Name': 'user1'}`)},
+		{Statement: aws.String(`INSERT INTO "users" VALUE {'Id': ?, 'Name': 'user2'}`), Parameters: []*dynamodb.AttributeValue{{N: aws.String("2")}}},
+		{Statement: aws.String(`INSERT INTO "users" VALUE {'Id': 1, 'Name': 'duplicate'}`)},
+		{Statement: aws.String(`UPDATE "users" SET Name = 'missing' WHERE Id = 3`)},
+	}})
+	if !assert.Nil(t, err) || !assert.Len(t, output.Responses, 4) {
+		return
+	}
+	assert.Nil(t, output.Responses[0].Error)
+	assert.Nil(t, output.Responses[1].Error)
+	if assert.NotNil(t, output.Responses[2].Error) {
+		assert.EqualValues(t, "DuplicateItem", *output.Responses[2].Error.Code)
+	}
+	if assert.NotNil(t, output.Responses[3].Error) {
+		assert.EqualValues(t, "ConditionalCheckFailed", *output.Responses[3].Error.Code)
+	}
+	read, err := db.BatchExecuteStatement(&dynamodb.BatchExecuteStatementInput{Statements: []*dynamodb.BatchStatementRequest{
+		{Statement: aws.String(`SELECT Name FROM "users" WHERE Id = 2`)},
+	}})
+	if assert.Nil(t, err) && assert.Len(t, read.Responses, 1) {
+		assert.EqualValues(t, "user2", aws.StringValue(read.Responses[0].Item["Name"].S))
+	}
+}
@@ -0,0 +1,248 @@
+package memdb
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"hash/fnv"
+)
+
+//read represents Query or Scan request
+type read struct {
+	table      *table
+	view       *index
+	startKey   item
+	limit      int
+	forward    bool
+	filter     condition
+	projection []path
+	count      bool
+}
+
+//newRead returns read for shared Query and Scan parameters
+func (s *Server) newRead(tableName, indexName *string, startKey item, limit *int64, filterExpression, projectionExpression, selected *string, consistent *bool, names map[string]*string, values map[string]*dynamodb.AttributeValue) (*read, error) {
+	target, err := s.table(tableName)
+	if err != nil {
+		return nil, err
+	}
+	view, err := target.index(indexName)
+	if err != nil {
+		return nil, err
+	}
+	if consistent != nil && *consistent && view.global {
+		return nil, validationError("consistent reads are not supported on global secondary indexes")
+	}
+	result := &read{table: target, view: view, startKey: startKey, forward: true}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, validationError("limit must be greater than or equal to 1")
+		}
+		result.limit = int(*limit)
+	}
+	if filterExpression != nil && *filterExpression != "" {
+		if result.filter, err = parseCondition(*filterExpression, names, values); err != nil {
+			return nil, err
+		}
+	}
+	if projectionExpression != nil && *projectionExpression != "" {
+		if result.projection, err = parseProjection(*projectionExpression, names); err != nil {
+			return nil, err
+		}
+	}
+	if selected != nil {
+		switch *selected {
+		case dynamodb.SelectCount:
+			result.count = true
+		case dynamodb.SelectSpecificAttributes:
+			if result.projection == nil {
+				return nil, validationError("select SPECIFIC_ATTRIBUTES requires ProjectionExpression")
+			}
+		case dynamodb.SelectAllAttributes, dynamodb.SelectAllProjectedAttributes:
+			if result.projection != nil {
+				return nil, validationError("cannot specify the ProjectionExpression when choosing to get %v", *selected)
+			}
+		default:
+			return nil, validationError("invalid Select: %v", *selected)
+		}
+	}
+	if len(startKey) > 0 {
+		for _, name := range target.orderNames(view) {
+			if _, ok := startKey[name]; !ok {
+				return nil, validationError("the provided starting key is invalid: missing %v", name)
+			}
+		}
+	}
+	return result, nil
+}
+
+//run reads candidates in index order starting after start key, limit applies to evaluated items before filtering
+func (r *read) run(candidates []item) (items []map[string]*dynamodb.AttributeValue, count, scanned int64, lastEvaluatedKey item, err error) {
+	names := r.table.orderNames(r.view)
+	if !r.forward {
+		for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		}
+	}
+	if len(r.startKey) > 0 {
+		var skipped int
+		for skipped < len(candidates) {
+			order := compareOrder(names, candidates[skipped], r.startKey)
+			if (r.forward && order > 0) || (!r.forward && order < 0) {
+				break
+			}
+			skipped++
+		}
+		candidates = candidates[skipped:]
+	}
+	if !r.count {
+		items = make([]map[string]*dynamodb.AttributeValue, 0)
+	}
+	for i, candidate := range candidates {
+		projected := r.table.project(r.view, candidate)
+		scanned++
+		matched := true
+		if r.filter != nil {
+			if matched, err = r.filter.evaluate(projected); err != nil {
+				return nil, 0, 0, nil, err
+			}
+		}
+		if matched {
+			count++
+			if !r.count {
+				if r.projection != nil {
+					projected = projection(projected, r.projection)
+				}
+				items = append(items, projected)
+			}
+		}
+		if r.limit > 0 && int(scanned) == r.limit && i+1 < len(candidates) {
+			lastEvaluatedKey = make(item)
+			for _, name := range names {
+				lastEvaluatedKey[name] = candidate[name]
+			}
+			break
+		}
+	}
+	return items, count, scanned, lastEvaluatedKey, nil
+}
+
+//Scan returns items in index order, segments partition items by hash key
+func (s *Server) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	request, err := s.newRead(input.TableName, input.IndexName, input.ExclusiveStartKey, input.Limit, input.FilterExpression, input.ProjectionExpression, input.Select, input.ConsistentRead, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	candidates := request.table.sorted(request.view)
+	if input.TotalSegments != nil || input.Segment != nil {
+		if input.TotalSegments == nil || input.Segment == nil || *input.TotalSegments < 1 || *input.Segment < 0 || *input.Segment >= *input.TotalSegments {
+			return nil, validationError("invalid Segment %v and TotalSegments %v", input.Segment, input.TotalSegments)
+		}
+		var segment = make([]item, 0)
+		for _, candidate := range candidates {
+			key, err := encodeKey(candidate, []string{request.view.hash})
+			if err != nil {
+				return nil, err
+			}
+			hash := fnv.New32a()
+			_, _ = hash.Write([]byte(key))
+			if int64(hash.Sum32())%*input.TotalSegments == *input.Segment {
+				segment = append(segment, candidate)
+			}
+		}
+		candidates = segment
+	}
+	items, count, scanned, lastEvaluatedKey, err := request.run(candidates)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.ScanOutput{Items: items, Count: &count, ScannedCount: &scanned, LastEvaluatedKey: lastEvaluatedKey}, nil
+}
+
+//Query returns items matching key condition in index order
+func (s *Server) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	request, err := s.newRead(input.TableName, input.IndexName, input.ExclusiveStartKey, input.Limit, input.FilterExpression, input.ProjectionExpression, input.Select, input.ConsistentRead, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	if input.KeyConditionExpression == nil || *input.KeyConditionExpression == "" {
+		return nil, validationError("either the KeyConditions or KeyConditionExpression parameter must be specified in the request")
+	}
+	keyCondition, err := parseCondition(*input.KeyConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkKeyCondition(keyCondition, request.view); err != nil {
+		return nil, err
+	}
+	if input.ScanIndexForward != nil {
+		request.forward = *input.ScanIndexForward
+	}
+	var candidates = make([]item, 0)
+	for _, candidate := range request.table.sorted(request.view) {
+		matched, err := keyCondition.evaluate(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			candidates = append(candidates, candidate)
+		}
+	}
+	items, count, scanned, lastEvaluatedKey, err := request.run(candidates)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.QueryOutput{Items: items, Count: &count, ScannedCount: &scanned, LastEvaluatedKey: lastEvaluatedKey}, nil
+}
+
+//checkKeyCondition checks that key condition has hash key equality and optional range key condition
+func checkKeyCondition(keyCondition condition, view *index) error {
+	var terms = make([]condition, 0, 2)
+	var collect func(candidate condition)
+	collect = func(candidate condition) {
+		if conjunction, ok := candidate.(*logical); ok && conjunction.operator == "AND" {
+			collect(conjunction.left)
+			collect(conjunction.right)
+			return
+		}
+		terms = append(terms, candidate)
+	}
+	collect(keyCondition)
+	keyName := func(candidate operand) string {
+		if keyPath, ok := candidate.(path); ok && len(keyPath) == 1 {
+			return keyPath[0].name
+		}
+		return ""
+	}
+	var hasHash bool
+	for _, term := range terms {
+		var name string
+		switch actual := term.(type) {
+		case *comparison:
+			if actual.operator == "<>" {
+				return validationError("unsupported operator on KeyConditionExpression: <>")
+			}
+			if name = keyName(actual.left); name == view.hash && actual.operator == "=" && !hasHash {
+				hasHash = true
+				continue
+			}
+		case *between:
+			name = keyName(actual.operand)
+		case *function:
+			if actual.name == "begins_with" && len(actual.path) == 1 {
+				name = actual.path[0].name
+			}
+		}
+		if name == "" || name == view.hash || name != view.rangeKey {
+			return validationError("query key condition not supported")
+		}
+	}
+	if !hasHash {
+		return validationError("query condition missed key schema element: %v", view.hash)
+	}
+	if len(terms) > 2 {
+		return validationError("query key condition not supported")
+	}
+	return nil
+}
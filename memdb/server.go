@@ -0,0 +1,210 @@
+//Package memdb provides in-memory DynamoDB serving the subset of DynamoDB JSON API used by dyndb,
+//it covers tables, items, queries, scans, batch and transactional writes and PartiQL statements so that tests run without DynamoDB Local.
+package memdb
+
+import (
+	"fmt"
+	"github.com/adrianwit/dyndb/endpoint"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"hash/crc32"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	targetPrefix = "DynamoDB_20120810."
+	errorPrefix  = "com.amazonaws.dynamodb.v20120810#"
+	contentType  = "application/x-amz-json-1.0"
+	arnPrefix    = "arn:aws:dynamodb:memory:000000000000:table/"
+
+	errCodeInternal         = "InternalServerError"
+	errCodeValidation       = "ValidationException"
+	errCodeNotFound         = "ResourceNotFoundException"
+	errCodeInUse            = "ResourceInUseException"
+	errCodeConditionFailed  = "ConditionalCheckFailedException"
+	errCodeTransactCanceled = "TransactionCanceledException"
+	errCodeUnknownOperation = "UnknownOperationException"
+	errCodeTableNotFound    = "TableNotFoundException"
+	errCodeTableExists      = "TableAlreadyExistsException"
+	errCodeBackupNotFound   = "BackupNotFoundException"
+	errCodePITRUnavailable  = "PointInTimeRecoveryUnavailableException"
+	errCodeRestoreTime      = "InvalidRestoreTimeException"
+)
+
+//Server represents in-memory DynamoDB, its exported methods mirror DynamoDB API operations and are served over HTTP
+type Server struct {
+	mutex   sync.Mutex
+	tables  map[string]*table
+	backups map[string]*backup
+}
+
+//ServeHTTP handles DynamoDB JSON 1.0 protocol request dispatched by X-Amz-Target header
+func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	target := request.Header.Get("X-Amz-Target")
+	method, ok := s.operation(target)
+	if !ok {
+		writeError(writer, newError(errCodeUnknownOperation, "unknown operation: %v", target))
+		return
+	}
+	input := reflect.New(method.Type().In(0).Elem())
+	if err := unmarshalJSON(input.Interface(), request.Body); err != nil {
+		writeError(writer, validationError("failed to decode %v input: %v", target, err))
+		return
+	}
+	results := method.Call([]reflect.Value{input})
+	if err, _ := results[1].Interface().(error); err != nil {
+		writeError(writer, err)
+		return
+	}
+	body, err := marshalJSON(results[0].Interface())
+	if err != nil {
+		writeError(writer, newError(errCodeInternal, "%v", err))
+		return
+	}
+	writeBody(writer, http.StatusOK, body)
+}
+
+//operation returns server method implementing targeted operation
+func (s *Server) operation(target string) (reflect.Value, bool) {
+	if !strings.HasPrefix(target, targetPrefix) {
+		return reflect.Value{}, false
+	}
+	method := reflect.ValueOf(s).MethodByName(strings.TrimPrefix(target, targetPrefix))
+	if !method.IsValid() {
+		return reflect.Value{}, false
+	}
+	methodType := method.Type()
+	if methodType.NumIn() != 1 || methodType.NumOut() != 2 || methodType.In(0).Kind() != reflect.Ptr {
+		return reflect.Value{}, false
+	}
+	if !strings.HasSuffix(methodType.In(0).Elem().Name(), "Input") {
+		return reflect.Value{}, false
+	}
+	return method, true
+}
+
+//Error represents DynamoDB API error, it implements awserr.Error
+type Error struct {
+	code    string
+	message string
+	reasons []*dynamodb.CancellationReason
+}
+
+//Error returns error text
+func (e *Error) Error() string {
+	return e.code + ": " + e.message
+}
+
+//Code returns DynamoDB error code i.e. ConditionalCheckFailedException
+func (e *Error) Code() string {
+	return e.code
+}
+
+//Message returns error message
+func (e *Error) Message() string {
+	return e.message
+}
+
+//OrigErr returns nil, errors are not wrapped
+func (e *Error) OrigErr() error {
+	return nil
+}
+
+func newError(code string, format string, args ...interface{}) *Error {
+	return &Error{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func writeError(writer http.ResponseWriter, err error) {
+	apiError, ok := err.(*Error)
+	if !ok {
+		apiError = newError(errCodeInternal, "%v", err)
+	}
+	var status = http.StatusBadRequest
+	if apiError.code == errCodeInternal {
+		status = http.StatusInternalServerError
+	}
+	body, _ := marshalJSON(&struct {
+		Type                *string                        `locationName:"__type" type:"string"`
+		Message             *string                        `locationName:"message" type:"string"`
+		CancellationReasons []*dynamodb.CancellationReason `type:"list"`
+	}{
+		Type:                stringPointer(errorPrefix + apiError.code),
+		Message:             stringPointer(apiError.message),
+		CancellationReasons: apiError.reasons,
+	})
+	writeBody(writer, status, body)
+}
+
+//writeBody writes JSON body with X-Amz-Crc32 checksum header as DynamoDB does, SDK v2 validates it
+func writeBody(writer http.ResponseWriter, status int, body []byte) {
+	writer.Header().Set("Content-Type", contentType)
+	writer.Header().Set("X-Amz-Crc32", strconv.FormatUint(uint64(crc32.ChecksumIEEE(body)), 10))
+	writer.WriteHeader(status)
+	_, _ = writer.Write(body)
+}
+
+func validationError(format string, args ...interface{}) *Error {
+	return newError(errCodeValidation, format, args...)
+}
+
+func stringPointer(text string) *string {
+	return &text
+}
+
+//New creates empty in-memory DynamoDB
+func New() *Server {
+	return &Server{tables: make(map[string]*table), backups: make(map[string]*backup)}
+}
+
+//memoryScheme endpoint prefix selects shared in-memory DynamoDB in dyndb config i.e. memory://test
+const memoryScheme = "memory://"
+
+func init() {
+	endpoint.Register(memoryScheme, Endpoint)
+}
+
+//sharedServer represents named in-memory DynamoDB served over HTTP
+type sharedServer struct {
+	URL    string
+	server *http.Server
+}
+
+var endpoints = struct {
+	sync.Mutex
+	byName map[string]*sharedServer
+}{byName: make(map[string]*sharedServer)}
+
+//Endpoint returns URL of in-memory DynamoDB shared by all clients using the same name, the server is started on first use
+func Endpoint(name string) (string, error) {
+	endpoints.Lock()
+	defer endpoints.Unlock()
+	if shared, ok := endpoints.byName[name]; ok {
+		return shared.URL, nil
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to start memory DynamoDB %v, %v", name, err)
+	}
+	shared := &sharedServer{URL: "http://" + listener.Addr().String(), server: &http.Server{Handler: New()}}
+	go func() {
+		_ = shared.server.Serve(listener)
+	}()
+	endpoints.byName[name] = shared
+	return shared.URL, nil
+}
+
+//Close stops named in-memory DynamoDB discarding its data, next Endpoint call starts an empty one
+func Close(name string) error {
+	endpoints.Lock()
+	shared, ok := endpoints.byName[name]
+	delete(endpoints.byName, name)
+	endpoints.Unlock()
+	if !ok {
+		return nil
+	}
+	return shared.server.Close()
+}
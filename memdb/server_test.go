@@ -0,0 +1,228 @@
+package memdb_test
+
+import (
+	"fmt"
+	"github.com/adrianwit/dyndb/memdb"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func newClient(t *testing.T) *dynamodb.DynamoDB {
+	server := httptest.NewServer(memdb.New())
+	t.Cleanup(server.Close)
+	config := aws.NewConfig().
+		WithEndpoint(server.URL).
+		WithRegion("us-west-1").
+		WithCredentials(credentials.NewStaticCredentials("key", "secret", "")).
+		WithMaxRetries(0)
+	return dynamodb.New(session.Must(session.NewSession()), config)
+}
+
+func errorCode(err error) string {
+	if awsError, ok := err.(awserr.Error); ok {
+		return awsError.Code()
+	}
+	return ""
+}
+
+func TestServer(t *testing.T) {
+	db := newClient(t)
+	createInput := &dynamodb.CreateTableInput{
+		TableName: aws.String("music"),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("Artist"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("SongTitle"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("Genre"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("Artist"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("SongTitle"), KeyType: aws.String("RANGE")},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName:  aws.String("genre"),
+				KeySchema:  []*dynamodb.KeySchemaElement{{AttributeName: aws.String("Genre"), KeyType: aws.String("HASH")}},
+				Projection: &dynamodb.Projection{ProjectionType: aws.String("KEYS_ONLY")},
+			},
+		},
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+	}
+	_, err := db.CreateTable(createInput)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = db.CreateTable(createInput)
+	assert.EqualValues(t, dynamodb.ErrCodeResourceInUseException, errorCode(err))
+
+	for i := 0; i < 5; i++ {
+		item := map[string]*dynamodb.AttributeValue{
+			"Artist":    {S: aws.String("Artist0")},
+			"SongTitle": {S: aws.String(fmt.Sprintf("Title%d", i))},
+			"Price":     {N: aws.String(fmt.Sprintf("%d.5", i))},
+		}
+		if i%2 == 0 {
+			item["Genre"] = &dynamodb.AttributeValue{S: aws.String("rock")}
+		}
+		_, err = db.PutItem(&dynamodb.PutItemInput{TableName: aws.String("music"), Item: item})
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	_, err = db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String("music"),
+		Item:                map[string]*dynamodb.AttributeValue{"Artist": {S: aws.String("Artist0")}, "SongTitle": {S: aws.String("Title0")}},
+		ConditionExpression: aws.String("attribute_not_exists(Artist)"),
+	})
+	assert.EqualValues(t, dynamodb.ErrCodeConditionalCheckFailedException, errorCode(err))
+
+	//query pages are positional, deleting returned items does not reset paging
+	var titles []string
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String("music"),
+		KeyConditionExpression:    aws.String("Artist = :a AND SongTitle > :t"),
+		FilterExpression:          aws.String("Price > :p"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":a": {S: aws.String("Artist0")}, ":t": {S: aws.String("Title0")}, ":p": {N: aws.String("1.5")}},
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int64(2),
+	}
+	for pages := 0; ; pages++ {
+		output, err := db.Query(input)
+		if !assert.Nil(t, err) || !assert.True(t, pages < 3) {
+			return
+		}
+		for _, item := range output.Items {
+			titles = append(titles, *item["SongTitle"].S)
+			_, err = db.DeleteItem(&dynamodb.DeleteItemInput{TableName: aws.String("music"), Key: map[string]*dynamodb.AttributeValue{"Artist": item["Artist"], "SongTitle": item["SongTitle"]}})
+			assert.Nil(t, err)
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+	assert.EqualValues(t, []string{"Title4", "Title3", "Title2"}, titles)
+
+	scanned, err := db.Scan(&dynamodb.ScanInput{TableName: aws.String("music"), IndexName: aws.String("genre")})
+	if assert.Nil(t, err) && assert.Len(t, scanned.Items, 1) {
+		assert.EqualValues(t, 3, len(scanned.Items[0]))
+		assert.EqualValues(t, "Title0", *scanned.Items[0]["SongTitle"].S)
+	}
+
+	updated, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String("music"),
+		Key:                       map[string]*dynamodb.AttributeValue{"Artist": {S: aws.String("Artist0")}, "SongTitle": {S: aws.String("Title1")}},
+		UpdateExpression:          aws.String("SET Price = Price + :p"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":p": {N: aws.String("0.25")}},
+		ReturnValues:              aws.String(dynamodb.ReturnValueUpdatedNew),
+	})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "1.75", *updated.Attributes["Price"].N)
+	}
+
+	_, err = db.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{Put: &dynamodb.Put{TableName: aws.String("music"), Item: map[string]*dynamodb.AttributeValue{"Artist": {S: aws.String("Artist1")}, "SongTitle": {S: aws.String("Title0")}}}},
+			{ConditionCheck: &dynamodb.ConditionCheck{
+				TableName:           aws.String("music"),
+				Key:                 map[string]*dynamodb.AttributeValue{"Artist": {S: aws.String("Artist0")}, "SongTitle": {S: aws.String("Title1")}},
+				ConditionExpression: aws.String("attribute_not_exists(Price)"),
+			}},
+		},
+	})
+	if canceled, ok := err.(*dynamodb.TransactionCanceledException); assert.True(t, ok, fmt.Sprintf("%v", err)) {
+		if assert.Len(t, canceled.CancellationReasons, 2) {
+			assert.EqualValues(t, "None", *canceled.CancellationReasons[0].Code)
+			assert.EqualValues(t, "ConditionalCheckFailed", *canceled.CancellationReasons[1].Code)
+		}
+	}
+	described, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("music")})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, 2, *described.Table.ItemCount)
+	}
+}
+
+func TestServer_UpdateTable(t *testing.T) {
+	db := newClient(t)
+	_, err := db.CreateTable(&dynamodb.CreateTableInput{
+		TableName:            aws.String("users"),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{{AttributeName: aws.String("Id"), AttributeType: aws.String("S")}},
+		KeySchema:            []*dynamodb.KeySchemaElement{{AttributeName: aws.String("Id"), KeyType: aws.String("HASH")}},
+		BillingMode:          aws.String(dynamodb.BillingModePayPerRequest),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var useCases = []struct {
+		description string
+		update      *dynamodb.ReplicationGroupUpdate
+		expect      []string
+		code        string
+	}{
+		{
+			description: "create replica",
+			update:      &dynamodb.ReplicationGroupUpdate{Create: &dynamodb.CreateReplicationGroupMemberAction{RegionName: aws.String("eu-west-1")}},
+			expect:      []string{"eu-west-1"},
+		},
+		{
+			description: "create another replica",
+			update:      &dynamodb.ReplicationGroupUpdate{Create: &dynamodb.CreateReplicationGroupMemberAction{RegionName: aws.String("us-east-1")}},
+			expect:      []string{"eu-west-1", "us-east-1"},
+		},
+		{
+			description: "existing replica",
+			update:      &dynamodb.ReplicationGroupUpdate{Create: &dynamodb.CreateReplicationGroupMemberAction{RegionName: aws.String("us-east-1")}},
+			code:        "ValidationException",
+		},
+		{
+			description: "delete replica",
+			update:      &dynamodb.ReplicationGroupUpdate{Delete: &dynamodb.DeleteReplicationGroupMemberAction{RegionName: aws.String("eu-west-1")}},
+			expect:      []string{"us-east-1"},
+		},
+		{
+			description: "missing replica",
+			update:      &dynamodb.ReplicationGroupUpdate{Delete: &dynamodb.DeleteReplicationGroupMemberAction{RegionName: aws.String("eu-west-1")}},
+			code:        "ValidationException",
+		},
+	}
+	for _, useCase := range useCases {
+		_, err := db.UpdateTable(&dynamodb.UpdateTableInput{TableName: aws.String("users"), ReplicaUpdates: []*dynamodb.ReplicationGroupUpdate{useCase.update}})
+		if useCase.code != "" {
+			assert.EqualValues(t, useCase.code, errorCode(err), useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		described, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("users")})
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		var regions = make([]string, 0)
+		for _, replica := range described.Table.Replicas {
+			regions = append(regions, *replica.RegionName)
+			assert.EqualValues(t, dynamodb.ReplicaStatusActive, *replica.ReplicaStatus, useCase.description)
+		}
+		assert.EqualValues(t, useCase.expect, regions, useCase.description)
+	}
+}
+
+func TestClose(t *testing.T) {
+	URL, err := memdb.Endpoint("close")
+	if !assert.Nil(t, err) {
+		return
+	}
+	same, _ := memdb.Endpoint("close")
+	assert.EqualValues(t, URL, same)
+	assert.Nil(t, memdb.Close("close"))
+	_, err = memdb.Endpoint("close")
+	assert.Nil(t, err)
+	assert.Nil(t, memdb.Close("close"))
+	assert.Nil(t, memdb.Close("close"))
+}
@@ -0,0 +1,529 @@
+package memdb
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"sort"
+	"time"
+)
+
+//index represents table primary key or secondary index
+type index struct {
+	name       string
+	hash       string
+	rangeKey   string
+	projection *dynamodb.Projection
+	global     bool
+}
+
+//keyNames returns index key attribute names
+func (i *index) keyNames() []string {
+	if i.rangeKey == "" {
+		return []string{i.hash}
+	}
+	return []string{i.hash, i.rangeKey}
+}
+
+//table represents in-memory table with its items indexed by encoded primary key
+type table struct {
+	description    *dynamodb.TableDescription
+	attributeTypes map[string]string
+	primary        *index
+	indexes        map[string]*index
+	items          map[string]item
+	ttl            *dynamodb.TimeToLiveDescription
+	tags           []*dynamodb.Tag
+	recovery       *dynamodb.PointInTimeRecoveryDescription
+}
+
+//keyOf returns encoded primary key, key attributes have to match defined types
+func (t *table) keyOf(values item) (string, error) {
+	for _, name := range t.primary.keyNames() {
+		value, ok := values[name]
+		if !ok {
+			return "", validationError("one or more parameter values were invalid: missing the key %v in the item", name)
+		}
+		if actual := typeOf(value); actual != t.attributeTypes[name] {
+			return "", validationError("one or more parameter values were invalid: type mismatch for key %v expected: %v actual: %v", name, t.attributeTypes[name], actual)
+		}
+	}
+	return encodeKey(values, t.primary.keyNames())
+}
+
+//checkKey checks that key has exactly primary key attributes
+func (t *table) checkKey(key item) (string, error) {
+	if len(key) != len(t.primary.keyNames()) {
+		return "", validationError("the provided key element does not match the schema")
+	}
+	return t.keyOf(key)
+}
+
+//checkIndexKeys checks that item secondary index key attributes have defined types
+func (t *table) checkIndexKeys(values item) error {
+	for _, candidate := range t.indexes {
+		for _, name := range candidate.keyNames() {
+			value, ok := values[name]
+			if !ok {
+				continue
+			}
+			if actual := typeOf(value); actual != t.attributeTypes[name] {
+				return validationError("one or more parameter values were invalid: type mismatch for index key %v expected: %v actual: %v IndexName: %v", name, t.attributeTypes[name], actual, candidate.name)
+			}
+		}
+	}
+	return nil
+}
+
+//index returns named secondary index or primary key for empty name
+func (t *table) index(name *string) (*index, error) {
+	if name == nil || *name == "" {
+		return t.primary, nil
+	}
+	result, ok := t.indexes[*name]
+	if !ok {
+		return nil, validationError("the table does not have the specified index: %v", *name)
+	}
+	return result, nil
+}
+
+//orderNames returns attribute names defining item order within index
+func (t *table) orderNames(view *index) []string {
+	var result = view.keyNames()
+	for _, name := range t.primary.keyNames() {
+		if name != view.hash && name != view.rangeKey {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+//compareOrder compares items or keys by index order
+func compareOrder(names []string, left, right item) int {
+	for _, name := range names {
+		if result, ok := compare(left[name], right[name]); ok && result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+//sorted returns items having index key attributes sorted by index order, secondary indexes are sparse
+func (t *table) sorted(view *index) []item {
+	var result = make([]item, 0, len(t.items))
+	for _, candidate := range t.items {
+		if candidate[view.hash] == nil || (view.rangeKey != "" && candidate[view.rangeKey] == nil) {
+			continue
+		}
+		result = append(result, candidate)
+	}
+	names := t.orderNames(view)
+	sort.Slice(result, func(i, j int) bool {
+		return compareOrder(names, result[i], result[j]) < 0
+	})
+	return result
+}
+
+//project returns item attributes available in index
+func (t *table) project(view *index, values item) item {
+	if view.projection == nil || view.projection.ProjectionType == nil || *view.projection.ProjectionType == dynamodb.ProjectionTypeAll {
+		return values
+	}
+	var result = make(item)
+	for _, name := range t.orderNames(view) {
+		result[name] = values[name]
+	}
+	if *view.projection.ProjectionType == dynamodb.ProjectionTypeInclude {
+		for _, name := range view.projection.NonKeyAttributes {
+			if value, ok := values[*name]; ok {
+				result[*name] = value
+			}
+		}
+	}
+	return result
+}
+
+//describe returns table description with current item count and size
+func (t *table) describe() *dynamodb.TableDescription {
+	description := *t.description
+	var count, size int64
+	for _, candidate := range t.items {
+		count++
+		size += int64(itemSize(candidate))
+	}
+	description.ItemCount, description.TableSizeBytes = &count, &size
+	return &description
+}
+
+func (s *Server) table(name *string) (*table, error) {
+	if name == nil {
+		return nil, validationError("table name was empty")
+	}
+	result, ok := s.tables[*name]
+	if !ok {
+		return nil, newError(errCodeNotFound, "requested resource not found: Table: %v not found", *name)
+	}
+	return result, nil
+}
+
+//tableByARN returns table for table ARN or name
+func (s *Server) tableByARN(resourceARN *string) (*table, error) {
+	if resourceARN == nil {
+		return nil, validationError("resource ARN was empty")
+	}
+	for _, candidate := range s.tables {
+		if *candidate.description.TableArn == *resourceARN || *candidate.description.TableName == *resourceARN {
+			return candidate, nil
+		}
+	}
+	return nil, newError(errCodeNotFound, "requested resource not found: %v", *resourceARN)
+}
+
+//newIndex returns index for key schema
+func newIndex(name string, schema []*dynamodb.KeySchemaElement, projection *dynamodb.Projection, attributeTypes map[string]string) (*index, error) {
+	result := &index{name: name, projection: projection}
+	for _, element := range schema {
+		if element.AttributeName == nil || element.KeyType == nil {
+			return nil, validationError("invalid key schema: %v", schema)
+		}
+		switch *element.KeyType {
+		case dynamodb.KeyTypeHash:
+			result.hash = *element.AttributeName
+		case dynamodb.KeyTypeRange:
+			result.rangeKey = *element.AttributeName
+		default:
+			return nil, validationError("invalid KeyType: %v", *element.KeyType)
+		}
+	}
+	if result.hash == "" || len(schema) > 2 {
+		return nil, validationError("invalid KeySchema: some index key attribute have no definition or key schema has more than one HASH or RANGE key")
+	}
+	for _, keyName := range result.keyNames() {
+		switch attributeTypes[keyName] {
+		case dynamodb.ScalarAttributeTypeS, dynamodb.ScalarAttributeTypeN, dynamodb.ScalarAttributeTypeB:
+		default:
+			return nil, validationError("one or more parameter values were invalid: some index key attributes are not defined in AttributeDefinitions: %v", keyName)
+		}
+	}
+	return result, nil
+}
+
+//CreateTable creates ACTIVE table with secondary indexes
+func (s *Server) CreateTable(input *dynamodb.CreateTableInput) (*dynamodb.CreateTableOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if input.TableName == nil || *input.TableName == "" {
+		return nil, validationError("table name was empty")
+	}
+	name := *input.TableName
+	if _, ok := s.tables[name]; ok {
+		return nil, newError(errCodeInUse, "table already exists: %v", name)
+	}
+	var attributeTypes = make(map[string]string)
+	for _, definition := range input.AttributeDefinitions {
+		if definition.AttributeName == nil || definition.AttributeType == nil {
+			return nil, validationError("invalid attribute definition: %v", definition)
+		}
+		attributeTypes[*definition.AttributeName] = *definition.AttributeType
+	}
+	primary, err := newIndex("", input.KeySchema, nil, attributeTypes)
+	if err != nil {
+		return nil, err
+	}
+	billingMode := dynamodb.BillingModeProvisioned
+	if input.BillingMode != nil {
+		billingMode = *input.BillingMode
+	}
+	throughput, err := throughputDescription(billingMode, input.ProvisionedThroughput)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	arn := arnPrefix + name
+	description := &dynamodb.TableDescription{
+		TableName:             stringPointer(name),
+		TableArn:              stringPointer(arn),
+		TableId:               stringPointer(name),
+		TableStatus:           stringPointer(dynamodb.TableStatusActive),
+		CreationDateTime:      &now,
+		KeySchema:             input.KeySchema,
+		AttributeDefinitions:  input.AttributeDefinitions,
+		ProvisionedThroughput: throughput,
+		BillingModeSummary:    &dynamodb.BillingModeSummary{BillingMode: stringPointer(billingMode)},
+	}
+	result := &table{description: description, attributeTypes: attributeTypes, primary: primary, indexes: make(map[string]*index), items: make(map[string]item), tags: input.Tags}
+	for _, definition := range input.GlobalSecondaryIndexes {
+		secondary, err := newIndex(stringValue(definition.IndexName), definition.KeySchema, definition.Projection, attributeTypes)
+		if err != nil {
+			return nil, err
+		}
+		indexThroughput, err := throughputDescription(billingMode, definition.ProvisionedThroughput)
+		if err != nil {
+			return nil, err
+		}
+		secondary.global = true
+		result.indexes[secondary.name] = secondary
+		description.GlobalSecondaryIndexes = append(description.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndexDescription{
+			IndexName:             definition.IndexName,
+			IndexArn:              stringPointer(arn + "/index/" + secondary.name),
+			IndexStatus:           stringPointer(dynamodb.IndexStatusActive),
+			KeySchema:             definition.KeySchema,
+			Projection:            definition.Projection,
+			ProvisionedThroughput: indexThroughput,
+		})
+	}
+	for _, definition := range input.LocalSecondaryIndexes {
+		secondary, err := newIndex(stringValue(definition.IndexName), definition.KeySchema, definition.Projection, attributeTypes)
+		if err != nil {
+			return nil, err
+		}
+		if secondary.hash != primary.hash {
+			return nil, validationError("local secondary index %v has to use table hash key %v", secondary.name, primary.hash)
+		}
+		result.indexes[secondary.name] = secondary
+		description.LocalSecondaryIndexes = append(description.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndexDescription{
+			IndexName:  definition.IndexName,
+			IndexArn:   stringPointer(arn + "/index/" + secondary.name),
+			KeySchema:  definition.KeySchema,
+			Projection: definition.Projection,
+		})
+	}
+	if stream := input.StreamSpecification; stream != nil && stream.StreamEnabled != nil && *stream.StreamEnabled {
+		label := now.UTC().Format("2006-01-02T15:04:05.000")
+		description.StreamSpecification = stream
+		description.LatestStreamLabel = stringPointer(label)
+		description.LatestStreamArn = stringPointer(arn + "/stream/" + label)
+	}
+	if sse := input.SSESpecification; sse != nil && sse.Enabled != nil && *sse.Enabled {
+		description.SSEDescription = &dynamodb.SSEDescription{Status: stringPointer(dynamodb.SSEStatusEnabled), SSEType: sse.SSEType, KMSMasterKeyArn: sse.KMSMasterKeyId}
+	}
+	if input.TableClass != nil {
+		description.TableClassSummary = &dynamodb.TableClassSummary{TableClass: input.TableClass}
+	}
+	s.tables[name] = result
+	return &dynamodb.CreateTableOutput{TableDescription: result.describe()}, nil
+}
+
+//throughputDescription returns provisioned throughput description, on-demand tables report zero capacity
+func throughputDescription(billingMode string, throughput *dynamodb.ProvisionedThroughput) (*dynamodb.ProvisionedThroughputDescription, error) {
+	var zero int64
+	result := &dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: &zero, WriteCapacityUnits: &zero, NumberOfDecreasesToday: &zero}
+	switch billingMode {
+	case dynamodb.BillingModePayPerRequest:
+		if throughput != nil {
+			return nil, validationError("one or more parameter values were invalid: neither ReadCapacityUnits nor WriteCapacityUnits can be specified when BillingMode is PAY_PER_REQUEST")
+		}
+	case dynamodb.BillingModeProvisioned:
+		if throughput == nil || throughput.ReadCapacityUnits == nil || throughput.WriteCapacityUnits == nil {
+			return nil, validationError("one or more parameter values were invalid: ReadCapacityUnits and WriteCapacityUnits must both be specified when BillingMode is PROVISIONED")
+		}
+		result.ReadCapacityUnits, result.WriteCapacityUnits = throughput.ReadCapacityUnits, throughput.WriteCapacityUnits
+	default:
+		return nil, validationError("invalid BillingMode: %v", billingMode)
+	}
+	return result, nil
+}
+
+func stringValue(text *string) string {
+	if text == nil {
+		return ""
+	}
+	return *text
+}
+
+//DescribeTable returns table description
+func (s *Server) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.DescribeTableOutput{Table: target.describe()}, nil
+}
+
+//DeleteTable deletes table with its items immediately
+func (s *Server) DeleteTable(input *dynamodb.DeleteTableInput) (*dynamodb.DeleteTableOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.tables, *input.TableName)
+	description := target.describe()
+	description.TableStatus = stringPointer(dynamodb.TableStatusDeleting)
+	return &dynamodb.DeleteTableOutput{TableDescription: description}, nil
+}
+
+//UpdateTable creates or deletes global table replicas, replicas become ACTIVE immediately
+func (s *Server) UpdateTable(input *dynamodb.UpdateTableInput) (*dynamodb.UpdateTableOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	var replicas = append([]*dynamodb.ReplicaDescription{}, target.description.Replicas...)
+	for _, update := range input.ReplicaUpdates {
+		switch {
+		case update.Create != nil:
+			region := stringValue(update.Create.RegionName)
+			if region == "" {
+				return nil, validationError("replica region name was empty")
+			}
+			if replicaIndex(replicas, region) != -1 {
+				return nil, validationError("one or more parameter values were invalid: replica %v of table %v already exists", region, *input.TableName)
+			}
+			replicas = append(replicas, &dynamodb.ReplicaDescription{RegionName: stringPointer(region), ReplicaStatus: stringPointer(dynamodb.ReplicaStatusActive)})
+		case update.Delete != nil:
+			region := stringValue(update.Delete.RegionName)
+			index := replicaIndex(replicas, region)
+			if index == -1 {
+				return nil, validationError("one or more parameter values were invalid: replica %v of table %v does not exist", region, *input.TableName)
+			}
+			replicas = append(replicas[:index], replicas[index+1:]...)
+		default:
+			return nil, validationError("unsupported replica update: %v", update)
+		}
+	}
+	target.description.Replicas = replicas
+	if len(replicas) > 0 {
+		target.description.GlobalTableVersion = stringPointer("2019.11.21")
+	} else {
+		target.description.GlobalTableVersion = nil
+	}
+	return &dynamodb.UpdateTableOutput{TableDescription: target.describe()}, nil
+}
+
+func replicaIndex(replicas []*dynamodb.ReplicaDescription, region string) int {
+	for i, replica := range replicas {
+		if stringValue(replica.RegionName) == region {
+			return i
+		}
+	}
+	return -1
+}
+
+//ListTables returns sorted table names page
+func (s *Server) ListTables(input *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var names = make([]string, 0, len(s.tables))
+	for name := range s.tables {
+		if input.ExclusiveStartTableName == nil || name > *input.ExclusiveStartTableName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var limit = 100
+	if input.Limit != nil && *input.Limit > 0 && *input.Limit < 100 {
+		limit = int(*input.Limit)
+	}
+	output := &dynamodb.ListTablesOutput{TableNames: make([]*string, 0)}
+	for i, name := range names {
+		if i == limit {
+			output.LastEvaluatedTableName = output.TableNames[i-1]
+			break
+		}
+		output.TableNames = append(output.TableNames, stringPointer(name))
+	}
+	return output, nil
+}
+
+//UpdateTimeToLive enables or disables table TTL, expired items are not removed
+func (s *Server) UpdateTimeToLive(input *dynamodb.UpdateTimeToLiveInput) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	specification := input.TimeToLiveSpecification
+	if specification == nil || specification.AttributeName == nil || specification.Enabled == nil {
+		return nil, validationError("TimeToLiveSpecification was empty")
+	}
+	status := dynamodb.TimeToLiveStatusDisabled
+	if *specification.Enabled {
+		status = dynamodb.TimeToLiveStatusEnabled
+	}
+	target.ttl = &dynamodb.TimeToLiveDescription{AttributeName: specification.AttributeName, TimeToLiveStatus: stringPointer(status)}
+	return &dynamodb.UpdateTimeToLiveOutput{TimeToLiveSpecification: specification}, nil
+}
+
+//DescribeTimeToLive returns table TTL status
+func (s *Server) DescribeTimeToLive(input *dynamodb.DescribeTimeToLiveInput) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	description := target.ttl
+	if description == nil {
+		description = &dynamodb.TimeToLiveDescription{TimeToLiveStatus: stringPointer(dynamodb.TimeToLiveStatusDisabled)}
+	}
+	return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: description}, nil
+}
+
+//TagResource adds or replaces table tags
+func (s *Server) TagResource(input *dynamodb.TagResourceInput) (*dynamodb.TagResourceOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.tableByARN(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range input.Tags {
+		if tag.Key == nil || tag.Value == nil {
+			return nil, validationError("invalid tag: %v", tag)
+		}
+		replaced := false
+		for _, existing := range target.tags {
+			if *existing.Key == *tag.Key {
+				existing.Value, replaced = tag.Value, true
+			}
+		}
+		if !replaced {
+			target.tags = append(target.tags, &dynamodb.Tag{Key: tag.Key, Value: tag.Value})
+		}
+	}
+	return &dynamodb.TagResourceOutput{}, nil
+}
+
+//UntagResource removes table tags
+func (s *Server) UntagResource(input *dynamodb.UntagResourceInput) (*dynamodb.UntagResourceOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.tableByARN(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	var tags = make([]*dynamodb.Tag, 0, len(target.tags))
+	for _, tag := range target.tags {
+		removed := false
+		for _, key := range input.TagKeys {
+			if key != nil && *key == *tag.Key {
+				removed = true
+			}
+		}
+		if !removed {
+			tags = append(tags, tag)
+		}
+	}
+	target.tags = tags
+	return &dynamodb.UntagResourceOutput{}, nil
+}
+
+//ListTagsOfResource returns table tags
+func (s *Server) ListTagsOfResource(input *dynamodb.ListTagsOfResourceInput) (*dynamodb.ListTagsOfResourceOutput, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	target, err := s.tableByARN(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	var tags = make([]*dynamodb.Tag, 0, len(target.tags))
+	for _, tag := range target.tags {
+		tags = append(tags, &dynamodb.Tag{Key: tag.Key, Value: tag.Value})
+	}
+	return &dynamodb.ListTagsOfResourceOutput{Tags: tags}, nil
+}
@@ -0,0 +1,365 @@
+package memdb
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+//item represents stored item
+type item = map[string]*dynamodb.AttributeValue
+
+//typeOf returns attribute value type descriptor i.e. S, N, B, SS, NS, BS, M, L, NULL or BOOL
+func typeOf(value *dynamodb.AttributeValue) string {
+	switch {
+	case value == nil:
+		return ""
+	case value.S != nil:
+		return dynamodb.ScalarAttributeTypeS
+	case value.N != nil:
+		return dynamodb.ScalarAttributeTypeN
+	case value.B != nil:
+		return dynamodb.ScalarAttributeTypeB
+	case value.SS != nil:
+		return "SS"
+	case value.NS != nil:
+		return "NS"
+	case value.BS != nil:
+		return "BS"
+	case value.M != nil:
+		return "M"
+	case value.L != nil:
+		return "L"
+	case value.NULL != nil:
+		return "NULL"
+	case value.BOOL != nil:
+		return "BOOL"
+	}
+	return ""
+}
+
+//parseNumber parses DynamoDB number as exact decimal
+func parseNumber(text string) (*big.Rat, error) {
+	result, ok := new(big.Rat).SetString(strings.TrimSpace(text))
+	if !ok {
+		return nil, validationError("invalid number: %v", text)
+	}
+	return result, nil
+}
+
+//formatNumber returns the shortest decimal representation of a number
+func formatNumber(number *big.Rat) string {
+	if number.IsInt() {
+		return number.Num().String()
+	}
+	scaled := new(big.Rat).Set(number)
+	ten := big.NewRat(10, 1)
+	for scale := 1; scale <= 38; scale++ {
+		scaled.Mul(scaled, ten)
+		if scaled.IsInt() {
+			return number.FloatString(scale)
+		}
+	}
+	return strings.TrimRight(number.FloatString(38), "0")
+}
+
+//normalizeNumber returns canonical number text so that 1, 1.0 and 1e0 are equal keys
+func normalizeNumber(text string) (string, error) {
+	number, err := parseNumber(text)
+	if err != nil {
+		return "", err
+	}
+	return formatNumber(number), nil
+}
+
+//compare compares scalar values of the same type, it returns false if values are not comparable
+func compare(left, right *dynamodb.AttributeValue) (int, bool) {
+	leftType, rightType := typeOf(left), typeOf(right)
+	if leftType != rightType {
+		return 0, false
+	}
+	switch leftType {
+	case dynamodb.ScalarAttributeTypeS:
+		return strings.Compare(*left.S, *right.S), true
+	case dynamodb.ScalarAttributeTypeB:
+		return bytes.Compare(left.B, right.B), true
+	case dynamodb.ScalarAttributeTypeN:
+		leftNumber, err := parseNumber(*left.N)
+		if err != nil {
+			return 0, false
+		}
+		rightNumber, err := parseNumber(*right.N)
+		if err != nil {
+			return 0, false
+		}
+		return leftNumber.Cmp(rightNumber), true
+	}
+	return 0, false
+}
+
+//equal returns true if values are deeply equal, sets are compared regardless of order
+func equal(left, right *dynamodb.AttributeValue) bool {
+	leftType, rightType := typeOf(left), typeOf(right)
+	if leftType != rightType {
+		return false
+	}
+	switch leftType {
+	case dynamodb.ScalarAttributeTypeS, dynamodb.ScalarAttributeTypeN, dynamodb.ScalarAttributeTypeB:
+		result, ok := compare(left, right)
+		return ok && result == 0
+	case "BOOL":
+		return *left.BOOL == *right.BOOL
+	case "NULL":
+		return true
+	case "SS", "NS", "BS":
+		leftMembers, rightMembers := setMembers(left), setMembers(right)
+		if len(leftMembers) != len(rightMembers) {
+			return false
+		}
+		for _, member := range leftMembers {
+			if indexOf(rightMembers, member) == -1 {
+				return false
+			}
+		}
+		return true
+	case "L":
+		if len(left.L) != len(right.L) {
+			return false
+		}
+		for i := range left.L {
+			if !equal(left.L[i], right.L[i]) {
+				return false
+			}
+		}
+		return true
+	case "M":
+		if len(left.M) != len(right.M) {
+			return false
+		}
+		for key, value := range left.M {
+			if !equal(value, right.M[key]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+//setMembers returns set members as scalar values
+func setMembers(value *dynamodb.AttributeValue) []*dynamodb.AttributeValue {
+	var result = make([]*dynamodb.AttributeValue, 0)
+	for _, member := range value.SS {
+		result = append(result, &dynamodb.AttributeValue{S: member})
+	}
+	for _, member := range value.NS {
+		result = append(result, &dynamodb.AttributeValue{N: member})
+	}
+	for _, member := range value.BS {
+		result = append(result, &dynamodb.AttributeValue{B: member})
+	}
+	return result
+}
+
+//newSet returns set of supplied type with scalar members
+func newSet(setType string, members []*dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	result := &dynamodb.AttributeValue{}
+	for _, member := range members {
+		switch setType {
+		case "SS":
+			result.SS = append(result.SS, member.S)
+		case "NS":
+			result.NS = append(result.NS, member.N)
+		case "BS":
+			result.BS = append(result.BS, member.B)
+		}
+	}
+	return result
+}
+
+func indexOf(values []*dynamodb.AttributeValue, value *dynamodb.AttributeValue) int {
+	for i, candidate := range values {
+		if equal(candidate, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+//size returns DynamoDB size() function result
+func size(value *dynamodb.AttributeValue) (int, bool) {
+	switch typeOf(value) {
+	case dynamodb.ScalarAttributeTypeS:
+		return len(*value.S), true
+	case dynamodb.ScalarAttributeTypeB:
+		return len(value.B), true
+	case "SS", "NS", "BS":
+		return len(setMembers(value)), true
+	case "L":
+		return len(value.L), true
+	case "M":
+		return len(value.M), true
+	}
+	return 0, false
+}
+
+//itemSize returns approximate item size in bytes
+func itemSize(values item) int {
+	var result int
+	for name, value := range values {
+		result += len(name) + valueSize(value)
+	}
+	return result
+}
+
+func valueSize(value *dynamodb.AttributeValue) int {
+	switch typeOf(value) {
+	case dynamodb.ScalarAttributeTypeS:
+		return len(*value.S)
+	case dynamodb.ScalarAttributeTypeN:
+		return len(*value.N)
+	case dynamodb.ScalarAttributeTypeB:
+		return len(value.B)
+	case "SS", "NS", "BS":
+		var result int
+		for _, member := range setMembers(value) {
+			result += valueSize(member)
+		}
+		return result
+	case "L":
+		var result = 3
+		for _, element := range value.L {
+			result += 1 + valueSize(element)
+		}
+		return result
+	case "M":
+		var result = 3
+		for key, element := range value.M {
+			result += 1 + len(key) + valueSize(element)
+		}
+		return result
+	}
+	return 1
+}
+
+//cloneValue returns deep copy of a value
+func cloneValue(value *dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	if value == nil {
+		return nil
+	}
+	result := *value
+	if value.L != nil {
+		result.L = make([]*dynamodb.AttributeValue, len(value.L))
+		for i, element := range value.L {
+			result.L[i] = cloneValue(element)
+		}
+	}
+	if value.M != nil {
+		result.M = cloneItem(value.M)
+	}
+	if value.SS != nil {
+		result.SS = append([]*string{}, value.SS...)
+	}
+	if value.NS != nil {
+		result.NS = append([]*string{}, value.NS...)
+	}
+	if value.BS != nil {
+		result.BS = append([][]byte{}, value.BS...)
+	}
+	return &result
+}
+
+//cloneItem returns deep copy of an item
+func cloneItem(values item) item {
+	if values == nil {
+		return nil
+	}
+	var result = make(item, len(values))
+	for name, value := range values {
+		result[name] = cloneValue(value)
+	}
+	return result
+}
+
+//validateValue checks that value has exactly one type, numbers are valid and sets are non empty without duplicates
+func validateValue(value *dynamodb.AttributeValue) error {
+	if value == nil {
+		return validationError("attribute value was empty")
+	}
+	var types int
+	for _, isSet := range []bool{value.S != nil, value.N != nil, value.B != nil, value.SS != nil, value.NS != nil, value.BS != nil, value.M != nil, value.L != nil, value.NULL != nil, value.BOOL != nil} {
+		if isSet {
+			types++
+		}
+	}
+	if types != 1 {
+		return validationError("supplied AttributeValue has %v data types, expected exactly one", types)
+	}
+	switch typeOf(value) {
+	case dynamodb.ScalarAttributeTypeN:
+		_, err := parseNumber(*value.N)
+		return err
+	case "SS", "NS", "BS":
+		members := setMembers(value)
+		if len(members) == 0 {
+			return validationError("one or more parameter values were invalid: an string set may not be empty")
+		}
+		for i, member := range members {
+			if err := validateValue(member); err != nil {
+				return err
+			}
+			if indexOf(members[:i], member) != -1 {
+				return validationError("one or more parameter values were invalid: input collection contains duplicates")
+			}
+		}
+	case "L":
+		for _, element := range value.L {
+			if err := validateValue(element); err != nil {
+				return err
+			}
+		}
+	case "M":
+		for _, element := range value.M {
+			if err := validateValue(element); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//encodeKey returns key text used to index items, numbers are normalized
+func encodeKey(values item, names []string) (string, error) {
+	var result = make([]string, 0, len(names))
+	for _, name := range names {
+		value := values[name]
+		switch typeOf(value) {
+		case dynamodb.ScalarAttributeTypeS:
+			result = append(result, "S"+*value.S)
+		case dynamodb.ScalarAttributeTypeN:
+			number, err := normalizeNumber(*value.N)
+			if err != nil {
+				return "", err
+			}
+			result = append(result, "N"+number)
+		case dynamodb.ScalarAttributeTypeB:
+			result = append(result, fmt.Sprintf("B%x", value.B))
+		default:
+			return "", validationError("one or more parameter values were invalid: missing the key %v in the item", name)
+		}
+	}
+	return strings.Join(result, "\x00"), nil
+}
+
+//sortedNames returns sorted map keys
+func sortedNames(values item) []string {
+	var result = make([]string, 0, len(values))
+	for name := range values {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,116 @@
+package dyndb
+
+import (
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"regexp"
+	"strings"
+)
+
+const (
+	//tablePrefixKey config parameter prepended to table names i.e. dev_, so that the same SQL runs in every environment
+	tablePrefixKey = "tablePrefix"
+	//tableSuffixKey config parameter appended to table names i.e. -staging
+	tableSuffixKey = "tableSuffix"
+	//tableMappingKey config parameter renames tables before prefix and suffix are applied, it takes a map or logical=physical pairs separated with ; i.e. users=app_users;orders=app_orders
+	tableMappingKey = "tableMapping"
+)
+
+//partiQLTableExpr matches table name following FROM, INTO or UPDATE keyword in PartiQL statement
+var partiQLTableExpr = regexp.MustCompile(`(?i)\b(FROM|INTO|UPDATE)(\s+)("[^"]+"|[A-Za-z_][A-Za-z0-9_.\-]*)`)
+
+//tableNamespace maps logical table names used in SQL and dialect calls to physical DynamoDB table names
+type tableNamespace struct {
+	prefix  string
+	suffix  string
+	mapping map[string]string
+}
+
+//isEmpty returns true if table names are used verbatim
+func (n *tableNamespace) isEmpty() bool {
+	return n.prefix == "" && n.suffix == "" && len(n.mapping) == 0
+}
+
+//physical returns DynamoDB table name for logical table name
+func (n *tableNamespace) physical(table string) string {
+	if mapped, ok := n.mapping[table]; ok {
+		table = mapped
+	}
+	return n.prefix + table + n.suffix
+}
+
+//logical returns logical table name for DynamoDB table name, false if table is outside the namespace
+func (n *tableNamespace) logical(table string) (string, bool) {
+	if !strings.HasPrefix(table, n.prefix) || !strings.HasSuffix(table, n.suffix) || len(table) <= len(n.prefix)+len(n.suffix) {
+		return "", false
+	}
+	table = table[len(n.prefix) : len(table)-len(n.suffix)]
+	for logical, physical := range n.mapping {
+		if physical == table {
+			return logical, true
+		}
+	}
+	if _, ok := n.mapping[table]; ok { //renamed table is only visible under its mapped name
+		return "", false
+	}
+	return table, true
+}
+
+//partiQL returns PartiQL statement with physical table names
+func (n *tableNamespace) partiQL(statement string) string {
+	if n.isEmpty() {
+		return statement
+	}
+	fragments := strings.Split(statement, "'") //odd fragments are string literals
+	for i := 0; i < len(fragments); i += 2 {
+		fragments[i] = partiQLTableExpr.ReplaceAllStringFunc(fragments[i], n.partiQLTable)
+	}
+	return strings.Join(fragments, "'")
+}
+
+//partiQLTable returns PartiQL table reference match with physical table name
+func (n *tableNamespace) partiQLTable(match string) string {
+	parts := partiQLTableExpr.FindStringSubmatch(match)
+	table, index := parts[3], ""
+	if strings.HasPrefix(table, `"`) {
+		table = strings.Trim(table, `"`)
+	} else if position := strings.Index(table, "."); position != -1 {
+		table, index = table[:position], table[position:]
+	}
+	return parts[1] + parts[2] + `"` + n.physical(table) + `"` + index
+}
+
+//newTableNamespace returns table namespace for tablePrefix, tableSuffix and tableMapping config parameters
+func newTableNamespace(config *dsc.Config) *tableNamespace {
+	result := &tableNamespace{
+		prefix:  config.Get(tablePrefixKey),
+		suffix:  config.Get(tableSuffixKey),
+		mapping: make(map[string]string),
+	}
+	if !config.Has(tableMappingKey) {
+		return result
+	}
+	value := config.Parameters[tableMappingKey]
+	if toolbox.IsMap(value) {
+		for logical, physical := range toolbox.AsMap(value) {
+			result.mapping[logical] = toolbox.AsString(physical)
+		}
+		return result
+	}
+	for _, pair := range strings.Split(toolbox.AsString(value), ";") {
+		if index := strings.Index(pair, "="); index != -1 {
+			result.mapping[strings.TrimSpace(pair[:index])] = strings.TrimSpace(pair[index+1:])
+		}
+	}
+	return result
+}
+
+//tableName returns DynamoDB table name for logical table name
+func tableName(config *dsc.Config, table string) string {
+	return newTableNamespace(config).physical(table)
+}
+
+//tableName returns DynamoDB table name for logical table name
+func (m *manager) tableName(table string) string {
+	return tableName(m.Config(), table)
+}
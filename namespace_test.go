@@ -0,0 +1,156 @@
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"sort"
+	"testing"
+)
+
+func TestTableNamespace(t *testing.T) {
+	var useCases = []struct {
+		description string
+		parameters  map[string]interface{}
+		table       string
+		physical    string
+		hidden      []string
+	}{
+		{
+			description: "verbatim table names",
+			parameters:  map[string]interface{}{},
+			table:       "music",
+			physical:    "music",
+		},
+		{
+			description: "prefix and suffix",
+			parameters:  map[string]interface{}{"tablePrefix": "dev_", "tableSuffix": "-v2"},
+			table:       "music",
+			physical:    "dev_music-v2",
+			hidden:      []string{"music", "prod_music-v2", "dev_-v2"},
+		},
+		{
+			description: "mapping pairs",
+			parameters:  map[string]interface{}{"tablePrefix": "dev_", "tableMapping": "music=songs; users=people"},
+			table:       "music",
+			physical:    "dev_songs",
+			hidden:      []string{"dev_music"},
+		},
+		{
+			description: "mapping map",
+			parameters:  map[string]interface{}{"tableMapping": map[string]interface{}{"music": "songs"}},
+			table:       "music",
+			physical:    "songs",
+		},
+	}
+	for _, useCase := range useCases {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", useCase.parameters)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		namespace := newTableNamespace(config)
+		assert.EqualValues(t, useCase.physical, namespace.physical(useCase.table), useCase.description)
+		logical, ok := namespace.logical(useCase.physical)
+		assert.True(t, ok, useCase.description)
+		assert.EqualValues(t, useCase.table, logical, useCase.description)
+		for _, table := range useCase.hidden {
+			_, ok := namespace.logical(table)
+			assert.False(t, ok, useCase.description+" "+table)
+		}
+	}
+}
+
+func TestTableNamespace_PartiQL(t *testing.T) {
+	namespace := &tableNamespace{prefix: "dev_", mapping: map[string]string{"users": "people"}}
+	var useCases = []struct {
+		description string
+		statement   string
+		expect      string
+	}{
+		{
+			description: "quoted table with index",
+			statement:   `SELECT * FROM "music"."genre" WHERE Genre = ?`,
+			expect:      `SELECT * FROM "dev_music"."genre" WHERE Genre = ?`,
+		},
+		{
+			description: "unquoted table",
+			statement:   `select * from music.genre where Genre = ?`,
+			expect:      `select * from "dev_music".genre where Genre = ?`,
+		},
+		{
+			description: "insert, update and delete",
+			statement:   `INSERT INTO users VALUE {'Id': ?}; UPDATE "users" SET Name = ? WHERE Id = ?; DELETE FROM users WHERE Id = ?`,
+			expect:      `INSERT INTO "dev_people" VALUE {'Id': ?}; UPDATE "dev_people" SET Name = ? WHERE Id = ?; DELETE FROM "dev_people" WHERE Id = ?`,
+		},
+		{
+			description: "string literal",
+			statement:   `UPDATE music SET Note = 'copied from music' WHERE Artist = ?`,
+			expect:      `UPDATE "dev_music" SET Note = 'copied from music' WHERE Artist = ?`,
+		},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, namespace.partiQL(useCase.statement), useCase.description)
+	}
+	assert.EqualValues(t, `SELECT * FROM music`, (&tableNamespace{}).partiQL(`SELECT * FROM music`))
+}
+
+func TestTableNamespace_Manager(t *testing.T) {
+	var managers = make(map[string]dsc.Manager)
+	for _, env := range []string{"dev", "prod"} {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+			"endpoint":     "memory://namespace",
+			"region":       "us-west-1",
+			"tablePrefix":  env + "_",
+			"tableMapping": "users=people",
+		})
+		if !assert.Nil(t, err) {
+			return
+		}
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err) {
+			return
+		}
+		managers[env] = manager
+		for _, SQL := range []string{
+			"CREATE TABLE IF NOT EXISTS music(Artist VARCHAR(255) HASH KEY, SongTitle VARCHAR(255) RANGE KEY)",
+			"CREATE TABLE IF NOT EXISTS users(Id VARCHAR(255) HASH KEY)",
+			"TRUNCATE TABLE music",
+		} {
+			_, err = manager.Execute(SQL)
+			if !assert.Nil(t, err, SQL) {
+				return
+			}
+		}
+		_, err = manager.Execute("INSERT INTO music(Artist, SongTitle, Env) VALUES(?, ?, ?)", "Artist0", "Title0", env)
+		assert.Nil(t, err)
+	}
+	dialect := dsc.GetDatastoreDialect("dyndb")
+	for env, manager := range managers {
+		tables, err := dialect.GetTables(manager, "")
+		if assert.Nil(t, err, env) {
+			sort.Strings(tables)
+			assert.EqualValues(t, []string{"music", "users"}, tables, env)
+		}
+		assert.EqualValues(t, "Artist,SongTitle", dialect.GetKeyName(manager, "", "music"), env)
+		assert.EqualValues(t, "Id", dialect.GetKeyName(manager, "", "users"), env)
+
+		var records = make([]map[string]interface{}, 0)
+		err = manager.ReadAll(&records, "SELECT Env FROM music WHERE Artist = ?", []interface{}{"Artist0"}, nil)
+		if assert.Nil(t, err, env) && assert.Len(t, records, 1, env) {
+			assert.EqualValues(t, env, records[0]["Env"], env)
+		}
+	}
+	connection, err := managers["dev"].ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db, _ := asDatabase(connection)
+	output, err := db.ListTables(&dynamodb.ListTablesInput{})
+	if assert.Nil(t, err) {
+		names := aws.StringValueSlice(output.TableNames)
+		sort.Strings(names)
+		assert.EqualValues(t, []string{"dev_music", "dev_people", "prod_music", "prod_people"}, names)
+	}
+}
@@ -0,0 +1,231 @@
+package dyndb
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	//partiQLKey config parameter sends all DML and queries to DynamoDB PartiQL API
+	partiQLKey = "partiql"
+	//partiQLPrefix statement prefix sends a single statement to DynamoDB PartiQL API i.e. PARTIQL SELECT * FROM "music"
+	partiQLPrefix = "partiql"
+	//maxBatchStatements max number of statements supported by BatchExecuteStatement
+	maxBatchStatements = 25
+)
+
+//asPartiQL returns PartiQL statement with true if statement uses PARTIQL prefix or partiql mode is enabled
+func (m *manager) asPartiQL(SQL string) (string, bool) {
+	trimmed := strings.TrimSpace(SQL)
+	if len(trimmed) > len(partiQLPrefix) && strings.EqualFold(trimmed[:len(partiQLPrefix)], partiQLPrefix) && unicode.IsSpace(rune(trimmed[len(partiQLPrefix)])) {
+		return newTableNamespace(m.Config()).partiQL(strings.TrimSpace(trimmed[len(partiQLPrefix):])), true
+	}
+	if !toolbox.AsBoolean(m.Config().Get(partiQLKey)) {
+		return SQL, false
+	}
+	return newTableNamespace(m.Config()).partiQL(SQL), true
+}
+
+//executePartiQL executes PartiQL statement, semicolon separated statements are sent with BatchExecuteStatement,
+//it returns number of affected items, a single DELETE returns old image to count deleted items, batch DELETE counts each successful statement
+func (m *manager) executePartiQL(ctx context.Context, db dynamodbiface.DynamoDBAPI, statement string, sqlParameters []interface{}) (int, error) {
+	statements := splitPartiQL(statement)
+	if len(statements) == 1 {
+		if count := countPlaceholders(statements[0]); count != len(sqlParameters) {
+			return 0, fmt.Errorf("expected %v bind params, but had %v: %v", count, len(sqlParameters), statements[0])
+		}
+		parameters, err := partiQLParameters(sqlParameters)
+		if err != nil {
+			return 0, err
+		}
+		operation := partiQLKeyword(statements[0])
+		if operation == "DELETE" && !hasPartiQLReturning(statements[0]) {
+			statements[0] += " RETURNING ALL OLD *"
+		}
+		output, err := db.ExecuteStatementWithContext(ctx, &dynamodb.ExecuteStatementInput{
+			Statement:  aws.String(statements[0]),
+			Parameters: parameters,
+		})
+		if err != nil {
+			return 0, err
+		}
+		switch operation {
+		case "INSERT", "UPDATE":
+			return 1, nil
+		case "DELETE":
+			return len(output.Items), nil
+		}
+		return 0, nil
+	}
+	if len(statements) > maxBatchStatements {
+		return 0, fmt.Errorf("too many batch statements: %v, max: %v", len(statements), maxBatchStatements)
+	}
+	input := &dynamodb.BatchExecuteStatementInput{}
+	offset := 0
+	for _, item := range statements {
+		count := countPlaceholders(item)
+		if offset+count > len(sqlParameters) {
+			return 0, fmt.Errorf("missing bind param for: %v", item)
+		}
+		parameters, err := partiQLParameters(sqlParameters[offset : offset+count])
+		if err != nil {
+			return 0, err
+		}
+		offset += count
+		input.Statements = append(input.Statements, &dynamodb.BatchStatementRequest{
+			Statement:  aws.String(item),
+			Parameters: parameters,
+		})
+	}
+	if offset != len(sqlParameters) {
+		return 0, fmt.Errorf("expected %v bind params, but had %v: %v", offset, len(sqlParameters), statement)
+	}
+	output, err := db.BatchExecuteStatementWithContext(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	affected := 0
+	for i, response := range output.Responses {
+		if response.Error != nil {
+			return affected, fmt.Errorf("failed to execute %v, %v: %v", statements[i], aws.StringValue(response.Error.Code), aws.StringValue(response.Error.Message))
+		}
+		if partiQLKeyword(statements[i]) != "SELECT" {
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+//partiQLKeyword returns upper case statement keyword i.e. SELECT, INSERT, UPDATE or DELETE
+func partiQLKeyword(statement string) string {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+//hasPartiQLReturning returns true if statement has RETURNING clause outside quoted literals
+func hasPartiQLReturning(statement string) bool {
+	for _, field := range splitFields(statement) {
+		if strings.EqualFold(field, "RETURNING") {
+			return true
+		}
+	}
+	return false
+}
+
+//readPartiQL executes PartiQL query, it follows NextToken till all items are read or handler stops reading
+func (m *manager) readPartiQL(ctx context.Context, db dynamodbiface.DynamoDBAPI, statement string, sqlParameters []interface{}, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
+	if count := countPlaceholders(statement); count != len(sqlParameters) {
+		return fmt.Errorf("expected %v bind params, but had %v: %v", count, len(sqlParameters), statement)
+	}
+	parameters, err := partiQLParameters(sqlParameters)
+	if err != nil {
+		return err
+	}
+	query := &dsc.QueryStatement{BaseStatement: &dsc.BaseStatement{SQL: statement}}
+	input := &dynamodb.ExecuteStatementInput{
+		Statement:  aws.String(statement),
+		Parameters: parameters,
+	}
+	for {
+		output, err := db.ExecuteStatementWithContext(ctx, input)
+		if err != nil {
+			return err
+		}
+		if len(query.Columns) == 0 && len(output.Items) > 0 {
+			var names = make([]string, 0)
+			for key := range output.Items[0] {
+				names = append(names, key)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				query.Columns = append(query.Columns, &dsc.SQLColumn{Name: name})
+			}
+		}
+		for _, item := range output.Items {
+			scanner := dsc.NewSQLScanner(query, m.Config(), nil)
+			scanner.Values = make(map[string]interface{})
+			if err := unmarshalMap(item, &scanner.Values); err != nil {
+				return err
+			}
+			toContinue, err := readingHandler(scanner)
+			if err != nil || !toContinue {
+				return err
+			}
+		}
+		if output.NextToken == nil {
+			return nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func partiQLParameters(sqlParameters []interface{}) ([]*dynamodb.AttributeValue, error) {
+	if len(sqlParameters) == 0 {
+		return nil, nil
+	}
+	var result = make([]*dynamodb.AttributeValue, 0, len(sqlParameters))
+	for _, parameter := range sqlParameters {
+		value, err := marshal(parameter)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+//splitPartiQL splits statements by semicolon outside quoted literals
+func splitPartiQL(statement string) []string {
+	var result = make([]string, 0)
+	var quote rune
+	begin := 0
+	for i, r := range statement {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ';':
+			if item := strings.TrimSpace(statement[begin:i]); item != "" {
+				result = append(result, item)
+			}
+			begin = i + 1
+		}
+	}
+	if item := strings.TrimSpace(statement[begin:]); item != "" || len(result) == 0 {
+		result = append(result, item)
+	}
+	return result
+}
+
+//countPlaceholders returns number of '?' outside quoted literals
+func countPlaceholders(statement string) int {
+	var quote rune
+	count := 0
+	for _, r := range statement {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,136 @@
+package dyndb
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"testing"
+)
+
+func TestSplitPartiQL(t *testing.T) {
+	var useCases = []struct {
+		description  string
+		statement    string
+		expect       []string
+		placeholders int
+	}{
+		{
+			description:  "single statement",
+			statement:    `SELECT * FROM "music" WHERE Artist = ?`,
+			expect:       []string{`SELECT * FROM "music" WHERE Artist = ?`},
+			placeholders: 1,
+		},
+		{
+			description:  "batch statements",
+			statement:    `INSERT INTO "music" VALUE {'Artist': ?, 'SongTitle': ?}; DELETE FROM "music" WHERE Artist = ? AND SongTitle = ?;`,
+			expect:       []string{`INSERT INTO "music" VALUE {'Artist': ?, 'SongTitle': ?}`, `DELETE FROM "music" WHERE Artist = ? AND SongTitle = ?`},
+			placeholders: 4,
+		},
+		{
+			description:  "quoted separator and placeholder",
+			statement:    `UPDATE "music" SET Tags = 'a;b?' WHERE Artist = ?`,
+			expect:       []string{`UPDATE "music" SET Tags = 'a;b?' WHERE Artist = ?`},
+			placeholders: 1,
+		},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, splitPartiQL(useCase.statement), useCase.description)
+		assert.EqualValues(t, useCase.placeholders, countPlaceholders(useCase.statement), useCase.description)
+	}
+}
+
+func TestManager_PartiQL(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://partiql",
+		"region":   "us-west-1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS partiql_users", "CREATE TABLE partiql_users(Id INT HASH KEY)"} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	var useCases = []struct {
+		description string
+		SQL         string
+		parameters  []interface{}
+		expect      int64
+		hasError    bool
+	}{
+		{
+			description: "insert",
+			SQL:         `PARTIQL INSERT INTO "partiql_users" VALUE {'Id': ?, 'Name': ?}`,
+			parameters:  []interface{}{1, "user1"},
+			expect:      1,
+		},
+		{
+			description: "batch insert",
+			SQL:         `PARTIQL INSERT INTO "partiql_users" VALUE {'Id': ?, 'Name': 'user2'}; INSERT INTO "partiql_users" VALUE {'Id': ?, 'Name': 'user3'}`,
+			parameters:  []interface{}{2, 3},
+			expect:      2,
+		},
+		{
+			description: "update",
+			SQL:         `PARTIQL UPDATE "partiql_users" SET Name = ? WHERE Id = ?`,
+			parameters:  []interface{}{"first", 1},
+			expect:      1,
+		},
+		{
+			description: "delete",
+			SQL:         `PARTIQL DELETE FROM "partiql_users" WHERE Id = ?`,
+			parameters:  []interface{}{3},
+			expect:      1,
+		},
+		{
+			description: "delete missing item",
+			SQL:         `PARTIQL DELETE FROM "partiql_users" WHERE Id = ?`,
+			parameters:  []interface{}{3},
+			expect:      0,
+		},
+		{
+			description: "leftover parameter",
+			SQL:         `PARTIQL DELETE FROM "partiql_users" WHERE Id = ?`,
+			parameters:  []interface{}{1, 2},
+			hasError:    true,
+		},
+		{
+			description: "batch leftover parameter",
+			SQL:         `PARTIQL DELETE FROM "partiql_users" WHERE Id = ?; DELETE FROM "partiql_users" WHERE Id = ?`,
+			parameters:  []interface{}{1, 2, 3},
+			hasError:    true,
+		},
+		{
+			description: "batch statement error",
+			SQL:         `PARTIQL INSERT INTO "partiql_users" VALUE {'Id': 4}; INSERT INTO "partiql_users" VALUE {'Id': 1}`,
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		result, err := manager.Execute(useCase.SQL, useCase.parameters...)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		affected, _ := result.RowsAffected()
+		assert.EqualValues(t, useCase.expect, affected, useCase.description)
+	}
+	var records = make([]map[string]interface{}, 0)
+	err = manager.ReadAll(&records, `PARTIQL SELECT Id, Name FROM "partiql_users" WHERE Id < ?`, []interface{}{4}, nil)
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, []map[string]interface{}{{"Id": float64(1), "Name": "first"}, {"Id": float64(2), "Name": "user2"}}, records)
+	}
+	for _, parameters := range [][]interface{}{nil, {4, 5}} {
+		records = make([]map[string]interface{}, 0)
+		err = manager.ReadAll(&records, `PARTIQL SELECT Id, Name FROM "partiql_users" WHERE Id < ?`, parameters, nil)
+		assert.NotNil(t, err, parameters)
+	}
+}
@@ -0,0 +1,133 @@
+package dyndb
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"reflect"
+)
+
+//PersistAllOnConnection upserts each item with a single UpdateItem call instead of reading keys first, returned old image tells inserted from updated items
+func (m *manager) PersistAllOnConnection(connection dsc.Connection, dataPointer interface{}, table string, provider dsc.DmlProvider) (inserted int, updated int, err error) {
+	return m.upsertAll(connection, dataPointer, table, provider, false)
+}
+
+//MergeAll upserts items updating only columns with non zero values, attributes of zero value fields keep their stored values, it returns number of inserted and updated items
+func MergeAll(datastoreManager dsc.Manager, dataPointer interface{}, table string) (inserted int, updated int, err error) {
+	m, ok := datastoreManager.(*manager)
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported manager: %T", datastoreManager)
+	}
+	connection, err := m.ConnectionProvider().Get()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer connection.Close()
+	return m.upsertAll(connection, dataPointer, table, nil, true)
+}
+
+//upsertAll upserts slice items with connection context, merge skips columns with zero values
+func (m *manager) upsertAll(connection dsc.Connection, dataPointer interface{}, table string, provider dsc.DmlProvider, merge bool) (inserted int, updated int, err error) {
+	db, err := asDatabase(connection)
+	if err != nil {
+		return 0, 0, err
+	}
+	ctx := connectionContext(connection)
+	if ranger, ok := dataPointer.(toolbox.Ranger); ok {
+		collection := toolbox.AsSlice(ranger)
+		dataPointer = &collection
+	} else if iterator, ok := dataPointer.(toolbox.Iterator); ok {
+		collection := toolbox.AsSlice(iterator)
+		dataPointer = &collection
+	}
+	toolbox.AssertPointerKind(dataPointer, reflect.Slice, "dataPointer")
+	structType := reflect.TypeOf(dataPointer).Elem().Elem()
+	if provider, err = dsc.NewDmlProviderIfNeeded(provider, table, structType); err != nil {
+		return 0, 0, err
+	}
+	if _, err = m.RegisterDescriptorIfNeeded(table, dataPointer); err != nil {
+		return 0, 0, err
+	}
+	description, err := m.describe(db, m.tableName(table))
+	if err != nil {
+		return 0, 0, err
+	}
+	keyNames := keySchemaNames(description.KeySchema)
+	var statements = make(map[string]*dsc.DmlStatement)
+	toolbox.ProcessSlice(dataPointer, func(item interface{}) bool {
+		parametrized := provider.Get(dsc.SQLTypeInsert, item)
+		statement, ok := statements[parametrized.SQL]
+		if !ok {
+			if statement, err = dsc.NewDmlParser().Parse(parametrized.SQL); err != nil {
+				err = fmt.Errorf("failed to parse %v due to %v", parametrized.SQL, err)
+				return false
+			}
+			statement.Table = m.tableName(statement.Table)
+			statements[parametrized.SQL] = statement
+		}
+		var input *dynamodb.UpdateItemInput
+		if input, err = upsertInput(statement, parametrized.Values, keyNames, merge); err != nil {
+			return false
+		}
+		var output *dynamodb.UpdateItemOutput
+		if output, err = db.UpdateItemWithContext(ctx, input); err != nil {
+			err = fmt.Errorf("failed to persist %v, %v", table, err)
+			return false
+		}
+		if len(output.Attributes) > 0 {
+			updated++
+		} else {
+			inserted++
+		}
+		return true
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return inserted, updated, nil
+}
+
+//upsertInput returns update item input setting insert statement columns other than keys, merge skips columns with zero values
+func upsertInput(statement *dsc.DmlStatement, parameters []interface{}, keyNames []string, merge bool) (*dynamodb.UpdateItemInput, error) {
+	record, err := statement.ColumnValueMap(toolbox.NewSliceIterator(parameters))
+	if err != nil {
+		return nil, err
+	}
+	var keyValues = make(map[string]interface{})
+	for _, name := range keyNames {
+		value, ok := record[name]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("missing key %v value in %v", name, statement.Table)
+		}
+		keyValues[name] = value
+	}
+	key, err := marshalMap(keyValues)
+	if err != nil {
+		return nil, err
+	}
+	result := &dynamodb.UpdateItemInput{
+		TableName:    aws.String(statement.Table),
+		Key:          key,
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	}
+	var columns = make([]*dsc.SQLColumn, 0, len(statement.Columns))
+	for _, column := range statement.Columns {
+		if _, isKey := keyValues[column.Name]; isKey || (merge && isZero(record[column.Name])) {
+			continue
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return result, nil
+	}
+	updated := &dsc.DmlStatement{BaseStatement: &dsc.BaseStatement{Table: statement.Table, Columns: columns}}
+	result.UpdateExpression, result.ExpressionAttributeNames, result.ExpressionAttributeValues, err = updateExpression(updated, record)
+	return result, err
+}
+
+//isZero returns true for nil or zero value, pointers to zero value are not zero
+func isZero(value interface{}) bool {
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
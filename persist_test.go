@@ -0,0 +1,101 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"testing"
+)
+
+func TestUpsertInput(t *testing.T) {
+	statement, err := dsc.NewDmlParser().Parse("INSERT INTO music(Artist, SongTitle, Price, Genre) VALUES(?, ?, ?, ?)")
+	if !assert.Nil(t, err) {
+		return
+	}
+	keyNames := []string{"Artist", "SongTitle"}
+	parameters := []interface{}{"Artist1", "Title1", 1.5, ""}
+
+	input, err := upsertInput(statement, parameters, keyNames, false)
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "Artist1", *input.Key["Artist"].S)
+		assert.EqualValues(t, "Title1", *input.Key["SongTitle"].S)
+		assert.EqualValues(t, dynamodb.ReturnValueAllOld, *input.ReturnValues)
+		assert.EqualValues(t, "SET #u1 = :u1, #u2 = :u2", *input.UpdateExpression)
+		assert.EqualValues(t, "Genre", *input.ExpressionAttributeNames["#u2"])
+	}
+
+	input, err = upsertInput(statement, parameters, keyNames, true)
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "SET #u1 = :u1", *input.UpdateExpression)
+		assert.EqualValues(t, "Price", *input.ExpressionAttributeNames["#u1"])
+		assert.EqualValues(t, "1.5", *input.ExpressionAttributeValues[":u1"].N)
+	}
+
+	input, err = upsertInput(statement, []interface{}{"Artist1", "Title1", 0, ""}, keyNames, true)
+	if assert.Nil(t, err) {
+		assert.Nil(t, input.UpdateExpression)
+		assert.EqualValues(t, 2, len(input.Key))
+	}
+
+	_, err = upsertInput(statement, parameters, []string{"Id"}, false)
+	assert.NotNil(t, err)
+}
+
+func TestManager_PersistAllOnConnection(t *testing.T) {
+	type user struct {
+		Id   int `primaryKey:"true"`
+		Name string
+	}
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://persist",
+		"region":   "us-west-1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	datastoreManager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS persist_users", "CREATE TABLE persist_users(Id INT HASH KEY)"} {
+		if _, err = datastoreManager.Execute(SQL); !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	connection, err := datastoreManager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	var useCases = []struct {
+		description string
+		ctx         context.Context
+		inserted    int
+		hasError    bool
+	}{
+		{
+			description: "cancelled context",
+			ctx:         cancelled,
+			hasError:    true,
+		},
+		{
+			description: "active context",
+			ctx:         context.Background(),
+			inserted:    1,
+		},
+	}
+	for _, useCase := range useCases {
+		records := []*user{{Id: 1, Name: "user1"}}
+		inserted, _, err := datastoreManager.PersistAllOnConnection(withContext(connection, useCase.ctx), &records, "persist_users", nil)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, useCase.inserted, inserted, useCase.description)
+		}
+	}
+}
@@ -0,0 +1,693 @@
+package dyndb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	getItemOperation      = "GetItem"
+	batchGetItemOperation = "BatchGetItem"
+	queryOperation        = "Query"
+	scanOperation         = "Scan"
+	putItemOperation      = "PutItem"
+	updateItemOperation   = "UpdateItem"
+	deleteItemOperation   = "DeleteItem"
+	partiQLOperation      = "ExecuteStatement"
+
+	//allowScanKey config parameter, false rejects queries that would scan a table or index
+	allowScanKey = "allowScan"
+	//safeModeKey config parameter, true rejects any statement that would scan a table or index, including DELETE without key criteria
+	safeModeKey = "safeMode"
+	//explainKeyword statement prefix returns plan instead of executing statement i.e. EXPLAIN SELECT * FROM music WHERE Artist = ?
+	explainKeyword = "EXPLAIN"
+	//pageSize max size of data read by a single Query or Scan request
+	pageSize = 1024 * 1024
+)
+
+//Plan represents statement access path
+type Plan struct {
+	Operation      string //GetItem, BatchGetItem, Query, Scan, PutItem, UpdateItem, DeleteItem or ExecuteStatement
+	Write          string //write operation applied to each item read by Operation i.e. DeleteItem for DELETE without key criteria
+	Table          string
+	Index          string
+	KeyCondition   string //key condition expression for Query, key criteria for item operations
+	Filter         string
+	Projection     string
+	Keys           int  //number of item keys for item operations
+	Consistent     bool //strongly consistent read
+	EstimatedPages int  //estimated number of requests, Scan uses table or index size, Query is estimated as a single page
+
+	statement     *dsc.QueryStatement
+	hints         hints
+	keyNames      []string
+	indexKeyNames []string //hash and sort key names of read table or index
+	selectCount   bool
+	limit         int //max number of returned rows, 0 returns all rows
+	keys          []map[string]*dynamodb.AttributeValue
+	projected     *dynamodb.KeysAndAttributes
+	query         *dynamodb.QueryInput
+	scan          *dynamodb.ScanInput
+}
+
+//columns returns plan columns as returned by EXPLAIN statement
+func (p *Plan) columns() []string {
+	return []string{"Operation", "Write", "Table", "Index", "KeyCondition", "Filter", "Projection", "Keys", "Consistent", "EstimatedPages"}
+}
+
+func (p *Plan) values() map[string]interface{} {
+	return map[string]interface{}{
+		"Operation":      p.Operation,
+		"Write":          p.Write,
+		"Table":          p.Table,
+		"Index":          p.Index,
+		"KeyCondition":   p.KeyCondition,
+		"Filter":         p.Filter,
+		"Projection":     p.Projection,
+		"Keys":           p.Keys,
+		"Consistent":     p.Consistent,
+		"EstimatedPages": p.EstimatedPages,
+	}
+}
+
+//explainParameter stands for bind parameter missing in explained statement
+type explainParameter struct{}
+
+//Explain returns access plan for SELECT, INSERT, UPDATE or DELETE statement without executing it, missing bind parameters are shown as '?'
+func Explain(datastoreManager dsc.Manager, SQL string, parameters []interface{}) (*Plan, error) {
+	m, ok := datastoreManager.(*manager)
+	if !ok {
+		return nil, fmt.Errorf("unsupported manager: %T", datastoreManager)
+	}
+	connection, err := m.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if err != nil {
+		return nil, err
+	}
+	return m.plan(db, SQL, parameters)
+}
+
+//plan returns plan for supplied statement
+func (m *manager) plan(db dynamodbiface.DynamoDBAPI, SQL string, parameters []interface{}) (*Plan, error) {
+	for placeholders := countPlaceholders(SQL); len(parameters) < placeholders; {
+		parameters = append(parameters, explainParameter{})
+	}
+	if statement, ok := m.asPartiQL(SQL); ok {
+		return &Plan{Operation: partiQLOperation, KeyCondition: statement, EstimatedPages: 1}, nil
+	}
+	withoutHints, queryHints := parseHints(SQL)
+	if hasKeywordPrefix(strings.TrimSpace(withoutHints), "SELECT") {
+		return m.planQuery(db, SQL, parameters)
+	}
+	statement, err := dsc.NewDmlParser().Parse(strings.TrimSpace(withoutHints))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v due to %v", SQL, err)
+	}
+	statement.Table = m.tableName(statement.Table)
+	return m.planModification(db, strings.TrimSpace(withoutHints), statement, queryHints, parameters)
+}
+
+//explain passes statement plan to reading handler as a single row
+func (m *manager) explain(db dynamodbiface.DynamoDBAPI, SQL string, parameters []interface{}, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
+	plan, err := m.plan(db, SQL, parameters)
+	if err != nil {
+		return err
+	}
+	statement := &dsc.QueryStatement{BaseStatement: &dsc.BaseStatement{SQL: SQL}}
+	for _, column := range plan.columns() {
+		statement.Columns = append(statement.Columns, &dsc.SQLColumn{Name: column})
+	}
+	scanner := dsc.NewSQLScanner(statement, m.Config(), nil)
+	scanner.Values = plan.values()
+	_, err = readingHandler(scanner)
+	return err
+}
+
+//allowScan returns false if allowScan or safeMode config parameter disables scans
+func (m *manager) allowScan() bool {
+	if m.Config().Has(allowScanKey) {
+		return toolbox.AsBoolean(m.Config().Get(allowScanKey))
+	}
+	return !m.safeMode()
+}
+
+//safeMode returns true if safeMode config parameter is set
+func (m *manager) safeMode() bool {
+	return toolbox.AsBoolean(m.Config().Get(safeModeKey))
+}
+
+//checkScan returns error if plan would scan a table while scans are disabled, ALLOW_SCAN hint overrides config parameters
+func (m *manager) checkScan(SQL string, plan *Plan) error {
+	if plan.Operation != scanOperation || plan.hints.Has(allowScanHint) {
+		return nil
+	}
+	if plan.Write != "" {
+		if !m.safeMode() {
+			return nil
+		}
+		if plan.Filter == "" {
+			return fmt.Errorf("%v would scan and delete all items of %v, add /*+ %v */ hint or disable %v config parameter", SQL, plan.Table, allowScanHint, safeModeKey)
+		}
+		action := "delete"
+		if plan.Write == updateItemOperation {
+			action = "update"
+		}
+		return fmt.Errorf("%v would scan %v to find items to %v, add key criteria, /*+ %v */ hint or disable %v config parameter", SQL, plan.Table, action, allowScanHint, safeModeKey)
+	}
+	if m.allowScan() {
+		return nil
+	}
+	return fmt.Errorf("%v would scan %v, add key criteria, /*+ %v */ hint or enable %v config parameter", SQL, plan.Table, allowScanHint, allowScanKey)
+}
+
+//planQuery returns GetItem or BatchGetItem plan if criteria matches full primary key, Query plan if criteria has hash key equality, otherwise Scan plan
+func (m *manager) planQuery(db dynamodbiface.DynamoDBAPI, SQL string, parameters []interface{}) (*Plan, error) {
+	SQL, queryHints := parseHints(SQL)
+	return m.planRead(db, SQL, queryHints, parameters)
+}
+
+//planRead returns read plan for SELECT statement without hints
+func (m *manager) planRead(db dynamodbiface.DynamoDBAPI, SQL string, queryHints hints, parameters []interface{}) (*Plan, error) {
+	SQL, where := splitCriteria(SQL)
+	statement, clauses, err := parseQuery(SQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse statement %v, %v", SQL, err)
+	}
+	statement.Table = m.tableName(statement.Table)
+	result, err := m.planStatement(db, statement, where, queryHints, parameters)
+	if err != nil {
+		return nil, err
+	}
+	result.applyClauses(clauses)
+	return result, nil
+}
+
+//applyClauses sets Query direction for ORDER BY index sort key and LIMIT on returned rows
+func (p *Plan) applyClauses(clauses *queryClauses) {
+	p.limit = clauses.limit
+	if p.query == nil || clauses.orderBy == "" || len(p.indexKeyNames) < 2 || p.indexKeyNames[1] != clauses.orderBy {
+		return
+	}
+	p.query.ScanIndexForward = aws.Bool(!clauses.descending)
+}
+
+//planStatement returns read plan for parsed SELECT statement with DynamoDB table name and criteria
+func (m *manager) planStatement(db dynamodbiface.DynamoDBAPI, statement *dsc.QueryStatement, where string, queryHints hints, parameters []interface{}) (*Plan, error) {
+	var criteria expr
+	var err error
+	if where != "" {
+		if criteria, err = parseCriteria(where); err != nil {
+			return nil, fmt.Errorf("failed to parse criteria %v, %v", where, err)
+		}
+	}
+	description, err := m.describe(db, statement.Table)
+	if err != nil {
+		return nil, err
+	}
+	options, err := m.readOptions(description, queryHints)
+	if err != nil {
+		return nil, err
+	}
+	keySchema, size, err := indexKeySchema(description, options.index)
+	if err != nil {
+		return nil, err
+	}
+	result := &Plan{
+		Table:      statement.Table,
+		Index:      options.index,
+		Consistent: options.consistent,
+		statement:  statement,
+		hints:      queryHints,
+	}
+	builder := newExpressionBuilder(parameters)
+	sel, projection := normalizeExpr(statement, builder)
+	result.selectCount = sel != nil
+	result.Projection = builder.describe(aws.StringValue(projection))
+	keyNames := keySchemaNames(keySchema)
+	result.indexKeyNames = keyNames
+	if criteria != nil && options.index == "" && sel == nil {
+		ok, err := result.planItems(criteria, parameters, keyNames)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result.projected = &dynamodb.KeysAndAttributes{
+				ProjectionExpression:     projection,
+				ExpressionAttributeNames: builder.attributeNames(),
+				ConsistentRead:           aws.Bool(options.consistent),
+			}
+			return result, nil
+		}
+	}
+	keyConditions, filters := splitKeyConditions(criteria, keyNames, builder)
+	var keyCondition, filter *string
+	if len(keyConditions) > 0 {
+		var conditions = make([]string, 0, len(keyConditions))
+		for _, item := range keyConditions {
+			condition, err := builder.condition(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to translate key condition %v, %v", where, err)
+			}
+			conditions = append(conditions, condition)
+		}
+		keyCondition = aws.String(strings.Join(conditions, " AND "))
+	}
+	if len(filters) > 0 {
+		condition, err := builder.condition(conjunction(filters))
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate criteria %v, %v", where, err)
+		}
+		filter = aws.String(condition)
+	}
+	values, err := builder.attributeValues()
+	if err != nil {
+		return nil, err
+	}
+	var index *string
+	if options.index != "" {
+		index = aws.String(options.index)
+	}
+	result.KeyCondition = builder.describe(aws.StringValue(keyCondition))
+	result.Filter = builder.describe(aws.StringValue(filter))
+	if keyCondition != nil {
+		result.Operation = queryOperation
+		result.EstimatedPages = 1
+		result.query = &dynamodb.QueryInput{
+			TableName:                 aws.String(statement.Table),
+			IndexName:                 index,
+			KeyConditionExpression:    keyCondition,
+			FilterExpression:          filter,
+			ProjectionExpression:      projection,
+			Select:                    sel,
+			ConsistentRead:            aws.Bool(options.consistent),
+			ExpressionAttributeNames:  builder.attributeNames(),
+			ExpressionAttributeValues: values,
+		}
+		return result, nil
+	}
+	result.Operation = scanOperation
+	result.EstimatedPages = estimatePages(size)
+	result.scan = &dynamodb.ScanInput{
+		TableName:                 aws.String(statement.Table),
+		IndexName:                 index,
+		FilterExpression:          filter,
+		ProjectionExpression:      projection,
+		Select:                    sel,
+		ConsistentRead:            aws.Bool(options.consistent),
+		ExpressionAttributeNames:  builder.attributeNames(),
+		ExpressionAttributeValues: values,
+	}
+	return result, nil
+}
+
+//planItems sets GetItem or BatchGetItem operation if criteria uses only equality or IN predicates on all primary key attributes
+func (p *Plan) planItems(criteria expr, parameters []interface{}, keyNames []string) (bool, error) {
+	valueMap, ok, err := keyCriteria(criteria, parameters)
+	if !ok || err != nil {
+		return false, err
+	}
+	var described = make([]string, 0)
+	ok, err = processCriteria(valueMap, func(keyValues map[string]interface{}) (bool, error) {
+		if len(keyNames) != len(keyValues) {
+			return false, nil
+		}
+		var conditions = make([]string, 0, len(keyNames))
+		for _, name := range keyNames {
+			value, ok := keyValues[name]
+			if !ok || (toolbox.IsSlice(value) && !isBinary(value)) {
+				return false, nil
+			}
+			conditions = append(conditions, name+" = "+describeValue(value))
+		}
+		keyAttributes, err := marshalMap(keyValues)
+		if err != nil {
+			return false, err
+		}
+		described = append(described, strings.Join(conditions, " AND "))
+		p.keys = append(p.keys, keyAttributes)
+		return true, nil
+	})
+	if !ok || err != nil || len(p.keys) == 0 {
+		p.keys = nil
+		return false, err
+	}
+	p.Keys = len(p.keys)
+	p.Operation = getItemOperation
+	p.EstimatedPages = 1
+	p.KeyCondition = described[0]
+	if len(p.keys) > 1 {
+		p.Operation = batchGetItemOperation
+		p.EstimatedPages = (len(p.keys) + maxBatchGetItems - 1) / maxBatchGetItems
+		p.KeyCondition = "(" + strings.Join(described, ") OR (") + ")"
+	}
+	return true, nil
+}
+
+//planKeys returns plan reading primary keys of items matching criteria with write operation applied to each key,
+//full key criteria are planned as the write operation itself, otherwise keys are read by Query or Scan with key projection
+func (m *manager) planKeys(db dynamodbiface.DynamoDBAPI, table, where string, queryHints hints, parameters []interface{}, write string) (*Plan, error) {
+	description, err := m.describe(db, table)
+	if err != nil {
+		return nil, err
+	}
+	keyNames := keySchemaNames(description.KeySchema)
+	statement := &dsc.QueryStatement{BaseStatement: &dsc.BaseStatement{
+		SQL:         fmt.Sprintf("SELECT %v FROM %v", strings.Join(keyNames, ", "), table),
+		Table:       table,
+		SQLCriteria: &dsc.SQLCriteria{Criteria: make([]*dsc.SQLCriterion, 0)},
+	}}
+	for _, name := range keyNames {
+		statement.Columns = append(statement.Columns, &dsc.SQLColumn{Name: name})
+	}
+	result, err := m.planStatement(db, statement, where, queryHints, parameters)
+	if err != nil {
+		return nil, err
+	}
+	result.Consistent = false
+	result.keyNames = keyNames
+	switch result.Operation {
+	case getItemOperation, batchGetItemOperation:
+		result.Operation = write
+		result.Projection = ""
+		result.EstimatedPages = len(result.keys)
+	default:
+		result.Write = write
+	}
+	return result, nil
+}
+
+//planModification returns plan for INSERT, UPDATE or DELETE statement
+func (m *manager) planModification(db dynamodbiface.DynamoDBAPI, SQL string, statement *dsc.DmlStatement, queryHints hints, parameters []interface{}) (*Plan, error) {
+	result := &Plan{Table: statement.Table, EstimatedPages: 1, Keys: 1, hints: queryHints}
+	var key map[string]*dynamodb.AttributeValue
+	switch statement.Type {
+	case "INSERT":
+		result.Operation = putItemOperation
+		input, err := m.insertInput(statement, parameters)
+		if err != nil {
+			return nil, err
+		}
+		description, err := m.describe(db, statement.Table)
+		if err != nil {
+			return nil, err
+		}
+		key = make(map[string]*dynamodb.AttributeValue)
+		for _, name := range keySchemaNames(description.KeySchema) {
+			key[name] = input.Item[name]
+		}
+	case "UPDATE":
+		return m.planUpdate(db, SQL, statement, queryHints, parameters)
+	case "DELETE":
+		_, where := splitCriteria(SQL)
+		return m.planDelete(db, statement.Table, where, queryHints, parameters)
+	default:
+		return nil, fmt.Errorf("unsupported statement: %v", statement.Type)
+	}
+	result.KeyCondition = describeKey(key)
+	return result, nil
+}
+
+//readPlan executes read plan
+func (m *manager) readPlan(ctx context.Context, db dynamodbiface.DynamoDBAPI, plan *Plan, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
+	if plan.limit > 0 {
+		handler, rows := readingHandler, 0
+		readingHandler = func(scanner dsc.Scanner) (bool, error) {
+			rows++
+			toContinue, err := handler(scanner)
+			return toContinue && rows < plan.limit, err
+		}
+	}
+	switch plan.Operation {
+	case getItemOperation:
+		return m.getItem(ctx, db, plan.statement, plan.keys[0], plan.projected, readingHandler)
+	case batchGetItemOperation:
+		return m.batchGetItems(ctx, db, plan.statement, plan.keys, plan.projected, readingHandler)
+	}
+	return m.readPages(ctx, db, plan, readingHandler)
+}
+
+//readPages passes Query or Scan page items and count to handler till it stops reading, pages are read with QueryPages or ScanPages
+func (p *Plan) readPages(ctx context.Context, db dynamodbiface.DynamoDBAPI, handler func(items []map[string]*dynamodb.AttributeValue, count int64) (toContinue bool, err error)) error {
+	var handlerErr error
+	handle := func(items []map[string]*dynamodb.AttributeValue, count *int64) bool {
+		var toContinue bool
+		toContinue, handlerErr = handler(items, aws.Int64Value(count))
+		return toContinue && handlerErr == nil
+	}
+	var err error
+	if p.query != nil {
+		err = db.QueryPagesWithContext(ctx, p.query, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+			return handle(output.Items, output.Count)
+		})
+	} else {
+		err = db.ScanPagesWithContext(ctx, p.scan, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+			return handle(output.Items, output.Count)
+		})
+	}
+	if handlerErr != nil {
+		return handlerErr
+	}
+	return err
+}
+
+//readPages reads Query or Scan pages till the last page or reading handler stops reading, COUNT queries emit a single row
+func (m *manager) readPages(ctx context.Context, db dynamodbiface.DynamoDBAPI, plan *Plan, readingHandler func(scanner dsc.Scanner) (toContinue bool, err error)) error {
+	statement := plan.statement
+	var count int64
+	err := plan.readPages(ctx, db, func(items []map[string]*dynamodb.AttributeValue, pageCount int64) (bool, error) {
+		count += pageCount
+		if plan.selectCount {
+			return true, nil
+		}
+		if len(statement.Columns) == 0 && len(items) > 0 {
+			var names = make([]string, 0, len(items[0]))
+			for key := range items[0] {
+				names = append(names, key)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				statement.Columns = append(statement.Columns, &dsc.SQLColumn{Name: name})
+			}
+		}
+		for _, item := range items {
+			toContinue, err := m.handleItem(statement, item, readingHandler)
+			if err != nil || !toContinue {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	if err != nil || !plan.selectCount {
+		return err
+	}
+	column := statement.Columns[0]
+	alias := column.Alias
+	if alias == "" {
+		alias = column.Expression
+	}
+	column.Name = alias
+	scanner := dsc.NewSQLScanner(statement, m.Config(), nil)
+	scanner.Values = map[string]interface{}{alias: int(count)}
+	_, err = readingHandler(scanner)
+	return err
+}
+
+//splitKeyConditions returns hash key equality with optional range key condition and remaining filter conjuncts
+func splitKeyConditions(criteria expr, keyNames []string, builder *expressionBuilder) ([]expr, []expr) {
+	if criteria == nil {
+		return nil, nil
+	}
+	items := conjuncts(criteria)
+	hashIndex, rangeIndex := -1, -1
+	for i, item := range items {
+		if hashIndex == -1 && len(keyNames) > 0 && isHashKeyCondition(item, keyNames[0], builder) {
+			hashIndex = i
+			continue
+		}
+		if rangeIndex == -1 && len(keyNames) > 1 && isRangeKeyCondition(item, keyNames[1], builder) {
+			rangeIndex = i
+		}
+	}
+	if hashIndex == -1 {
+		return nil, items
+	}
+	keyConditions := []expr{items[hashIndex]}
+	if rangeIndex != -1 {
+		keyConditions = append(keyConditions, items[rangeIndex])
+	}
+	var filters = make([]expr, 0)
+	for i, item := range items {
+		if i != hashIndex && i != rangeIndex {
+			filters = append(filters, item)
+		}
+	}
+	return keyConditions, filters
+}
+
+func isHashKeyCondition(criteria expr, key string, builder *expressionBuilder) bool {
+	compare, ok := criteria.(*compareExpr)
+	if !ok || compare.Op != "=" {
+		return false
+	}
+	return (isKeyPath(compare.X, key) && isScalarOperand(compare.Y, builder)) || (isKeyPath(compare.Y, key) && isScalarOperand(compare.X, builder))
+}
+
+func isRangeKeyCondition(criteria expr, key string, builder *expressionBuilder) bool {
+	switch actual := criteria.(type) {
+	case *compareExpr:
+		if actual.Op == "<>" {
+			return false
+		}
+		return isKeyPath(actual.X, key) && isScalarOperand(actual.Y, builder)
+	case *betweenExpr:
+		return isKeyPath(actual.X, key) && isScalarOperand(actual.Min, builder) && isScalarOperand(actual.Max, builder)
+	case *likeExpr:
+		if actual.Not || !isKeyPath(actual.X, key) {
+			return false
+		}
+		var pattern interface{}
+		switch operand := actual.Pattern.(type) {
+		case *literalExpr:
+			pattern = operand.Value
+		case *placeholderExpr:
+			pattern, _ = builder.parameter(operand)
+		}
+		text, ok := pattern.(string)
+		return ok && len(text) > 1 && strings.Index(text, "%") == len(text)-1
+	}
+	return false
+}
+
+func isKeyPath(operand expr, key string) bool {
+	path, ok := operand.(*pathExpr)
+	return ok && path.Path == key
+}
+
+//isScalarOperand returns true for literal or placeholder bound to a non list value
+func isScalarOperand(operand expr, builder *expressionBuilder) bool {
+	switch actual := operand.(type) {
+	case *literalExpr:
+		return actual.Value != nil
+	case *placeholderExpr:
+		value, err := builder.parameter(actual)
+		return err == nil && value != nil && (!toolbox.IsSlice(value) || isBinary(value))
+	}
+	return false
+}
+
+func isBinary(value interface{}) bool {
+	_, ok := value.([]byte)
+	return ok
+}
+
+//conjuncts returns AND-ed criteria items
+func conjuncts(criteria expr) []expr {
+	if logical, ok := criteria.(*logicalExpr); ok && logical.Op == "AND" {
+		return append(conjuncts(logical.X), conjuncts(logical.Y)...)
+	}
+	return []expr{criteria}
+}
+
+//conjunction returns items joined with AND
+func conjunction(items []expr) expr {
+	var result expr
+	for _, item := range items {
+		if result == nil {
+			result = item
+			continue
+		}
+		result = &logicalExpr{Op: "AND", X: result, Y: item}
+	}
+	return result
+}
+
+//indexKeySchema returns key schema and size in bytes of the table or supplied secondary index
+func indexKeySchema(description *dynamodb.TableDescription, index string) ([]*dynamodb.KeySchemaElement, int64, error) {
+	if index == "" {
+		return description.KeySchema, aws.Int64Value(description.TableSizeBytes), nil
+	}
+	for _, candidate := range description.GlobalSecondaryIndexes {
+		if aws.StringValue(candidate.IndexName) == index {
+			return candidate.KeySchema, aws.Int64Value(candidate.IndexSizeBytes), nil
+		}
+	}
+	for _, candidate := range description.LocalSecondaryIndexes {
+		if aws.StringValue(candidate.IndexName) == index {
+			return candidate.KeySchema, aws.Int64Value(candidate.IndexSizeBytes), nil
+		}
+	}
+	return nil, 0, fmt.Errorf("unknown index %v on table %v", index, aws.StringValue(description.TableName))
+}
+
+func estimatePages(size int64) int {
+	if size <= pageSize {
+		return 1
+	}
+	return int((size + pageSize - 1) / pageSize)
+}
+
+var expressionTokens = regexp.MustCompile(`#n\d+|:p\d+`)
+
+//describe returns expression with attribute names and values substituted
+func (b *expressionBuilder) describe(expression string) string {
+	return expressionTokens.ReplaceAllStringFunc(expression, func(token string) string {
+		if name, ok := b.names[token]; ok {
+			return *name
+		}
+		if value, ok := b.values[token]; ok {
+			return describeValue(value)
+		}
+		return token
+	})
+}
+
+//describeKey returns item key as equality criteria
+func describeKey(key map[string]*dynamodb.AttributeValue) string {
+	var names = make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result = make([]string, 0, len(names))
+	for _, name := range names {
+		var value interface{} = explainParameter{}
+		if key[name] != nil && key[name].M == nil {
+			value = plainAttribute(key[name])
+		}
+		result = append(result, name+" = "+describeValue(value))
+	}
+	return strings.Join(result, " AND ")
+}
+
+//describeValue returns SQL like literal for supplied value
+func describeValue(value interface{}) string {
+	switch actual := value.(type) {
+	case explainParameter:
+		return "?"
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.Replace(actual, "'", "''", -1) + "'"
+	case json.Number:
+		return actual.String()
+	case bool, int, int64, float64, float32, int32, uint, uint64:
+		return toolbox.AsString(actual)
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return toolbox.AsString(value)
+}
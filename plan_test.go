@@ -0,0 +1,227 @@
+package dyndb
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"strings"
+	"testing"
+)
+
+func TestSplitKeyConditions(t *testing.T) {
+	keyNames := []string{"Artist", "SongTitle"}
+	var useCases = []struct {
+		description  string
+		criteria     string
+		parameters   []interface{}
+		keyCondition string
+		filter       string
+	}{
+		{
+			description:  "hash key",
+			criteria:     "Artist = ?",
+			parameters:   []interface{}{"Artist1"},
+			keyCondition: "Artist = 'Artist1'",
+		},
+		{
+			description:  "hash and range key with filter",
+			criteria:     "Price > 1 AND SongTitle >= ? AND Artist = ?",
+			parameters:   []interface{}{"T", "Artist1"},
+			keyCondition: "Artist = 'Artist1' AND SongTitle >= 'T'",
+			filter:       "Price > 1",
+		},
+		{
+			description:  "range key prefix",
+			criteria:     "Artist = 'Artist1' AND SongTitle LIKE 'Ti%'",
+			keyCondition: "Artist = 'Artist1' AND begins_with(SongTitle, 'Ti')",
+		},
+		{
+			description:  "range key between",
+			criteria:     "Artist = ? AND SongTitle BETWEEN ? AND ? AND Genre = ?",
+			parameters:   []interface{}{"Artist1", "A", "C", "Pop"},
+			keyCondition: "Artist = 'Artist1' AND SongTitle BETWEEN 'A' AND 'C'",
+			filter:       "Genre = 'Pop'",
+		},
+		{
+			description:  "range key not equal is a filter",
+			criteria:     "Artist = ? AND SongTitle <> ?",
+			parameters:   []interface{}{"Artist1", "T"},
+			keyCondition: "Artist = 'Artist1'",
+			filter:       "SongTitle <> 'T'",
+		},
+		{
+			description: "range key without hash key",
+			criteria:    "SongTitle = ?",
+			parameters:  []interface{}{"T"},
+			filter:      "SongTitle = 'T'",
+		},
+		{
+			description: "OR criteria",
+			criteria:    "Artist = ? OR Genre = ?",
+			parameters:  []interface{}{"Artist1", "Pop"},
+			filter:      "(Artist = 'Artist1' OR Genre = 'Pop')",
+		},
+		{
+			description: "hash key IN",
+			criteria:    "Artist IN (?, ?)",
+			parameters:  []interface{}{"Artist1", "Artist2"},
+			filter:      "Artist IN ('Artist1', 'Artist2')",
+		},
+	}
+	for _, useCase := range useCases {
+		criteria, err := parseCriteria(useCase.criteria)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		builder := newExpressionBuilder(useCase.parameters)
+		keyConditions, filters := splitKeyConditions(criteria, keyNames, builder)
+		var conditions = make([]string, 0)
+		for _, item := range keyConditions {
+			condition, err := builder.condition(item)
+			assert.Nil(t, err, useCase.description)
+			conditions = append(conditions, builder.describe(condition))
+		}
+		assert.EqualValues(t, useCase.keyCondition, strings.Join(conditions, " AND "), useCase.description)
+		filter := ""
+		if len(filters) > 0 {
+			condition, err := builder.condition(conjunction(filters))
+			assert.Nil(t, err, useCase.description)
+			filter = builder.describe(condition)
+		}
+		assert.EqualValues(t, useCase.filter, filter, useCase.description)
+	}
+}
+
+func TestPlan_PlanItems(t *testing.T) {
+	keyNames := []string{"Artist", "SongTitle"}
+	var useCases = []struct {
+		description  string
+		criteria     string
+		parameters   []interface{}
+		expectOK     bool
+		operation    string
+		keys         int
+		keyCondition string
+		pages        int
+	}{
+		{
+			description:  "single key",
+			criteria:     "SongTitle = ? AND Artist = ?",
+			parameters:   []interface{}{"Title1", "Artist1"},
+			expectOK:     true,
+			operation:    getItemOperation,
+			keys:         1,
+			keyCondition: "Artist = 'Artist1' AND SongTitle = 'Title1'",
+			pages:        1,
+		},
+		{
+			description:  "tuple keys",
+			criteria:     "(Artist, SongTitle) IN ((?, ?), (?, ?))",
+			parameters:   []interface{}{"Artist1", "Title1", "Artist2", "Title2"},
+			expectOK:     true,
+			operation:    batchGetItemOperation,
+			keys:         2,
+			keyCondition: "(Artist = 'Artist1' AND SongTitle = 'Title1') OR (Artist = 'Artist2' AND SongTitle = 'Title2')",
+			pages:        1,
+		},
+		{
+			description:  "missing parameters",
+			criteria:     "Artist = ? AND SongTitle = ?",
+			parameters:   []interface{}{explainParameter{}, explainParameter{}},
+			expectOK:     true,
+			operation:    getItemOperation,
+			keys:         1,
+			keyCondition: "Artist = ? AND SongTitle = ?",
+			pages:        1,
+		},
+		{
+			description:  "hash key IN with range key",
+			criteria:     "Artist IN (?, ?) AND SongTitle = ?",
+			parameters:   []interface{}{"Artist1", "Artist2", "Title1"},
+			expectOK:     true,
+			operation:    batchGetItemOperation,
+			keys:         2,
+			keyCondition: "(Artist = 'Artist1' AND SongTitle = 'Title1') OR (Artist = 'Artist2' AND SongTitle = 'Title1')",
+			pages:        1,
+		},
+		{
+			description:  "hash and range key IN",
+			criteria:     "Artist IN (?, ?) AND SongTitle IN (?, ?)",
+			parameters:   []interface{}{"Artist1", "Artist2", "Title1", "Title2"},
+			expectOK:     true,
+			operation:    batchGetItemOperation,
+			keys:         4,
+			keyCondition: "(Artist = 'Artist1' AND SongTitle = 'Title1') OR (Artist = 'Artist1' AND SongTitle = 'Title2') OR (Artist = 'Artist2' AND SongTitle = 'Title1') OR (Artist = 'Artist2' AND SongTitle = 'Title2')",
+			pages:        1,
+		},
+		{
+			description: "partial key",
+			criteria:    "Artist = ?",
+			parameters:  []interface{}{"Artist1"},
+		},
+		{
+			description: "non key attribute",
+			criteria:    "Artist = ? AND SongTitle = ? AND Genre = ?",
+			parameters:  []interface{}{"Artist1", "Title1", "Pop"},
+		},
+	}
+	for _, useCase := range useCases {
+		criteria, err := parseCriteria(useCase.criteria)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		plan := &Plan{}
+		ok, err := plan.planItems(criteria, useCase.parameters, keyNames)
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.expectOK, ok, useCase.description)
+		if !ok {
+			assert.Nil(t, plan.keys, useCase.description)
+			continue
+		}
+		assert.EqualValues(t, useCase.operation, plan.Operation, useCase.description)
+		assert.EqualValues(t, useCase.keys, plan.Keys, useCase.description)
+		assert.EqualValues(t, useCase.keyCondition, plan.KeyCondition, useCase.description)
+		assert.EqualValues(t, useCase.pages, plan.EstimatedPages, useCase.description)
+	}
+}
+
+func TestEstimatePages(t *testing.T) {
+	assert.EqualValues(t, 1, estimatePages(0))
+	assert.EqualValues(t, 1, estimatePages(pageSize))
+	assert.EqualValues(t, 2, estimatePages(pageSize+1))
+	assert.EqualValues(t, 100, estimatePages(100*pageSize))
+}
+
+func TestManager_CheckScan(t *testing.T) {
+	scan := &Plan{Operation: scanOperation, Table: "music"}
+	deleteAll := &Plan{Operation: scanOperation, Write: deleteItemOperation, Table: "music"}
+	hinted := &Plan{Operation: scanOperation, Write: deleteItemOperation, Table: "music", hints: hints{allowScanHint: ""}}
+	query := &Plan{Operation: queryOperation, Table: "music"}
+	var useCases = []struct {
+		description string
+		parameters  map[string]interface{}
+		plan        *Plan
+		hasError    bool
+	}{
+		{description: "scan allowed by default", plan: scan},
+		{description: "delete all allowed by default", plan: deleteAll},
+		{description: "scan disabled", parameters: map[string]interface{}{allowScanKey: false}, plan: scan, hasError: true},
+		{description: "delete all with scan disabled", parameters: map[string]interface{}{allowScanKey: false}, plan: deleteAll},
+		{description: "safe mode scan", parameters: map[string]interface{}{safeModeKey: true}, plan: scan, hasError: true},
+		{description: "safe mode delete all", parameters: map[string]interface{}{safeModeKey: true}, plan: deleteAll, hasError: true},
+		{description: "safe mode with scan enabled", parameters: map[string]interface{}{safeModeKey: true, allowScanKey: true}, plan: scan},
+		{description: "safe mode with hint", parameters: map[string]interface{}{safeModeKey: true}, plan: hinted},
+		{description: "safe mode query", parameters: map[string]interface{}{safeModeKey: true}, plan: query},
+	}
+	for _, useCase := range useCases {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", useCase.parameters)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		datastoreManager, err := newManagerFactory().Create(config)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		err = datastoreManager.(*manager).checkScan("SQL", useCase.plan)
+		assert.EqualValues(t, useCase.hasError, err != nil, useCase.description)
+	}
+}
@@ -0,0 +1,219 @@
+package dyndb
+
+import (
+	"fmt"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"strconv"
+	"strings"
+)
+
+//queryClauses represents ORDER BY and LIMIT clauses of SELECT statement
+type queryClauses struct {
+	orderBy    string //ORDER BY column, Query on index sort key applies the direction, otherwise items are returned in key order
+	descending bool
+	limit      int //max number of returned rows, 0 returns all rows
+}
+
+//parseQuery parses SELECT statement without WHERE clause, columns can use document paths i.e. SELECT Address.City, Tags[0] FROM users,
+//table alias, GROUP BY, ORDER BY and LIMIT clauses can follow table name, GROUP BY columns are parsed but not applied
+func parseQuery(SQL string) (*dsc.QueryStatement, *queryClauses, error) {
+	tokens, err := tokenize(SQL)
+	if err != nil {
+		return nil, nil, err
+	}
+	parser := &criteriaParser{tokens: tokens}
+	if !parser.acceptKeyword("SELECT") {
+		return nil, nil, parser.unexpected("SELECT")
+	}
+	result := &dsc.QueryStatement{BaseStatement: &dsc.BaseStatement{
+		SQL:         SQL,
+		SQLCriteria: &dsc.SQLCriteria{Criteria: make([]*dsc.SQLCriterion, 0)},
+	}}
+	if parser.acceptSymbol("*") {
+		result.AllField = true
+	} else {
+		expressionAlias := 1
+		for {
+			column, err := parser.parseColumn()
+			if err != nil {
+				return nil, nil, err
+			}
+			if column.Expression != "" && column.Alias == "" {
+				column.Alias = "f" + toolbox.AsString(expressionAlias)
+				expressionAlias++
+			}
+			result.Columns = append(result.Columns, column)
+			if !parser.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if !parser.acceptKeyword("FROM") {
+		return nil, nil, parser.unexpected("FROM")
+	}
+	table := parser.next()
+	if table.kind != identToken {
+		parser.index--
+		return nil, nil, parser.unexpected("table")
+	}
+	result.Table = table.text
+	if candidate := parser.peek(); candidate.kind == identToken && !isQueryClause(candidate.keyword()) {
+		result.Alias = parser.next().text
+	}
+	clauses, err := parser.parseQueryClauses(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parser.peek().kind != eofToken {
+		return nil, nil, parser.unexpected("end of statement")
+	}
+	return result, clauses, nil
+}
+
+func isQueryClause(keyword string) bool {
+	return keyword == "GROUP" || keyword == "ORDER" || keyword == "LIMIT"
+}
+
+//parseQueryClauses parses optional GROUP BY, ORDER BY and LIMIT clauses, GROUP BY columns are set on statement
+func (p *criteriaParser) parseQueryClauses(statement *dsc.QueryStatement) (*queryClauses, error) {
+	result := &queryClauses{}
+	if p.acceptKeyword("GROUP") {
+		if !p.acceptKeyword("BY") {
+			return nil, p.unexpected("BY")
+		}
+		for {
+			if p.peek().kind != identToken {
+				return nil, p.unexpected("GROUP BY column")
+			}
+			statement.GroupBy = append(statement.GroupBy, &dsc.SQLColumn{Name: p.next().text})
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("ORDER") {
+		if !p.acceptKeyword("BY") {
+			return nil, p.unexpected("BY")
+		}
+		if p.peek().kind != identToken {
+			return nil, p.unexpected("ORDER BY column")
+		}
+		result.orderBy = p.next().text
+		if p.acceptKeyword("DESC") {
+			result.descending = true
+		} else {
+			p.acceptKeyword("ASC")
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		value, err := strconv.Atoi(p.peek().text)
+		if p.peek().kind != numberToken || err != nil || value < 1 {
+			return nil, p.unexpected("positive LIMIT")
+		}
+		p.next()
+		result.limit = value
+	}
+	return result, nil
+}
+
+//parseColumn parses column path or aggregate function with optional alias
+func (p *criteriaParser) parseColumn() (*dsc.SQLColumn, error) {
+	result := &dsc.SQLColumn{}
+	operand, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch actual := operand.(type) {
+	case *pathExpr:
+		result.Name = actual.Path
+	case *callExpr:
+		var args = make([]string, 0)
+		for _, arg := range actual.Args {
+			switch value := arg.(type) {
+			case *pathExpr:
+				args = append(args, value.Path)
+			case *literalExpr:
+				args = append(args, toolbox.AsString(value.Value))
+			default:
+				return nil, fmt.Errorf("unsupported %v argument: %T", actual.Name, arg)
+			}
+		}
+		result.Function = actual.Name
+		result.FunctionArguments = strings.Join(args, ", ")
+		result.Expression = actual.Name + "(" + result.FunctionArguments + ")"
+	default:
+		return nil, fmt.Errorf("unsupported column: %T", operand)
+	}
+	if p.acceptKeyword("AS") || (p.peek().kind == identToken && p.peek().keyword() != "FROM") {
+		alias := p.next()
+		if alias.kind != identToken {
+			p.index--
+			return nil, p.unexpected("alias")
+		}
+		result.Alias = alias.text
+	}
+	return result, nil
+}
+
+//resultColumns returns query result column names, aliased columns use alias
+func resultColumns(statement *dsc.QueryStatement) []string {
+	var result = make([]string, 0)
+	for _, column := range statement.Columns {
+		if column.Alias != "" && column.Expression == "" {
+			result = append(result, column.Alias)
+			continue
+		}
+		result = append(result, column.Name)
+	}
+	return result
+}
+
+//isDocumentPath returns true if column references nested map or list element
+func isDocumentPath(name string) bool {
+	return strings.ContainsAny(name, ".[")
+}
+
+//documentValue returns value for document path i.e. Address.City, Tags[0]
+func documentValue(values map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = values
+	for _, element := range strings.Split(path, ".") {
+		name := element
+		if position := strings.Index(element, "["); position != -1 {
+			name = element[:position]
+		}
+		aMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = aMap[name]; !ok {
+			return nil, false
+		}
+		for _, index := range listIndexes(element) {
+			list, ok := current.([]interface{})
+			if !ok || index >= len(list) {
+				return nil, false
+			}
+			current = list[index]
+		}
+	}
+	return current, true
+}
+
+//listIndexes returns list indexes of a path element i.e. Matrix[1][2] -> 1, 2
+func listIndexes(element string) []int {
+	var result = make([]int, 0)
+	for {
+		begin := strings.Index(element, "[")
+		if begin == -1 {
+			return result
+		}
+		end := strings.Index(element[begin:], "]")
+		if end == -1 {
+			return result
+		}
+		index, _ := strconv.Atoi(element[begin+1 : begin+end])
+		result = append(result, index)
+		element = element[begin+end+1:]
+	}
+}
@@ -0,0 +1,155 @@
+package dyndb
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	var useCases = []struct {
+		description string
+		SQL         string
+		table       string
+		columns     []string
+		result      []string
+		projection  string
+		names       map[string]string
+		allField    bool
+		isCount     bool
+		clauses     queryClauses
+		groupBy     []string
+		hasError    bool
+	}{
+		{
+			description: "all fields",
+			SQL:         "SELECT * FROM music",
+			table:       "music",
+			columns:     []string{},
+			result:      []string{},
+			allField:    true,
+		},
+		{
+			description: "reserved words",
+			SQL:         "SELECT Date, User FROM events",
+			table:       "events",
+			columns:     []string{"Date", "User"},
+			result:      []string{"Date", "User"},
+			projection:  "#n1,#n2",
+			names:       map[string]string{"#n1": "Date", "#n2": "User"},
+		},
+		{
+			description: "document paths",
+			SQL:         "SELECT Address.City, Tags[0], Tags[1] AS second FROM users",
+			table:       "users",
+			columns:     []string{"Address.City", "Tags[0]", "Tags[1]"},
+			result:      []string{"Address.City", "Tags[0]", "second"},
+			projection:  "#n1.#n2,#n3",
+			names:       map[string]string{"#n1": "Address", "#n2": "City", "#n3": "Tags"},
+		},
+		{
+			description: "count",
+			SQL:         "SELECT COUNT(*) AS cnt FROM music",
+			table:       "music",
+			columns:     []string{""},
+			result:      []string{""},
+			isCount:     true,
+		},
+		{
+			description: "limit",
+			SQL:         "SELECT Artist FROM music LIMIT 10",
+			table:       "music",
+			columns:     []string{"Artist"},
+			result:      []string{"Artist"},
+			projection:  "#n1",
+			names:       map[string]string{"#n1": "Artist"},
+			clauses:     queryClauses{limit: 10},
+		},
+		{
+			description: "alias, group by, order by and limit",
+			SQL:         "SELECT Artist FROM music m GROUP BY Artist, Genre ORDER BY SongTitle DESC LIMIT 2",
+			table:       "music",
+			columns:     []string{"Artist"},
+			result:      []string{"Artist"},
+			projection:  "#n1",
+			names:       map[string]string{"#n1": "Artist"},
+			clauses:     queryClauses{orderBy: "SongTitle", descending: true, limit: 2},
+			groupBy:     []string{"Artist", "Genre"},
+		},
+		{
+			description: "order by ascending",
+			SQL:         "SELECT * FROM music ORDER BY SongTitle ASC",
+			table:       "music",
+			columns:     []string{},
+			result:      []string{},
+			allField:    true,
+			clauses:     queryClauses{orderBy: "SongTitle"},
+		},
+		{
+			description: "invalid limit",
+			SQL:         "SELECT Artist FROM music LIMIT 0",
+			hasError:    true,
+		},
+		{
+			description: "order without by",
+			SQL:         "SELECT Artist FROM music ORDER Artist",
+			hasError:    true,
+		},
+		{
+			description: "unsupported clause",
+			SQL:         "SELECT Artist FROM music HAVING Artist",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		statement, clauses, err := parseQuery(useCase.SQL)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.table, statement.Table, useCase.description)
+		assert.EqualValues(t, useCase.allField, statement.AllField, useCase.description)
+		assert.EqualValues(t, useCase.columns, statement.ColumnNames(), useCase.description)
+		assert.EqualValues(t, useCase.result, resultColumns(statement), useCase.description)
+		assert.EqualValues(t, useCase.clauses, *clauses, useCase.description)
+		var groupBy []string
+		for _, column := range statement.GroupBy {
+			groupBy = append(groupBy, column.Name)
+		}
+		assert.EqualValues(t, useCase.groupBy, groupBy, useCase.description)
+		builder := newExpressionBuilder(nil)
+		sel, projection := normalizeExpr(statement, builder)
+		assert.EqualValues(t, useCase.isCount, sel != nil, useCase.description)
+		if useCase.projection == "" {
+			assert.Nil(t, projection, useCase.description)
+			continue
+		}
+		if assert.NotNil(t, projection, useCase.description) {
+			assert.EqualValues(t, useCase.projection, *projection, useCase.description)
+		}
+		var names = make(map[string]string)
+		for k, v := range builder.attributeNames() {
+			names[k] = *v
+		}
+		assert.EqualValues(t, useCase.names, names, useCase.description)
+	}
+}
+
+func TestDocumentValue(t *testing.T) {
+	values := map[string]interface{}{
+		"Address": map[string]interface{}{"City": "Austin"},
+		"Tags":    []interface{}{"rock", []interface{}{"a", "b"}},
+	}
+	value, ok := documentValue(values, "Address.City")
+	assert.True(t, ok)
+	assert.EqualValues(t, "Austin", value)
+	value, ok = documentValue(values, "Tags[1][0]")
+	assert.True(t, ok)
+	assert.EqualValues(t, "a", value)
+	_, ok = documentValue(values, "Tags[3]")
+	assert.False(t, ok)
+	_, ok = documentValue(values, "Address.Zip")
+	assert.False(t, ok)
+}
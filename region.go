@@ -0,0 +1,440 @@
+package dyndb
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//regionsKey config parameter lists global table regions ordered by read preference i.e. nearest first, separated with ; or space,
+	//region config parameter is the primary region for writes, it defaults to the first listed region
+	regionsKey = "regions"
+	//failoverCooldownKey config parameter, time in ms a region is skipped after a regional error
+	failoverCooldownKey = "failoverCooldownMs"
+	//defaultFailoverCooldown default time a region is skipped after a regional error
+	defaultFailoverCooldown = 30 * time.Second
+	//failoverWritesKey config parameter, allows failing over non idempotent writes i.e. conditional writes or updates with ADD, which may be applied twice
+	failoverWritesKey = "failoverWrites"
+)
+
+//regionClient represents client of a global table replica region
+type regionClient struct {
+	dynamodbiface.DynamoDBAPI
+	region         string
+	unhealthyUntil time.Time
+}
+
+//multiRegionClient routes writes, strongly consistent and transactional reads to the primary region and other reads to the first healthy region in preference order,
+//a regional error marks the region unhealthy for a cooldown and the call fails over to the next region,
+//non idempotent writes fail over only with failoverWrites, other operations, including DDL, use the primary region
+type multiRegionClient struct {
+	dynamodbiface.DynamoDBAPI
+	reads          []*regionClient
+	writes         []*regionClient
+	cooldown       time.Duration
+	failoverWrites bool
+	mutex          sync.Mutex
+}
+
+//primaryClient returns primary region client for DDL and table metadata changes
+func primaryClient(db dynamodbiface.DynamoDBAPI) dynamodbiface.DynamoDBAPI {
+	if client, ok := db.(*multiRegionClient); ok {
+		return client.DynamoDBAPI
+	}
+	return db
+}
+
+//candidates returns healthy clients in supplied order followed by unhealthy ones as the last resort
+func (c *multiRegionClient) candidates(clients []*regionClient) []*regionClient {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	var healthy = make([]*regionClient, 0, len(clients))
+	var unhealthy = make([]*regionClient, 0)
+	for _, client := range clients {
+		if now.Before(client.unhealthyUntil) {
+			unhealthy = append(unhealthy, client)
+			continue
+		}
+		healthy = append(healthy, client)
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (c *multiRegionClient) markUnhealthy(client *regionClient) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	client.unhealthyUntil = time.Now().Add(c.cooldown)
+}
+
+//call runs operation with the first candidate region, it fails over to the next region on a regional error unless failover is disabled
+func (c *multiRegionClient) call(clients []*regionClient, failover bool, operation func(db dynamodbiface.DynamoDBAPI) error) error {
+	var err error
+	for _, client := range c.candidates(clients) {
+		if err = operation(client.DynamoDBAPI); err == nil || !isRegionalError(err) {
+			return err
+		}
+		dsc.Logf("[dynamoDB]: region %v failed, %v\n", client.region, err)
+		c.markUnhealthy(client)
+		if !failover {
+			return err
+		}
+	}
+	return err
+}
+
+//readClients returns clients for a read, strongly consistent reads use the primary region first as replicas are eventually consistent
+func (c *multiRegionClient) readClients(consistent bool) []*regionClient {
+	if consistent {
+		return c.writes
+	}
+	return c.reads
+}
+
+//canFailover returns true if write can be retried in another region, a timed out write may have been already applied
+func (c *multiRegionClient) canFailover(idempotent bool) bool {
+	return idempotent || c.failoverWrites
+}
+
+//DescribeTable uses the primary region first, as table ARN and status are used by DDL and tags, other regions are used only on a regional error
+func (c *multiRegionClient) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	return c.DescribeTableWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) DescribeTableWithContext(ctx aws.Context, input *dynamodb.DescribeTableInput, options ...request.Option) (output *dynamodb.DescribeTableOutput, err error) {
+	err = c.call(c.writes, true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.DescribeTableWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) ListTables(input *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+	return c.ListTablesWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) ListTablesWithContext(ctx aws.Context, input *dynamodb.ListTablesInput, options ...request.Option) (output *dynamodb.ListTablesOutput, err error) {
+	err = c.call(c.reads, true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.ListTablesWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	return c.GetItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) GetItemWithContext(ctx aws.Context, input *dynamodb.GetItemInput, options ...request.Option) (output *dynamodb.GetItemOutput, err error) {
+	err = c.call(c.readClients(aws.BoolValue(input.ConsistentRead)), true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.GetItemWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	return c.BatchGetItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) BatchGetItemWithContext(ctx aws.Context, input *dynamodb.BatchGetItemInput, options ...request.Option) (output *dynamodb.BatchGetItemOutput, err error) {
+	consistent := false
+	for _, keys := range input.RequestItems {
+		consistent = consistent || aws.BoolValue(keys.ConsistentRead)
+	}
+	err = c.call(c.readClients(consistent), true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.BatchGetItemWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	return c.QueryWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) QueryWithContext(ctx aws.Context, input *dynamodb.QueryInput, options ...request.Option) (output *dynamodb.QueryOutput, err error) {
+	err = c.call(c.readClients(aws.BoolValue(input.ConsistentRead)), true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.QueryWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	return c.ScanWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) ScanWithContext(ctx aws.Context, input *dynamodb.ScanInput, options ...request.Option) (output *dynamodb.ScanOutput, err error) {
+	err = c.call(c.readClients(aws.BoolValue(input.ConsistentRead)), true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.ScanWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+//TransactGetItems uses the primary region first, transactional reads are serializable only within a region
+func (c *multiRegionClient) TransactGetItems(input *dynamodb.TransactGetItemsInput) (*dynamodb.TransactGetItemsOutput, error) {
+	return c.TransactGetItemsWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) TransactGetItemsWithContext(ctx aws.Context, input *dynamodb.TransactGetItemsInput, options ...request.Option) (output *dynamodb.TransactGetItemsOutput, err error) {
+	err = c.call(c.writes, true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.TransactGetItemsWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	return c.PutItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) PutItemWithContext(ctx aws.Context, input *dynamodb.PutItemInput, options ...request.Option) (output *dynamodb.PutItemOutput, err error) {
+	err = c.call(c.writes, c.canFailover(input.ConditionExpression == nil), func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.PutItemWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	return c.UpdateItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) UpdateItemWithContext(ctx aws.Context, input *dynamodb.UpdateItemInput, options ...request.Option) (output *dynamodb.UpdateItemOutput, err error) {
+	idempotent := input.ConditionExpression == nil && isIdempotentUpdate(aws.StringValue(input.UpdateExpression))
+	err = c.call(c.writes, c.canFailover(idempotent), func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.UpdateItemWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	return c.DeleteItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) DeleteItemWithContext(ctx aws.Context, input *dynamodb.DeleteItemInput, options ...request.Option) (output *dynamodb.DeleteItemOutput, err error) {
+	err = c.call(c.writes, c.canFailover(input.ConditionExpression == nil), func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.DeleteItemWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	return c.BatchWriteItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) BatchWriteItemWithContext(ctx aws.Context, input *dynamodb.BatchWriteItemInput, options ...request.Option) (output *dynamodb.BatchWriteItemOutput, err error) {
+	err = c.call(c.writes, true, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.BatchWriteItemWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+//TransactWriteItems fails over only transactions with unconditional puts and deletes
+func (c *multiRegionClient) TransactWriteItems(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	return c.TransactWriteItemsWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) TransactWriteItemsWithContext(ctx aws.Context, input *dynamodb.TransactWriteItemsInput, options ...request.Option) (output *dynamodb.TransactWriteItemsOutput, err error) {
+	idempotent := true
+	for _, item := range input.TransactItems {
+		switch {
+		case item.Put != nil:
+			idempotent = idempotent && item.Put.ConditionExpression == nil
+		case item.Delete != nil:
+			idempotent = idempotent && item.Delete.ConditionExpression == nil
+		default:
+			idempotent = false
+		}
+	}
+	err = c.call(c.writes, c.canFailover(idempotent), func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.TransactWriteItemsWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+//ExecuteStatement routes PartiQL SELECT as read, other statements as non idempotent write
+func (c *multiRegionClient) ExecuteStatement(input *dynamodb.ExecuteStatementInput) (*dynamodb.ExecuteStatementOutput, error) {
+	return c.ExecuteStatementWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) ExecuteStatementWithContext(ctx aws.Context, input *dynamodb.ExecuteStatementInput, options ...request.Option) (output *dynamodb.ExecuteStatementOutput, err error) {
+	clients, failover := c.writes, c.failoverWrites
+	if isPartiQLSelect(input.Statement) {
+		clients, failover = c.readClients(aws.BoolValue(input.ConsistentRead)), true
+	}
+	err = c.call(clients, failover, func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.ExecuteStatementWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) BatchExecuteStatement(input *dynamodb.BatchExecuteStatementInput) (*dynamodb.BatchExecuteStatementOutput, error) {
+	return c.BatchExecuteStatementWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *multiRegionClient) BatchExecuteStatementWithContext(ctx aws.Context, input *dynamodb.BatchExecuteStatementInput, options ...request.Option) (output *dynamodb.BatchExecuteStatementOutput, err error) {
+	idempotent := true
+	for _, statement := range input.Statements {
+		idempotent = idempotent && isPartiQLSelect(statement.Statement)
+	}
+	err = c.call(c.writes, c.canFailover(idempotent), func(db dynamodbiface.DynamoDBAPI) (err error) {
+		output, err = db.BatchExecuteStatementWithContext(ctx, input, options...)
+		return err
+	})
+	return output, err
+}
+
+func (c *multiRegionClient) QueryPages(input *dynamodb.QueryInput, handler func(*dynamodb.QueryOutput, bool) bool) error {
+	return c.QueryPagesWithContext(aws.BackgroundContext(), input, handler)
+}
+
+//QueryPagesWithContext reads pages from the first healthy region, it fails over only till the first page is handled
+func (c *multiRegionClient) QueryPagesWithContext(ctx aws.Context, input *dynamodb.QueryInput, handler func(*dynamodb.QueryOutput, bool) bool, options ...request.Option) error {
+	var handled bool
+	return c.callPages(c.readClients(aws.BoolValue(input.ConsistentRead)), &handled, func(db dynamodbiface.DynamoDBAPI) error {
+		return db.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+			handled = true
+			return handler(output, lastPage)
+		}, options...)
+	})
+}
+
+func (c *multiRegionClient) ScanPages(input *dynamodb.ScanInput, handler func(*dynamodb.ScanOutput, bool) bool) error {
+	return c.ScanPagesWithContext(aws.BackgroundContext(), input, handler)
+}
+
+//ScanPagesWithContext reads pages from the first healthy region, it fails over only till the first page is handled
+func (c *multiRegionClient) ScanPagesWithContext(ctx aws.Context, input *dynamodb.ScanInput, handler func(*dynamodb.ScanOutput, bool) bool, options ...request.Option) error {
+	var handled bool
+	return c.callPages(c.readClients(aws.BoolValue(input.ConsistentRead)), &handled, func(db dynamodbiface.DynamoDBAPI) error {
+		return db.ScanPagesWithContext(ctx, input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+			handled = true
+			return handler(output, lastPage)
+		}, options...)
+	})
+}
+
+//callPages runs pages operation with the first candidate region, a regional error after a handled page is returned as pages would be repeated
+func (c *multiRegionClient) callPages(clients []*regionClient, handled *bool, operation func(db dynamodbiface.DynamoDBAPI) error) error {
+	var err error
+	for _, client := range c.candidates(clients) {
+		if err = operation(client.DynamoDBAPI); err == nil || !isRegionalError(err) {
+			return err
+		}
+		dsc.Logf("[dynamoDB]: region %v failed, %v\n", client.region, err)
+		c.markUnhealthy(client)
+		if *handled {
+			return err
+		}
+	}
+	return err
+}
+
+func isPartiQLSelect(statement *string) bool {
+	return hasKeywordPrefix(strings.TrimSpace(aws.StringValue(statement)), "SELECT")
+}
+
+//isIdempotentUpdate returns false if update expression depends on the current item value i.e. ADD, DELETE, list_append or arithmetic
+func isIdempotentUpdate(expression string) bool {
+	for _, field := range strings.FieldsFunc(expression, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '(' || r == ')' || r == '\t' || r == '\n'
+	}) {
+		switch strings.ToUpper(field) {
+		case "ADD", "DELETE", "LIST_APPEND":
+			return false
+		}
+	}
+	return !strings.ContainsAny(expression, "+-")
+}
+
+//isRegionalError returns true for network, timeout and server side errors that justify failing over to another region
+func isRegionalError(err error) bool {
+	var failure awserr.RequestFailure
+	if errors.As(err, &failure) && failure.StatusCode() >= 500 {
+		return true
+	}
+	var awsError awserr.Error
+	if errors.As(err, &awsError) {
+		switch awsError.Code() {
+		case request.ErrCodeRequestError, request.ErrCodeResponseTimeout, dynamodb.ErrCodeInternalServerError, "ServiceUnavailable":
+			return true
+		}
+		return false
+	}
+	var netError net.Error
+	return errors.As(err, &netError)
+}
+
+//getRegions returns configured regions with primary region first in writes and preference order in reads
+func getRegions(config *dsc.Config) (primary string, reads []string) {
+	if !config.Has(regionsKey) {
+		return "", nil
+	}
+	value := config.Parameters[regionsKey]
+	if toolbox.IsSlice(value) {
+		for _, region := range toolbox.AsSlice(value) {
+			reads = append(reads, toolbox.AsString(region))
+		}
+	} else {
+		reads = strings.FieldsFunc(toolbox.AsString(value), func(r rune) bool {
+			return r == ';' || r == ' '
+		})
+	}
+	if len(reads) == 0 {
+		return "", nil
+	}
+	primary = reads[0]
+	if config.Has(regionKey) {
+		primary = config.Get(regionKey)
+	}
+	return primary, reads
+}
+
+//newMultiRegionClient creates client per region with supplied factory
+func newMultiRegionClient(config *dsc.Config, sess *session.Session, awsConfig *aws.Config, clientFactory ClientFactory) (dynamodbiface.DynamoDBAPI, error) {
+	primary, regions := getRegions(config)
+	result := &multiRegionClient{
+		cooldown:       config.GetDuration(failoverCooldownKey, time.Millisecond, defaultFailoverCooldown),
+		failoverWrites: config.GetBoolean(failoverWritesKey, false),
+	}
+	var clients = make(map[string]*regionClient)
+	for _, region := range append([]string{primary}, regions...) {
+		if _, ok := clients[region]; ok {
+			continue
+		}
+		db, err := clientFactory(sess, awsConfig.Copy().WithRegion(region))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %v DynamoDB client, %v", region, err)
+		}
+		clients[region] = &regionClient{DynamoDBAPI: db, region: region}
+		result.writes = append(result.writes, clients[region])
+	}
+	for _, region := range regions {
+		result.reads = append(result.reads, clients[region])
+	}
+	if _, ok := clients[primary]; ok && len(result.reads) < len(result.writes) {
+		result.reads = append(result.reads, clients[primary]) //primary not listed in regions
+	}
+	result.DynamoDBAPI = clients[primary].DynamoDBAPI
+	return result, nil
+}
@@ -0,0 +1,448 @@
+package dyndb
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetRegions(t *testing.T) {
+	var useCases = []struct {
+		description string
+		parameters  map[string]interface{}
+		primary     string
+		reads       []string
+	}{
+		{
+			description: "single region",
+			parameters:  map[string]interface{}{"region": "us-west-1"},
+		},
+		{
+			description: "primary defaults to the first region",
+			parameters:  map[string]interface{}{"regions": "eu-west-1; us-east-1"},
+			primary:     "eu-west-1",
+			reads:       []string{"eu-west-1", "us-east-1"},
+		},
+		{
+			description: "explicit primary",
+			parameters:  map[string]interface{}{"region": "us-east-1", "regions": []interface{}{"eu-west-1", "us-east-1"}},
+			primary:     "us-east-1",
+			reads:       []string{"eu-west-1", "us-east-1"},
+		},
+	}
+	for _, useCase := range useCases {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", useCase.parameters)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		primary, reads := getRegions(config)
+		assert.EqualValues(t, useCase.primary, primary, useCase.description)
+		assert.EqualValues(t, useCase.reads, reads, useCase.description)
+	}
+}
+
+func TestIsRegionalError(t *testing.T) {
+	var useCases = []struct {
+		description string
+		err         error
+		expect      bool
+	}{
+		{
+			description: "server error",
+			err:         awserr.NewRequestFailure(awserr.New(dynamodb.ErrCodeInternalServerError, "internal error", nil), 500, "1"),
+			expect:      true,
+		},
+		{
+			description: "service unavailable",
+			err:         awserr.NewRequestFailure(awserr.New("ServiceUnavailable", "unavailable", nil), 503, "1"),
+			expect:      true,
+		},
+		{
+			description: "connection error",
+			err:         awserr.New(request.ErrCodeRequestError, "send request failed", &net.OpError{Op: "dial", Err: errors.New("connection refused")}),
+			expect:      true,
+		},
+		{
+			description: "client error",
+			err:         awserr.NewRequestFailure(awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil), 400, "1"),
+		},
+		{
+			description: "missing table",
+			err:         awserr.NewRequestFailure(awserr.New(dynamodb.ErrCodeResourceNotFoundException, "not found", nil), 400, "1"),
+		},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, isRegionalError(useCase.err), useCase.description)
+	}
+}
+
+//regionCalls counts requests sent per region
+type regionCalls struct {
+	mutex sync.Mutex
+	calls map[string]int
+}
+
+func (c *regionCalls) reset() map[string]int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	result := c.calls
+	c.calls = make(map[string]int)
+	return result
+}
+
+func TestMultiRegionClient(t *testing.T) {
+	unavailable := httptest.NewServer(nil)
+	unavailable.Close()
+	unavailableURL, _ := url.Parse(unavailable.URL)
+	var useCases = []struct {
+		description string
+		cooldown    interface{}
+		firstWrite  map[string]int
+		secondWrite map[string]int
+		read        map[string]int
+	}{
+		{
+			description: "failed primary skipped during cooldown",
+			firstWrite:  map[string]int{"us-east-1": 1, "eu-west-1": 1},
+			secondWrite: map[string]int{"eu-west-1": 1},
+			read:        map[string]int{"eu-west-1": 1},
+		},
+		{
+			description: "failed primary retried without cooldown",
+			cooldown:    0,
+			firstWrite:  map[string]int{"us-east-1": 1, "eu-west-1": 1},
+			secondWrite: map[string]int{"us-east-1": 1, "eu-west-1": 1},
+			read:        map[string]int{"eu-west-1": 1},
+		},
+	}
+	for _, useCase := range useCases {
+		parameters := map[string]interface{}{
+			"endpoint": "memory://region",
+			"region":   "us-east-1",
+			"regions":  "eu-west-1;us-east-1",
+		}
+		if useCase.cooldown != nil {
+			parameters["failoverCooldownMs"] = useCase.cooldown
+		}
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", parameters)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		counter := &regionCalls{calls: make(map[string]int)}
+		var primaryDown int32
+		manager, err := NewManagerFactory(func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+			region := aws.StringValue(awsConfig.Region)
+			client := dynamodb.New(sess, awsConfig.Copy().WithMaxRetries(0))
+			client.Handlers.Send.PushFront(func(r *request.Request) {
+				if region == "us-east-1" && atomic.LoadInt32(&primaryDown) == 1 {
+					r.HTTPRequest.URL.Host = unavailableURL.Host
+				}
+				counter.mutex.Lock()
+				defer counter.mutex.Unlock()
+				counter.calls[region]++
+			})
+			return client, nil
+		}).Create(config)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		for _, SQL := range []string{"DROP TABLE IF EXISTS users", "CREATE TABLE users(Id INT HASH KEY)"} {
+			_, err = manager.Execute(SQL)
+			assert.Nil(t, err, SQL)
+		}
+		var records = make([]map[string]interface{}, 0)
+		err = manager.ReadAll(&records, "SELECT Id, Name FROM users WHERE Id = ?", []interface{}{1}, nil)
+		assert.Nil(t, err, useCase.description)
+		assert.Len(t, records, 0, useCase.description)
+
+		atomic.StoreInt32(&primaryDown, 1)
+		counter.reset()
+		_, err = manager.Execute("INSERT INTO users(Id, Name) VALUES(?, ?)", 1, "user1")
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.firstWrite, counter.reset(), useCase.description)
+
+		_, err = manager.Execute("INSERT INTO users(Id, Name) VALUES(?, ?)", 2, "user2")
+		assert.Nil(t, err, useCase.description)
+		assert.EqualValues(t, useCase.secondWrite, counter.reset(), useCase.description)
+
+		records = make([]map[string]interface{}, 0)
+		err = manager.ReadAll(&records, "SELECT Id, Name FROM users WHERE Id = ?", []interface{}{2}, nil)
+		if assert.Nil(t, err, useCase.description) && assert.Len(t, records, 1, useCase.description) {
+			assert.EqualValues(t, "user2", records[0]["Name"], useCase.description)
+		}
+		assert.EqualValues(t, useCase.read, counter.reset(), useCase.description)
+	}
+}
+
+func TestMultiRegionClient_PrimaryOnly(t *testing.T) {
+	unavailable := httptest.NewServer(nil)
+	unavailable.Close()
+	unavailableURL, _ := url.Parse(unavailable.URL)
+	key := map[string]*dynamodb.AttributeValue{"Id": {N: aws.String("1")}}
+	conditionalUpdate := func(db dynamodbiface.DynamoDBAPI) error {
+		_, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+			TableName:                 aws.String("users"),
+			Key:                       key,
+			UpdateExpression:          aws.String("SET #n = :v"),
+			ConditionExpression:       aws.String("attribute_exists(#n)"),
+			ExpressionAttributeNames:  map[string]*string{"#n": aws.String("Name")},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":v": {S: aws.String("user2")}},
+		})
+		return err
+	}
+	var useCases = []struct {
+		description    string
+		failoverWrites bool
+		SQL            string
+		call           func(db dynamodbiface.DynamoDBAPI) error
+		hasError       bool
+		expect         map[string]int
+	}{
+		{
+			description: "conditional update not failed over",
+			call:        conditionalUpdate,
+			hasError:    true,
+			expect:      map[string]int{"us-east-1": 1},
+		},
+		{
+			description:    "conditional update failed over with failoverWrites",
+			failoverWrites: true,
+			call:           conditionalUpdate,
+			expect:         map[string]int{"us-east-1": 1, "eu-west-1": 1},
+		},
+		{
+			description: "update with ADD not failed over",
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				_, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+					TableName:                 aws.String("users"),
+					Key:                       key,
+					UpdateExpression:          aws.String("ADD #n :v"),
+					ExpressionAttributeNames:  map[string]*string{"#n": aws.String("Visits")},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":v": {N: aws.String("1")}},
+				})
+				return err
+			},
+			hasError: true,
+			expect:   map[string]int{"us-east-1": 1},
+		},
+		{
+			description: "unconditional put failed over",
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				_, err := db.PutItem(&dynamodb.PutItemInput{
+					TableName: aws.String("users"),
+					Item:      map[string]*dynamodb.AttributeValue{"Id": {N: aws.String("2")}},
+				})
+				return err
+			},
+			expect: map[string]int{"us-east-1": 1, "eu-west-1": 1},
+		},
+		{
+			description:    "DDL describe sent to primary only",
+			failoverWrites: true,
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				_, err := primaryClient(db).DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("users")})
+				return err
+			},
+			hasError: true,
+			expect:   map[string]int{"us-east-1": 1},
+		},
+		{
+			description:    "DDL sent to primary only",
+			failoverWrites: true,
+			SQL:            "CREATE TABLE IF NOT EXISTS users(Id INT HASH KEY)",
+			hasError:       true,
+			expect:         map[string]int{"us-east-1": 2},
+		},
+	}
+	for _, useCase := range useCases {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+			"endpoint":       "memory://region",
+			"region":         "us-east-1",
+			"regions":        "eu-west-1;us-east-1",
+			"failoverWrites": useCase.failoverWrites,
+		})
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		counter := &regionCalls{calls: make(map[string]int)}
+		var primaryDown int32
+		manager, err := NewManagerFactory(func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+			region := aws.StringValue(awsConfig.Region)
+			client := dynamodb.New(sess, awsConfig.Copy().WithMaxRetries(0))
+			client.Handlers.Send.PushFront(func(r *request.Request) {
+				if region == "us-east-1" && atomic.LoadInt32(&primaryDown) == 1 {
+					r.HTTPRequest.URL.Host = unavailableURL.Host
+				}
+				counter.mutex.Lock()
+				defer counter.mutex.Unlock()
+				counter.calls[region]++
+			})
+			return client, nil
+		}).Create(config)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		for _, SQL := range []string{"DROP TABLE IF EXISTS users", "CREATE TABLE users(Id INT HASH KEY)"} {
+			_, err = manager.Execute(SQL)
+			assert.Nil(t, err, SQL)
+		}
+		_, err = manager.Execute("INSERT INTO users(Id, Name) VALUES(?, ?)", 1, "user1")
+		assert.Nil(t, err, useCase.description)
+		connection, err := manager.ConnectionProvider().Get()
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		db, err := asDatabase(connection)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		atomic.StoreInt32(&primaryDown, 1)
+		counter.reset()
+		if useCase.SQL != "" {
+			_, err = manager.Execute(useCase.SQL)
+		} else {
+			err = useCase.call(db)
+		}
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+		} else {
+			assert.Nil(t, err, useCase.description)
+		}
+		assert.EqualValues(t, useCase.expect, counter.reset(), useCase.description)
+		connection.Close()
+	}
+}
+
+func TestMultiRegionClient_ConsistentRead(t *testing.T) {
+	key := map[string]*dynamodb.AttributeValue{"Id": {N: aws.String("1")}}
+	var useCases = []struct {
+		description string
+		SQL         string
+		call        func(db dynamodbiface.DynamoDBAPI) error
+		hasError    bool
+		expect      map[string]int
+	}{
+		{
+			description: "eventually consistent get from nearest region",
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				_, err := db.GetItem(&dynamodb.GetItemInput{TableName: aws.String("users"), Key: key})
+				return err
+			},
+			expect: map[string]int{"eu-west-1": 1},
+		},
+		{
+			description: "consistent get from primary",
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				_, err := db.GetItem(&dynamodb.GetItemInput{TableName: aws.String("users"), Key: key, ConsistentRead: aws.Bool(true)})
+				return err
+			},
+			expect: map[string]int{"us-east-1": 1},
+		},
+		{
+			description: "consistent batch get from primary",
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				_, err := db.BatchGetItem(&dynamodb.BatchGetItemInput{RequestItems: map[string]*dynamodb.KeysAndAttributes{
+					"users": {Keys: []map[string]*dynamodb.AttributeValue{key}, ConsistentRead: aws.Bool(true)},
+				}})
+				return err
+			},
+			expect: map[string]int{"us-east-1": 1},
+		},
+		{
+			description: "consistent scan pages from primary",
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				return db.ScanPages(&dynamodb.ScanInput{TableName: aws.String("users"), ConsistentRead: aws.Bool(true)}, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+					return true
+				})
+			},
+			expect: map[string]int{"us-east-1": 1},
+		},
+		{
+			description: "transactional get from primary, not emulated by memdb",
+			call: func(db dynamodbiface.DynamoDBAPI) error {
+				_, err := db.TransactGetItems(&dynamodb.TransactGetItemsInput{TransactItems: []*dynamodb.TransactGetItem{
+					{Get: &dynamodb.Get{TableName: aws.String("users"), Key: key}},
+				}})
+				return err
+			},
+			hasError: true,
+			expect:   map[string]int{"us-east-1": 1},
+		},
+		{
+			description: "consistent hint query from primary",
+			SQL:         "SELECT /*+ CONSISTENT */ Id, Name FROM users WHERE Id = 1",
+			expect:      map[string]int{"us-east-1": 1},
+		},
+		{
+			description: "query from nearest region",
+			SQL:         "SELECT Id, Name FROM users WHERE Id = 1",
+			expect:      map[string]int{"eu-west-1": 1},
+		},
+	}
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://region",
+		"region":   "us-east-1",
+		"regions":  "eu-west-1;us-east-1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	counter := &regionCalls{calls: make(map[string]int)}
+	manager, err := NewManagerFactory(func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+		region := aws.StringValue(awsConfig.Region)
+		client := dynamodb.New(sess, awsConfig.Copy().WithMaxRetries(0))
+		client.Handlers.Send.PushFront(func(r *request.Request) {
+			counter.mutex.Lock()
+			defer counter.mutex.Unlock()
+			counter.calls[region]++
+		})
+		return client, nil
+	}).Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS users", "CREATE TABLE users(Id INT HASH KEY)"} {
+		_, err = manager.Execute(SQL)
+		assert.Nil(t, err, SQL)
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, useCase := range useCases {
+		var records = make([]map[string]interface{}, 0)
+		if useCase.SQL != "" {
+			err = manager.ReadAll(&records, useCase.SQL, nil, nil)
+			assert.Nil(t, err, useCase.description)
+		}
+		counter.reset()
+		if useCase.SQL != "" {
+			err = manager.ReadAll(&records, useCase.SQL, nil, nil)
+		} else {
+			err = useCase.call(db)
+		}
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+		} else {
+			assert.Nil(t, err, useCase.description)
+		}
+		assert.EqualValues(t, useCase.expect, counter.reset(), useCase.description)
+	}
+}
@@ -0,0 +1,68 @@
+package dyndb
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"strings"
+)
+
+//returnValuesHint sets UPDATE returned item images i.e. UPDATE /*+ RETURN_VALUES(UPDATED_OLD) */ music SET Price = ? WHERE Artist = ? AND SongTitle = ?
+const returnValuesHint = "RETURN_VALUES"
+
+//returnedItems collects item images returned by PutItem, UpdateItem and DeleteItem into sql.Out parameter destination
+type returnedItems struct {
+	destination *[]map[string]interface{}
+}
+
+//add appends item image to destination, empty images are skipped
+func (r *returnedItems) add(attributes map[string]*dynamodb.AttributeValue) error {
+	if r == nil || len(attributes) == 0 {
+		return nil
+	}
+	var item = make(map[string]interface{})
+	if err := unmarshalMap(attributes, &item); err != nil {
+		return err
+	}
+	*r.destination = append(*r.destination, item)
+	return nil
+}
+
+//returnValues returns ReturnValues option, NONE if images are not collected, RETURN_VALUES hint overrides default value
+func (r *returnedItems) returnValues(queryHints hints, defaultValue string) string {
+	if r == nil {
+		return dynamodb.ReturnValueNone
+	}
+	if value := queryHints[returnValuesHint]; value != "" {
+		return strings.ToUpper(value)
+	}
+	return defaultValue
+}
+
+//outParameter removes sql.Out parameter from parameters, its destination has to be *[]map[string]interface{}
+func outParameter(parameters []interface{}) ([]interface{}, *returnedItems, error) {
+	var result = make([]interface{}, 0, len(parameters))
+	var returned *returnedItems
+	for _, parameter := range parameters {
+		var out *sql.Out
+		switch actual := parameter.(type) {
+		case sql.Out:
+			out = &actual
+		case *sql.Out:
+			out = actual
+		default:
+			result = append(result, parameter)
+			continue
+		}
+		destination, ok := out.Dest.(*[]map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("unsupported sql.Out destination: %T, expected *[]map[string]interface{}", out.Dest)
+		}
+		if returned != nil {
+			return nil, nil, fmt.Errorf("multiple sql.Out parameters are not supported")
+		}
+		*destination = make([]map[string]interface{}, 0)
+		returned = &returnedItems{destination: destination}
+	}
+	return result, returned, nil
+}
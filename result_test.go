@@ -0,0 +1,34 @@
+package dyndb
+
+import (
+	"database/sql"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOutParameter(t *testing.T) {
+	var images []map[string]interface{}
+	parameters, returned, err := outParameter([]interface{}{9.99, sql.Out{Dest: &images}, "Artist1"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, []interface{}{9.99, "Artist1"}, parameters)
+	assert.EqualValues(t, dynamodb.ReturnValueAllNew, returned.returnValues(nil, dynamodb.ReturnValueAllNew))
+	assert.EqualValues(t, dynamodb.ReturnValueUpdatedOld, returned.returnValues(hints{returnValuesHint: "updated_old"}, dynamodb.ReturnValueAllNew))
+	assert.Nil(t, returned.add(nil))
+	assert.Nil(t, returned.add(map[string]*dynamodb.AttributeValue{"Artist": {S: aws.String("Artist1")}, "Price": {N: aws.String("9.99")}}))
+	assert.EqualValues(t, []map[string]interface{}{{"Artist": "Artist1", "Price": 9.99}}, images)
+
+	parameters, returned, err = outParameter([]interface{}{"Artist1"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{"Artist1"}, parameters)
+	assert.Nil(t, returned)
+	assert.EqualValues(t, dynamodb.ReturnValueNone, returned.returnValues(hints{returnValuesHint: "ALL_OLD"}, dynamodb.ReturnValueAllNew))
+	assert.Nil(t, returned.add(map[string]*dynamodb.AttributeValue{"Artist": {S: aws.String("Artist1")}}))
+
+	var count int
+	_, _, err = outParameter([]interface{}{sql.Out{Dest: &count}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,260 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	//sampleSizeKey config parameter number of items scanned to infer non key attributes
+	sampleSizeKey     = "sampleSize"
+	defaultSampleSize = 100
+	nullAttributeType = "NULL"
+)
+
+//AttributeSchema represents attribute inferred from table key schema and sampled items
+type AttributeSchema struct {
+	Name     string
+	Type     string         //dominant DynamoDB type: S, N, B, BOOL, L, M, SS, NS, BS or NULL if only nulls were sampled
+	Types    map[string]int //number of sampled items by attribute type
+	Count    int            //number of sampled items with non null attribute
+	Nullable bool           //true if attribute is missing or null in some sampled items
+	Integer  bool           //true if all sampled numbers are integers
+	KeyType  string         //HASH or RANGE for primary key attributes
+}
+
+//ScanType returns Go type suitable for attribute values
+func (a *AttributeSchema) ScanType() reflect.Type {
+	switch a.Type {
+	case dynamodb.ScalarAttributeTypeS:
+		return reflect.TypeOf("")
+	case dynamodb.ScalarAttributeTypeN:
+		if a.Integer {
+			return reflect.TypeOf(int64(0))
+		}
+		return reflect.TypeOf(float64(0))
+	case dynamodb.ScalarAttributeTypeB:
+		return reflect.TypeOf([]byte{})
+	case "BOOL":
+		return reflect.TypeOf(false)
+	case "SS":
+		return reflect.TypeOf([]string{})
+	case "NS":
+		return reflect.TypeOf([]float64{})
+	case "BS":
+		return reflect.TypeOf([][]byte{})
+	case "L":
+		return reflect.TypeOf([]interface{}{})
+	case "M":
+		return reflect.TypeOf(map[string]interface{}{})
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
+
+//IndexSchema represents table secondary index
+type IndexSchema struct {
+	Name   string
+	Global bool
+	Keys   []string //hash key followed by optional range key
+}
+
+//TableSchema represents table key schema, secondary indexes and attributes inferred from sampled items
+type TableSchema struct {
+	Table      string
+	PkColumns  []string //hash key followed by optional range key
+	Indexes    []*IndexSchema
+	Attributes []*AttributeSchema //key attributes followed by other attributes sorted by name
+	Sampled    int
+}
+
+//Attribute returns attribute schema by name or nil
+func (s *TableSchema) Attribute(name string) *AttributeSchema {
+	for _, attribute := range s.Attributes {
+		if attribute.Name == name {
+			return attribute
+		}
+	}
+	return nil
+}
+
+//Columns returns attributes as dsc columns
+func (s *TableSchema) Columns() []dsc.Column {
+	var result = make([]dsc.Column, 0, len(s.Attributes))
+	for _, attribute := range s.Attributes {
+		nullable := attribute.Nullable
+		result = append(result, dsc.NewColumn(attribute.Name, attribute.Type, nil, nil, nil, attribute.ScanType(), &nullable))
+	}
+	return result
+}
+
+//TableDescriptor returns table descriptor with primary key, column types and nullability
+func (s *TableSchema) TableDescriptor() *dsc.TableDescriptor {
+	result := &dsc.TableDescriptor{
+		Table:       s.Table,
+		PkColumns:   s.PkColumns,
+		Columns:     make([]string, 0, len(s.Attributes)),
+		ColumnTypes: make(map[string]string),
+		Nullables:   make(map[string]bool),
+	}
+	for _, attribute := range s.Attributes {
+		result.Columns = append(result.Columns, attribute.Name)
+		result.ColumnTypes[attribute.Name] = attribute.Type
+		result.Nullables[attribute.Name] = attribute.Nullable
+	}
+	return result
+}
+
+//configSampleSize returns sampleSize config parameter or 100
+func configSampleSize(config *dsc.Config) int {
+	if config.Has(sampleSizeKey) {
+		return toolbox.AsInt(config.Get(sampleSizeKey))
+	}
+	return defaultSampleSize
+}
+
+//InferSchema returns table schema based on DescribeTable and up to sampleSize scanned items, if sampleSize is 0 sampleSize config parameter or 100 is used
+func InferSchema(manager dsc.Manager, table string, sampleSize int) (*TableSchema, error) {
+	if sampleSize == 0 {
+		sampleSize = configSampleSize(manager.Config())
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if err != nil {
+		return nil, err
+	}
+	description, err := describeCached(manager, db, tableName(manager.Config(), table))
+	if err != nil {
+		return nil, err
+	}
+	items, err := sampleItems(db, *description.TableName, sampleSize)
+	if err != nil {
+		return nil, err
+	}
+	result := newTableSchema(description, items)
+	result.Table = table
+	return result, nil
+}
+
+func sampleItems(db dynamodbiface.DynamoDBAPI, table string, sampleSize int) ([]map[string]*dynamodb.AttributeValue, error) {
+	var result = make([]map[string]*dynamodb.AttributeValue, 0)
+	if sampleSize <= 0 {
+		return result, nil
+	}
+	input := &dynamodb.ScanInput{TableName: aws.String(table), Limit: aws.Int64(int64(sampleSize))}
+	err := db.ScanPagesWithContext(context.Background(), input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		result = append(result, output.Items...)
+		return len(result) < sampleSize
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) > sampleSize {
+		result = result[:sampleSize]
+	}
+	return result, nil
+}
+
+func newTableSchema(description *dynamodb.TableDescription, items []map[string]*dynamodb.AttributeValue) *TableSchema {
+	result := &TableSchema{
+		Table:      aws.StringValue(description.TableName),
+		PkColumns:  keySchemaNames(description.KeySchema),
+		Indexes:    make([]*IndexSchema, 0),
+		Attributes: make([]*AttributeSchema, 0),
+		Sampled:    len(items),
+	}
+	for _, index := range description.GlobalSecondaryIndexes {
+		result.Indexes = append(result.Indexes, &IndexSchema{Name: aws.StringValue(index.IndexName), Global: true, Keys: keySchemaNames(index.KeySchema)})
+	}
+	for _, index := range description.LocalSecondaryIndexes {
+		result.Indexes = append(result.Indexes, &IndexSchema{Name: aws.StringValue(index.IndexName), Keys: keySchemaNames(index.KeySchema)})
+	}
+	var attributes = make(map[string]*AttributeSchema)
+	definedTypes := make(map[string]string)
+	for _, definition := range description.AttributeDefinitions {
+		definedTypes[aws.StringValue(definition.AttributeName)] = aws.StringValue(definition.AttributeType)
+	}
+	for i, name := range result.PkColumns {
+		keyType := dynamodb.KeyTypeHash
+		if i > 0 {
+			keyType = dynamodb.KeyTypeRange
+		}
+		attribute := &AttributeSchema{Name: name, Type: definedTypes[name], Types: make(map[string]int), KeyType: keyType, Integer: true}
+		attributes[name] = attribute
+		result.Attributes = append(result.Attributes, attribute)
+	}
+	var names = make([]string, 0)
+	for _, definition := range description.AttributeDefinitions {
+		name := aws.StringValue(definition.AttributeName)
+		if _, ok := attributes[name]; ok {
+			continue
+		}
+		attributes[name] = &AttributeSchema{Name: name, Type: definedTypes[name], Types: make(map[string]int), Integer: true}
+		names = append(names, name)
+	}
+	for _, item := range items {
+		for name, value := range item {
+			attribute, ok := attributes[name]
+			if !ok {
+				attribute = &AttributeSchema{Name: name, Types: make(map[string]int), Integer: true}
+				attributes[name] = attribute
+				names = append(names, name)
+			}
+			attributeType := nullAttributeType
+			if !aws.BoolValue(value.NULL) {
+				attributeType = getAttributeType(value)
+				attribute.Count++
+			}
+			attribute.Types[attributeType]++
+			if value.N != nil && strings.ContainsAny(*value.N, ".eE") {
+				attribute.Integer = false
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attribute := attributes[name]
+		if dominant := dominantType(attribute.Types); dominant != nullAttributeType || attribute.Type == "" {
+			attribute.Type = dominant
+		}
+		attribute.Nullable = attribute.Count == 0 || attribute.Count < len(items)
+		result.Attributes = append(result.Attributes, attribute)
+	}
+	return result
+}
+
+//dominantType returns the most frequent non null type, ties are resolved by type name
+func dominantType(types map[string]int) string {
+	result, count := nullAttributeType, 0
+	for attributeType, typeCount := range types {
+		if attributeType == nullAttributeType {
+			continue
+		}
+		if typeCount > count || (typeCount == count && attributeType < result) {
+			result, count = attributeType, typeCount
+		}
+	}
+	return result
+}
+
+func keySchemaNames(keySchema []*dynamodb.KeySchemaElement) []string {
+	var result = make([]string, 0, len(keySchema))
+	for _, keyType := range []string{dynamodb.KeyTypeHash, dynamodb.KeyTypeRange} {
+		for _, key := range keySchema {
+			if aws.StringValue(key.KeyType) == keyType {
+				result = append(result, aws.StringValue(key.AttributeName))
+			}
+		}
+	}
+	return result
+}
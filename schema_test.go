@@ -0,0 +1,80 @@
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestNewTableSchema(t *testing.T) {
+	description := &dynamodb.TableDescription{
+		TableName: aws.String("music"),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("SongTitle"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+			{AttributeName: aws.String("Artist"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("Artist"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("SongTitle"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("Genre"), AttributeType: aws.String("S")},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndexDescription{
+			{IndexName: aws.String("GenreIndex"), KeySchema: []*dynamodb.KeySchemaElement{{AttributeName: aws.String("Genre"), KeyType: aws.String(dynamodb.KeyTypeHash)}}},
+		},
+	}
+	items := []map[string]*dynamodb.AttributeValue{
+		{
+			"Artist":    {S: aws.String("Artist1")},
+			"SongTitle": {S: aws.String("Title1")},
+			"Year":      {N: aws.String("1984")},
+			"Price":     {N: aws.String("1.99")},
+			"Tags":      {SS: []*string{aws.String("pop")}},
+		},
+		{
+			"Artist":    {S: aws.String("Artist2")},
+			"SongTitle": {S: aws.String("Title2")},
+			"Year":      {N: aws.String("1985")},
+			"Price":     {N: aws.String("2")},
+			"Tags":      {NULL: aws.Bool(true)},
+		},
+		{
+			"Artist":    {S: aws.String("Artist3")},
+			"SongTitle": {S: aws.String("Title3")},
+			"Year":      {S: aws.String("1986")},
+			"Price":     {N: aws.String("3")},
+		},
+	}
+	schema := newTableSchema(description, items)
+	assert.EqualValues(t, []string{"Artist", "SongTitle"}, schema.PkColumns)
+	assert.EqualValues(t, 3, schema.Sampled)
+	if assert.Len(t, schema.Indexes, 1) {
+		assert.EqualValues(t, &IndexSchema{Name: "GenreIndex", Global: true, Keys: []string{"Genre"}}, schema.Indexes[0])
+	}
+	descriptor := schema.TableDescriptor()
+	assert.EqualValues(t, []string{"Artist", "SongTitle", "Genre", "Price", "Tags", "Year"}, descriptor.Columns)
+	assert.EqualValues(t, map[string]string{
+		"Artist":    "S",
+		"SongTitle": "S",
+		"Genre":     "S",
+		"Price":     "N",
+		"Tags":      "SS",
+		"Year":      "N",
+	}, descriptor.ColumnTypes)
+	assert.EqualValues(t, map[string]bool{
+		"Artist":    false,
+		"SongTitle": false,
+		"Genre":     true,
+		"Price":     false,
+		"Tags":      true,
+		"Year":      false,
+	}, descriptor.Nullables)
+
+	year := schema.Attribute("Year")
+	assert.EqualValues(t, map[string]int{"N": 2, "S": 1}, year.Types)
+	assert.Equal(t, reflect.TypeOf(int64(0)), year.ScanType())
+	assert.Equal(t, reflect.TypeOf(float64(0)), schema.Attribute("Price").ScanType())
+	assert.EqualValues(t, dynamodb.KeyTypeHash, schema.Attribute("Artist").KeyType)
+	assert.Len(t, schema.Columns(), 6)
+}
@@ -0,0 +1,23 @@
+//go:build !sdkv2
+
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+//defaultSDK is AWS SDK version used when sdk option is not set
+const defaultSDK = sdkV1
+
+//sdkClientFactories default client factories by SDK version, v2 requires sdkv2 build tag
+var sdkClientFactories = map[string]ClientFactory{sdkV1: newClient}
+
+//sdkStreamsClientFactories streams client factories by SDK version
+var sdkStreamsClientFactories = map[string]streamsClientFactory{sdkV1: newStreamsClient}
+
+//marshalling uses SDK v1 dynamodbattribute
+var (
+	marshal      = dynamodbattribute.Marshal
+	marshalMap   = dynamodbattribute.MarshalMap
+	unmarshalMap = dynamodbattribute.UnmarshalMap
+)
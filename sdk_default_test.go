@@ -0,0 +1,15 @@
+//go:build !sdkv2
+
+package dyndb_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewConnection_SDKv2RequiresTag(t *testing.T) {
+	_, err := newSDKConnection("v2")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "sdkv2 tag")
+	}
+}
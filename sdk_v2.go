@@ -0,0 +1,68 @@
+//go:build sdkv2
+
+package dyndb
+
+import (
+	"context"
+	"github.com/adrianwit/dyndb/sdkv2"
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
+)
+
+//defaultSDK is AWS SDK version used when sdk option is not set
+const defaultSDK = sdkV2
+
+//sdkClientFactories default client factories by SDK version
+var sdkClientFactories = map[string]ClientFactory{sdkV1: newClient, sdkV2: newV2Client}
+
+//sdkStreamsClientFactories streams client factories by SDK version
+var sdkStreamsClientFactories = map[string]streamsClientFactory{sdkV1: newStreamsClient, sdkV2: newV2StreamsClient}
+
+//marshalling uses SDK v2 attributevalue
+var (
+	marshal      = sdkv2.Marshal
+	marshalMap   = sdkv2.MarshalMap
+	unmarshalMap = sdkv2.UnmarshalMap
+)
+
+//newV2Client creates SDK v2 client, session is not used
+func newV2Client(_ *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+	client, err := sdkv2.NewClient(context.Background(), v2Options(awsConfig))
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+//newV2StreamsClient creates SDK v2 DynamoDB Streams client, session is not used
+func newV2StreamsClient(_ *session.Session, awsConfig *aws.Config) (dynamodbstreamsiface.DynamoDBStreamsAPI, error) {
+	client, err := sdkv2.NewStreamsClient(context.Background(), v2Options(awsConfig))
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+//v2Options returns SDK v2 client options for connection region, endpoint, credentials and HTTP client
+func v2Options(awsConfig *aws.Config) *sdkv2.Options {
+	result := &sdkv2.Options{
+		Region:   aws.StringValue(awsConfig.Region),
+		Endpoint: aws.StringValue(awsConfig.Endpoint),
+	}
+	if awsConfig.HTTPClient != nil {
+		result.HTTPClient = awsConfig.HTTPClient
+	}
+	if credentials := awsConfig.Credentials; credentials != nil {
+		result.Credentials = awsv2.NewCredentialsCache(awsv2.CredentialsProviderFunc(func(ctx context.Context) (awsv2.Credentials, error) {
+			value, err := credentials.GetWithContext(ctx)
+			if err != nil {
+				return awsv2.Credentials{}, err
+			}
+			return awsv2.Credentials{AccessKeyID: value.AccessKeyID, SecretAccessKey: value.SecretAccessKey, SessionToken: value.SessionToken, Source: value.ProviderName}, nil
+		}))
+	}
+	return result
+}
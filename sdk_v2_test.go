@@ -0,0 +1,26 @@
+//go:build sdkv2
+
+package dyndb_test
+
+import (
+	"github.com/adrianwit/dyndb"
+	"github.com/adrianwit/dyndb/sdkv2"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewConnection_SDKv2(t *testing.T) {
+	connection, err := newSDKConnection("v2")
+	if !assert.Nil(t, err) {
+		return
+	}
+	db := connection.Unwrap(dyndb.DbAPIPointer).(dynamodbiface.DynamoDBAPI)
+	assert.IsType(t, &sdkv2.Client{}, db)
+	assert.Panics(t, func() {
+		connection.Unwrap(dyndb.DbPointer)
+	})
+	_, err = db.ListTables(&dynamodb.ListTablesInput{})
+	assert.Nil(t, err)
+}
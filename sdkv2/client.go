@@ -0,0 +1,459 @@
+//Package sdkv2 provides DynamoDB client built on AWS SDK for Go v2 implementing SDK v1 dynamodbiface.DynamoDBAPI,
+//so that the driver can switch SDK at build or config time during the transition, it is compiled into the driver with sdkv2 build tag.
+package sdkv2
+
+import (
+	"context"
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"reflect"
+	"time"
+)
+
+//Options represents SDK v2 client options, empty options use config.LoadDefaultConfig defaults
+type Options struct {
+	Region      string
+	Endpoint    string
+	Credentials awsv2.CredentialsProvider
+	HTTPClient  awsv2.HTTPClient
+}
+
+//loadConfig loads SDK v2 config with config.LoadDefaultConfig
+func (o *Options) loadConfig(ctx context.Context) (awsv2.Config, error) {
+	var options = make([]func(*config.LoadOptions) error, 0)
+	if o.Region != "" {
+		options = append(options, config.WithRegion(o.Region))
+	}
+	if o.Credentials != nil {
+		options = append(options, config.WithCredentialsProvider(o.Credentials))
+	}
+	v2Config, err := config.LoadDefaultConfig(ctx, options...)
+	if err == nil && o.HTTPClient != nil {
+		v2Config.HTTPClient = o.HTTPClient
+	}
+	return v2Config, err
+}
+
+//endpoint returns base endpoint or nil for the default one
+func (o *Options) endpoint() *string {
+	if o.Endpoint == "" {
+		return nil
+	}
+	return awsv2.String(o.Endpoint)
+}
+
+//Client adapts SDK v2 client to SDK v1 interface, calls are context first, pages use v2 paginators and table waits v2 waiters,
+//describe requests used by SDK v1 style waiters are sent with v2 client, operations not listed below return UnsupportedOperation error
+type Client struct {
+	client *dynamodbv2.Client
+}
+
+var _ dynamodbiface.DynamoDBAPI = (*Client)(nil)
+
+//New creates v1 interface adapter for SDK v2 client
+func New(client *dynamodbv2.Client) *Client {
+	return &Client{client: client}
+}
+
+//NewClient creates SDK v2 client with config.LoadDefaultConfig
+func NewClient(ctx context.Context, options *Options) (*Client, error) {
+	v2Config, err := options.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := dynamodbv2.NewFromConfig(v2Config, func(v2Options *dynamodbv2.Options) {
+		v2Options.BaseEndpoint = options.endpoint()
+	})
+	return New(client), nil
+}
+
+//call converts v1 input, calls v2 operation with ctx and converts its output
+func call[O, I, V2I, V2O, V2Options any](ctx context.Context, input *I, operation func(context.Context, *V2I, ...V2Options) (*V2O, error)) (*O, error) {
+	v2Input := new(V2I)
+	convert(reflect.ValueOf(input), reflect.ValueOf(v2Input).Elem())
+	v2Output, err := operation(ctx, v2Input)
+	if err != nil {
+		return nil, asAWSError(err)
+	}
+	output := new(O)
+	convert(reflect.ValueOf(v2Output), reflect.ValueOf(output).Elem())
+	return output, nil
+}
+
+func (c *Client) BatchExecuteStatement(input *dynamodb.BatchExecuteStatementInput) (*dynamodb.BatchExecuteStatementOutput, error) {
+	return c.BatchExecuteStatementWithContext(context.Background(), input)
+}
+
+func (c *Client) BatchExecuteStatementWithContext(ctx aws.Context, input *dynamodb.BatchExecuteStatementInput, _ ...request.Option) (*dynamodb.BatchExecuteStatementOutput, error) {
+	return call[dynamodb.BatchExecuteStatementOutput](ctx, input, c.client.BatchExecuteStatement)
+}
+
+func (c *Client) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	return c.BatchGetItemWithContext(context.Background(), input)
+}
+
+func (c *Client) BatchGetItemWithContext(ctx aws.Context, input *dynamodb.BatchGetItemInput, _ ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	return call[dynamodb.BatchGetItemOutput](ctx, input, c.client.BatchGetItem)
+}
+
+func (c *Client) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	return c.BatchWriteItemWithContext(context.Background(), input)
+}
+
+func (c *Client) BatchWriteItemWithContext(ctx aws.Context, input *dynamodb.BatchWriteItemInput, _ ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	return call[dynamodb.BatchWriteItemOutput](ctx, input, c.client.BatchWriteItem)
+}
+
+func (c *Client) CreateBackup(input *dynamodb.CreateBackupInput) (*dynamodb.CreateBackupOutput, error) {
+	return c.CreateBackupWithContext(context.Background(), input)
+}
+
+func (c *Client) CreateBackupWithContext(ctx aws.Context, input *dynamodb.CreateBackupInput, _ ...request.Option) (*dynamodb.CreateBackupOutput, error) {
+	return call[dynamodb.CreateBackupOutput](ctx, input, c.client.CreateBackup)
+}
+
+func (c *Client) CreateTable(input *dynamodb.CreateTableInput) (*dynamodb.CreateTableOutput, error) {
+	return c.CreateTableWithContext(context.Background(), input)
+}
+
+func (c *Client) CreateTableWithContext(ctx aws.Context, input *dynamodb.CreateTableInput, _ ...request.Option) (*dynamodb.CreateTableOutput, error) {
+	return call[dynamodb.CreateTableOutput](ctx, input, c.client.CreateTable)
+}
+
+func (c *Client) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	return c.DeleteItemWithContext(context.Background(), input)
+}
+
+func (c *Client) DeleteItemWithContext(ctx aws.Context, input *dynamodb.DeleteItemInput, _ ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	return call[dynamodb.DeleteItemOutput](ctx, input, c.client.DeleteItem)
+}
+
+func (c *Client) DeleteTable(input *dynamodb.DeleteTableInput) (*dynamodb.DeleteTableOutput, error) {
+	return c.DeleteTableWithContext(context.Background(), input)
+}
+
+func (c *Client) DeleteTableWithContext(ctx aws.Context, input *dynamodb.DeleteTableInput, _ ...request.Option) (*dynamodb.DeleteTableOutput, error) {
+	return call[dynamodb.DeleteTableOutput](ctx, input, c.client.DeleteTable)
+}
+
+func (c *Client) DescribeBackup(input *dynamodb.DescribeBackupInput) (*dynamodb.DescribeBackupOutput, error) {
+	return c.DescribeBackupWithContext(context.Background(), input)
+}
+
+func (c *Client) DescribeBackupWithContext(ctx aws.Context, input *dynamodb.DescribeBackupInput, _ ...request.Option) (*dynamodb.DescribeBackupOutput, error) {
+	return call[dynamodb.DescribeBackupOutput](ctx, input, c.client.DescribeBackup)
+}
+
+func (c *Client) DescribeContinuousBackups(input *dynamodb.DescribeContinuousBackupsInput) (*dynamodb.DescribeContinuousBackupsOutput, error) {
+	return c.DescribeContinuousBackupsWithContext(context.Background(), input)
+}
+
+func (c *Client) DescribeContinuousBackupsWithContext(ctx aws.Context, input *dynamodb.DescribeContinuousBackupsInput, _ ...request.Option) (*dynamodb.DescribeContinuousBackupsOutput, error) {
+	return call[dynamodb.DescribeContinuousBackupsOutput](ctx, input, c.client.DescribeContinuousBackups)
+}
+
+func (c *Client) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	return c.DescribeTableWithContext(context.Background(), input)
+}
+
+func (c *Client) DescribeTableWithContext(ctx aws.Context, input *dynamodb.DescribeTableInput, _ ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	return call[dynamodb.DescribeTableOutput](ctx, input, c.client.DescribeTable)
+}
+
+func (c *Client) DescribeTimeToLive(input *dynamodb.DescribeTimeToLiveInput) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	return c.DescribeTimeToLiveWithContext(context.Background(), input)
+}
+
+func (c *Client) DescribeTimeToLiveWithContext(ctx aws.Context, input *dynamodb.DescribeTimeToLiveInput, _ ...request.Option) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	return call[dynamodb.DescribeTimeToLiveOutput](ctx, input, c.client.DescribeTimeToLive)
+}
+
+func (c *Client) ExecuteStatement(input *dynamodb.ExecuteStatementInput) (*dynamodb.ExecuteStatementOutput, error) {
+	return c.ExecuteStatementWithContext(context.Background(), input)
+}
+
+func (c *Client) ExecuteStatementWithContext(ctx aws.Context, input *dynamodb.ExecuteStatementInput, _ ...request.Option) (*dynamodb.ExecuteStatementOutput, error) {
+	return call[dynamodb.ExecuteStatementOutput](ctx, input, c.client.ExecuteStatement)
+}
+
+func (c *Client) ExecuteTransaction(input *dynamodb.ExecuteTransactionInput) (*dynamodb.ExecuteTransactionOutput, error) {
+	return c.ExecuteTransactionWithContext(context.Background(), input)
+}
+
+func (c *Client) ExecuteTransactionWithContext(ctx aws.Context, input *dynamodb.ExecuteTransactionInput, _ ...request.Option) (*dynamodb.ExecuteTransactionOutput, error) {
+	return call[dynamodb.ExecuteTransactionOutput](ctx, input, c.client.ExecuteTransaction)
+}
+
+func (c *Client) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	return c.GetItemWithContext(context.Background(), input)
+}
+
+func (c *Client) GetItemWithContext(ctx aws.Context, input *dynamodb.GetItemInput, _ ...request.Option) (*dynamodb.GetItemOutput, error) {
+	return call[dynamodb.GetItemOutput](ctx, input, c.client.GetItem)
+}
+
+func (c *Client) ListBackups(input *dynamodb.ListBackupsInput) (*dynamodb.ListBackupsOutput, error) {
+	return c.ListBackupsWithContext(context.Background(), input)
+}
+
+func (c *Client) ListBackupsWithContext(ctx aws.Context, input *dynamodb.ListBackupsInput, _ ...request.Option) (*dynamodb.ListBackupsOutput, error) {
+	return call[dynamodb.ListBackupsOutput](ctx, input, c.client.ListBackups)
+}
+
+func (c *Client) ListTables(input *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+	return c.ListTablesWithContext(context.Background(), input)
+}
+
+func (c *Client) ListTablesWithContext(ctx aws.Context, input *dynamodb.ListTablesInput, _ ...request.Option) (*dynamodb.ListTablesOutput, error) {
+	return call[dynamodb.ListTablesOutput](ctx, input, c.client.ListTables)
+}
+
+func (c *Client) ListTagsOfResource(input *dynamodb.ListTagsOfResourceInput) (*dynamodb.ListTagsOfResourceOutput, error) {
+	return c.ListTagsOfResourceWithContext(context.Background(), input)
+}
+
+func (c *Client) ListTagsOfResourceWithContext(ctx aws.Context, input *dynamodb.ListTagsOfResourceInput, _ ...request.Option) (*dynamodb.ListTagsOfResourceOutput, error) {
+	return call[dynamodb.ListTagsOfResourceOutput](ctx, input, c.client.ListTagsOfResource)
+}
+
+func (c *Client) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	return c.PutItemWithContext(context.Background(), input)
+}
+
+func (c *Client) PutItemWithContext(ctx aws.Context, input *dynamodb.PutItemInput, _ ...request.Option) (*dynamodb.PutItemOutput, error) {
+	return call[dynamodb.PutItemOutput](ctx, input, c.client.PutItem)
+}
+
+func (c *Client) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	return c.QueryWithContext(context.Background(), input)
+}
+
+func (c *Client) QueryWithContext(ctx aws.Context, input *dynamodb.QueryInput, _ ...request.Option) (*dynamodb.QueryOutput, error) {
+	return call[dynamodb.QueryOutput](ctx, input, c.client.Query)
+}
+
+func (c *Client) RestoreTableFromBackup(input *dynamodb.RestoreTableFromBackupInput) (*dynamodb.RestoreTableFromBackupOutput, error) {
+	return c.RestoreTableFromBackupWithContext(context.Background(), input)
+}
+
+func (c *Client) RestoreTableFromBackupWithContext(ctx aws.Context, input *dynamodb.RestoreTableFromBackupInput, _ ...request.Option) (*dynamodb.RestoreTableFromBackupOutput, error) {
+	return call[dynamodb.RestoreTableFromBackupOutput](ctx, input, c.client.RestoreTableFromBackup)
+}
+
+func (c *Client) RestoreTableToPointInTime(input *dynamodb.RestoreTableToPointInTimeInput) (*dynamodb.RestoreTableToPointInTimeOutput, error) {
+	return c.RestoreTableToPointInTimeWithContext(context.Background(), input)
+}
+
+func (c *Client) RestoreTableToPointInTimeWithContext(ctx aws.Context, input *dynamodb.RestoreTableToPointInTimeInput, _ ...request.Option) (*dynamodb.RestoreTableToPointInTimeOutput, error) {
+	return call[dynamodb.RestoreTableToPointInTimeOutput](ctx, input, c.client.RestoreTableToPointInTime)
+}
+
+func (c *Client) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	return c.ScanWithContext(context.Background(), input)
+}
+
+func (c *Client) ScanWithContext(ctx aws.Context, input *dynamodb.ScanInput, _ ...request.Option) (*dynamodb.ScanOutput, error) {
+	return call[dynamodb.ScanOutput](ctx, input, c.client.Scan)
+}
+
+func (c *Client) TagResource(input *dynamodb.TagResourceInput) (*dynamodb.TagResourceOutput, error) {
+	return c.TagResourceWithContext(context.Background(), input)
+}
+
+func (c *Client) TagResourceWithContext(ctx aws.Context, input *dynamodb.TagResourceInput, _ ...request.Option) (*dynamodb.TagResourceOutput, error) {
+	return call[dynamodb.TagResourceOutput](ctx, input, c.client.TagResource)
+}
+
+func (c *Client) TransactGetItems(input *dynamodb.TransactGetItemsInput) (*dynamodb.TransactGetItemsOutput, error) {
+	return c.TransactGetItemsWithContext(context.Background(), input)
+}
+
+func (c *Client) TransactGetItemsWithContext(ctx aws.Context, input *dynamodb.TransactGetItemsInput, _ ...request.Option) (*dynamodb.TransactGetItemsOutput, error) {
+	return call[dynamodb.TransactGetItemsOutput](ctx, input, c.client.TransactGetItems)
+}
+
+func (c *Client) TransactWriteItems(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	return c.TransactWriteItemsWithContext(context.Background(), input)
+}
+
+func (c *Client) TransactWriteItemsWithContext(ctx aws.Context, input *dynamodb.TransactWriteItemsInput, _ ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	return call[dynamodb.TransactWriteItemsOutput](ctx, input, c.client.TransactWriteItems)
+}
+
+func (c *Client) UntagResource(input *dynamodb.UntagResourceInput) (*dynamodb.UntagResourceOutput, error) {
+	return c.UntagResourceWithContext(context.Background(), input)
+}
+
+func (c *Client) UntagResourceWithContext(ctx aws.Context, input *dynamodb.UntagResourceInput, _ ...request.Option) (*dynamodb.UntagResourceOutput, error) {
+	return call[dynamodb.UntagResourceOutput](ctx, input, c.client.UntagResource)
+}
+
+func (c *Client) UpdateContinuousBackups(input *dynamodb.UpdateContinuousBackupsInput) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	return c.UpdateContinuousBackupsWithContext(context.Background(), input)
+}
+
+func (c *Client) UpdateContinuousBackupsWithContext(ctx aws.Context, input *dynamodb.UpdateContinuousBackupsInput, _ ...request.Option) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	return call[dynamodb.UpdateContinuousBackupsOutput](ctx, input, c.client.UpdateContinuousBackups)
+}
+
+func (c *Client) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	return c.UpdateItemWithContext(context.Background(), input)
+}
+
+func (c *Client) UpdateItemWithContext(ctx aws.Context, input *dynamodb.UpdateItemInput, _ ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	return call[dynamodb.UpdateItemOutput](ctx, input, c.client.UpdateItem)
+}
+
+func (c *Client) UpdateTable(input *dynamodb.UpdateTableInput) (*dynamodb.UpdateTableOutput, error) {
+	return c.UpdateTableWithContext(context.Background(), input)
+}
+
+func (c *Client) UpdateTableWithContext(ctx aws.Context, input *dynamodb.UpdateTableInput, _ ...request.Option) (*dynamodb.UpdateTableOutput, error) {
+	return call[dynamodb.UpdateTableOutput](ctx, input, c.client.UpdateTable)
+}
+
+func (c *Client) UpdateTimeToLive(input *dynamodb.UpdateTimeToLiveInput) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	return c.UpdateTimeToLiveWithContext(context.Background(), input)
+}
+
+func (c *Client) UpdateTimeToLiveWithContext(ctx aws.Context, input *dynamodb.UpdateTimeToLiveInput, _ ...request.Option) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	return call[dynamodb.UpdateTimeToLiveOutput](ctx, input, c.client.UpdateTimeToLive)
+}
+
+func (c *Client) QueryPages(input *dynamodb.QueryInput, handler func(*dynamodb.QueryOutput, bool) bool) error {
+	return c.QueryPagesWithContext(context.Background(), input, handler)
+}
+
+func (c *Client) QueryPagesWithContext(ctx aws.Context, input *dynamodb.QueryInput, handler func(*dynamodb.QueryOutput, bool) bool, _ ...request.Option) error {
+	v2Input := &dynamodbv2.QueryInput{}
+	convert(reflect.ValueOf(input), reflect.ValueOf(v2Input).Elem())
+	paginator := dynamodbv2.NewQueryPaginator(c.client, v2Input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return asAWSError(err)
+		}
+		output := &dynamodb.QueryOutput{}
+		convert(reflect.ValueOf(page), reflect.ValueOf(output).Elem())
+		if !handler(output, !paginator.HasMorePages()) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (c *Client) ScanPages(input *dynamodb.ScanInput, handler func(*dynamodb.ScanOutput, bool) bool) error {
+	return c.ScanPagesWithContext(context.Background(), input, handler)
+}
+
+func (c *Client) ScanPagesWithContext(ctx aws.Context, input *dynamodb.ScanInput, handler func(*dynamodb.ScanOutput, bool) bool, _ ...request.Option) error {
+	v2Input := &dynamodbv2.ScanInput{}
+	convert(reflect.ValueOf(input), reflect.ValueOf(v2Input).Elem())
+	paginator := dynamodbv2.NewScanPaginator(c.client, v2Input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return asAWSError(err)
+		}
+		output := &dynamodb.ScanOutput{}
+		convert(reflect.ValueOf(page), reflect.ValueOf(output).Elem())
+		if !handler(output, !paginator.HasMorePages()) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (c *Client) ListTablesPages(input *dynamodb.ListTablesInput, handler func(*dynamodb.ListTablesOutput, bool) bool) error {
+	return c.ListTablesPagesWithContext(context.Background(), input, handler)
+}
+
+func (c *Client) ListTablesPagesWithContext(ctx aws.Context, input *dynamodb.ListTablesInput, handler func(*dynamodb.ListTablesOutput, bool) bool, _ ...request.Option) error {
+	v2Input := &dynamodbv2.ListTablesInput{}
+	convert(reflect.ValueOf(input), reflect.ValueOf(v2Input).Elem())
+	paginator := dynamodbv2.NewListTablesPaginator(c.client, v2Input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return asAWSError(err)
+		}
+		output := &dynamodb.ListTablesOutput{}
+		convert(reflect.ValueOf(page), reflect.ValueOf(output).Elem())
+		if !handler(output, !paginator.HasMorePages()) {
+			return nil
+		}
+	}
+	return nil
+}
+
+//newRequest returns SDK v1 request sending operation with v2 client, it lets SDK v1 request.Waiter poll v2 client
+func newRequest[O, I any](name string, input *I, operation func(aws.Context, *I, ...request.Option) (*O, error)) (*request.Request, *O) {
+	output := new(O)
+	handlers := request.Handlers{}
+	handlers.Send.PushBack(func(req *request.Request) {
+		result, err := operation(req.Context(), input)
+		if err != nil {
+			req.Error = err
+			return
+		}
+		*output = *result
+	})
+	operationInfo := &request.Operation{Name: name, HTTPMethod: "POST", HTTPPath: "/"}
+	return request.New(aws.Config{}, metadata.ClientInfo{ServiceName: dynamodb.ServiceName}, handlers, nil, operationInfo, input, output), output
+}
+
+func (c *Client) DescribeBackupRequest(input *dynamodb.DescribeBackupInput) (*request.Request, *dynamodb.DescribeBackupOutput) {
+	return newRequest("DescribeBackup", input, c.DescribeBackupWithContext)
+}
+
+func (c *Client) DescribeContinuousBackupsRequest(input *dynamodb.DescribeContinuousBackupsInput) (*request.Request, *dynamodb.DescribeContinuousBackupsOutput) {
+	return newRequest("DescribeContinuousBackups", input, c.DescribeContinuousBackupsWithContext)
+}
+
+func (c *Client) DescribeTableRequest(input *dynamodb.DescribeTableInput) (*request.Request, *dynamodb.DescribeTableOutput) {
+	return newRequest("DescribeTable", input, c.DescribeTableWithContext)
+}
+
+func (c *Client) WaitUntilTableExists(input *dynamodb.DescribeTableInput) error {
+	return c.WaitUntilTableExistsWithContext(context.Background(), input)
+}
+
+//WaitUntilTableExistsWithContext waits with v2 waiter, SDK v1 waiter delay and max attempts set its delay and max wait time
+func (c *Client) WaitUntilTableExistsWithContext(ctx aws.Context, input *dynamodb.DescribeTableInput, options ...request.WaiterOption) error {
+	v2Input := &dynamodbv2.DescribeTableInput{}
+	convert(reflect.ValueOf(input), reflect.ValueOf(v2Input).Elem())
+	delay, maxWaitTime := waiterDuration(options)
+	waiter := dynamodbv2.NewTableExistsWaiter(c.client, func(options *dynamodbv2.TableExistsWaiterOptions) {
+		options.MinDelay, options.MaxDelay = delay, delay
+	})
+	return asAWSError(waiter.Wait(ctx, v2Input, maxWaitTime))
+}
+
+func (c *Client) WaitUntilTableNotExists(input *dynamodb.DescribeTableInput) error {
+	return c.WaitUntilTableNotExistsWithContext(context.Background(), input)
+}
+
+//WaitUntilTableNotExistsWithContext waits with v2 waiter, SDK v1 waiter delay and max attempts set its delay and max wait time
+func (c *Client) WaitUntilTableNotExistsWithContext(ctx aws.Context, input *dynamodb.DescribeTableInput, options ...request.WaiterOption) error {
+	v2Input := &dynamodbv2.DescribeTableInput{}
+	convert(reflect.ValueOf(input), reflect.ValueOf(v2Input).Elem())
+	delay, maxWaitTime := waiterDuration(options)
+	waiter := dynamodbv2.NewTableNotExistsWaiter(c.client, func(options *dynamodbv2.TableNotExistsWaiterOptions) {
+		options.MinDelay, options.MaxDelay = delay, delay
+	})
+	return asAWSError(waiter.Wait(ctx, v2Input, maxWaitTime))
+}
+
+//waiterDuration returns delay and max wait time for SDK v1 waiter options, defaults are SDK v1 table waiter 20s delay and 25 attempts
+func waiterDuration(options []request.WaiterOption) (time.Duration, time.Duration) {
+	waiter := request.Waiter{MaxAttempts: 25, Delay: request.ConstantWaiterDelay(20 * time.Second)}
+	waiter.ApplyOptions(options...)
+	delay := waiter.Delay(1)
+	return delay, time.Duration(waiter.MaxAttempts) * delay
+}
@@ -0,0 +1,169 @@
+package sdkv2_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/adrianwit/dyndb/memdb"
+	"github.com/adrianwit/dyndb/sdkv2"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newClient(t *testing.T) dynamodbiface.DynamoDBAPI {
+	server := httptest.NewServer(memdb.New())
+	t.Cleanup(server.Close)
+	client, err := sdkv2.NewClient(context.Background(), &sdkv2.Options{
+		Region:      "us-west-1",
+		Endpoint:    server.URL,
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func errorCode(err error) string {
+	if awsError, ok := err.(awserr.Error); ok {
+		return awsError.Code()
+	}
+	return ""
+}
+
+func TestClient(t *testing.T) {
+	db := newClient(t)
+	_, err := db.CreateTable(&dynamodb.CreateTableInput{
+		TableName: aws.String("music"),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("Artist"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("SongTitle"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("Artist"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("SongTitle"), KeyType: aws.String("RANGE")},
+		},
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	if !assert.Nil(t, db.WaitUntilTableExists(&dynamodb.DescribeTableInput{TableName: aws.String("music")})) {
+		return
+	}
+	var tables []string
+	err = db.ListTablesPages(&dynamodb.ListTablesInput{}, func(output *dynamodb.ListTablesOutput, lastPage bool) bool {
+		tables = append(tables, aws.StringValueSlice(output.TableNames)...)
+		return true
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"music"}, tables)
+
+	for i := 0; i < 5; i++ {
+		_, err = db.PutItem(&dynamodb.PutItemInput{TableName: aws.String("music"), Item: map[string]*dynamodb.AttributeValue{
+			"Artist":    {S: aws.String("Artist0")},
+			"SongTitle": {S: aws.String(fmt.Sprintf("Title%d", i))},
+			"Price":     {N: aws.String(fmt.Sprintf("%d.5", i))},
+			"Tags":      {SS: aws.StringSlice([]string{"rock"})},
+		}})
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	key := map[string]*dynamodb.AttributeValue{"Artist": {S: aws.String("Artist0")}, "SongTitle": {S: aws.String("Title1")}}
+	got, err := db.GetItem(&dynamodb.GetItemInput{TableName: aws.String("music"), Key: key})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "1.5", *got.Item["Price"].N)
+		assert.EqualValues(t, []string{"rock"}, aws.StringValueSlice(got.Item["Tags"].SS))
+	}
+
+	_, err = db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String("music"),
+		Item:                key,
+		ConditionExpression: aws.String("attribute_not_exists(Artist)"),
+	})
+	assert.EqualValues(t, dynamodb.ErrCodeConditionalCheckFailedException, errorCode(err))
+
+	var titles []string
+	var pages int
+	err = db.QueryPages(&dynamodb.QueryInput{
+		TableName:                 aws.String("music"),
+		KeyConditionExpression:    aws.String("Artist = :a"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":a": {S: aws.String("Artist0")}},
+		Limit:                     aws.Int64(2),
+	}, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		pages++
+		for _, item := range output.Items {
+			titles = append(titles, *item["SongTitle"].S)
+		}
+		return true
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, pages)
+	assert.EqualValues(t, []string{"Title0", "Title1", "Title2", "Title3", "Title4"}, titles)
+
+	updated, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String("music"),
+		Key:                       key,
+		UpdateExpression:          aws.String("SET Price = Price + :p"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":p": {N: aws.String("0.25")}},
+		ReturnValues:              aws.String(dynamodb.ReturnValueUpdatedNew),
+	})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, "1.75", *updated.Attributes["Price"].N)
+	}
+
+	_, err = db.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{Delete: &dynamodb.Delete{TableName: aws.String("music"), Key: key}},
+			{ConditionCheck: &dynamodb.ConditionCheck{
+				TableName:           aws.String("music"),
+				Key:                 key,
+				ConditionExpression: aws.String("attribute_not_exists(Price)"),
+			}},
+		},
+	})
+	if canceled, ok := err.(*dynamodb.TransactionCanceledException); assert.True(t, ok, fmt.Sprintf("%v", err)) {
+		if assert.Len(t, canceled.CancellationReasons, 2) {
+			assert.EqualValues(t, "ConditionalCheckFailed", *canceled.CancellationReasons[1].Code)
+		}
+	}
+
+	_, err = db.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String("music")})
+	assert.Nil(t, err)
+	assert.Nil(t, db.WaitUntilTableNotExists(&dynamodb.DescribeTableInput{TableName: aws.String("music")}))
+	_, err = db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("music")})
+	assert.EqualValues(t, dynamodb.ErrCodeResourceNotFoundException, errorCode(err))
+}
+
+func TestClient_Unsupported(t *testing.T) {
+	db := newClient(t)
+	_, err := db.DescribeLimits(&dynamodb.DescribeLimitsInput{})
+	assert.EqualValues(t, sdkv2.ErrCodeUnsupportedOperation, errorCode(err))
+	err = db.BatchGetItemPages(&dynamodb.BatchGetItemInput{}, func(*dynamodb.BatchGetItemOutput, bool) bool { return true })
+	assert.EqualValues(t, sdkv2.ErrCodeUnsupportedOperation, errorCode(err))
+	request, _ := db.GetItemRequest(&dynamodb.GetItemInput{})
+	assert.EqualValues(t, sdkv2.ErrCodeUnsupportedOperation, errorCode(request.Send()))
+}
+
+func TestClient_Waiters(t *testing.T) {
+	db := newClient(t)
+	options := []request.WaiterOption{request.WithWaiterDelay(request.ConstantWaiterDelay(10 * time.Millisecond)), request.WithWaiterMaxAttempts(3)}
+	input := &dynamodb.DescribeTableInput{TableName: aws.String("missing")}
+	started := time.Now()
+	err := db.WaitUntilTableExistsWithContext(context.Background(), input, options...)
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(started) < 5*time.Second)
+	assert.Nil(t, db.WaitUntilTableNotExistsWithContext(context.Background(), input, options...))
+
+	describe, output := db.DescribeTableRequest(input)
+	assert.EqualValues(t, dynamodb.ErrCodeResourceNotFoundException, errorCode(describe.Send()))
+	assert.Nil(t, output.Table)
+}
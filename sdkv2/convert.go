@@ -0,0 +1,206 @@
+package sdkv2
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamstypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/smithy-go"
+	"reflect"
+)
+
+var (
+	attributeValueV1 = reflect.TypeOf((*dynamodb.AttributeValue)(nil))
+	attributeValueV2 = reflect.TypeOf((*types.AttributeValue)(nil)).Elem()
+	//streamAttributeValueV2 DynamoDB Streams v2 attribute value, it is converted to v1 only
+	streamAttributeValueV2 = reflect.TypeOf((*streamstypes.AttributeValue)(nil)).Elem()
+)
+
+//convert copies source into target matching struct fields by name, SDK v1 pointers map to v2 values, enums and numbers are converted
+func convert(source, target reflect.Value) {
+	if !source.IsValid() {
+		return
+	}
+	switch {
+	case target.Type() == attributeValueV2:
+		if value, ok := source.Interface().(*dynamodb.AttributeValue); ok && value != nil {
+			target.Set(reflect.ValueOf(toV2(value)))
+		}
+		return
+	case source.Type() == attributeValueV2:
+		if value, ok := source.Interface().(types.AttributeValue); ok && value != nil && target.Type() == attributeValueV1 {
+			target.Set(reflect.ValueOf(toV1(value)))
+		}
+		return
+	case source.Type() == streamAttributeValueV2:
+		if value, ok := source.Interface().(streamstypes.AttributeValue); ok && value != nil && target.Type() == attributeValueV1 {
+			if converted, err := attributevalue.FromDynamoDBStreams(value); err == nil {
+				target.Set(reflect.ValueOf(toV1(converted)))
+			}
+		}
+		return
+	case source.Type().AssignableTo(target.Type()):
+		target.Set(source)
+		return
+	case source.Kind() == reflect.Ptr:
+		if !source.IsNil() {
+			convert(source.Elem(), target)
+		}
+		return
+	case target.Kind() == reflect.Ptr:
+		if source.Kind() == reflect.String && source.Len() == 0 {
+			return //unset v2 enum
+		}
+		value := reflect.New(target.Type().Elem())
+		convert(source, value.Elem())
+		target.Set(value)
+		return
+	}
+	switch target.Kind() {
+	case reflect.Struct:
+		if source.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if sourceField := source.FieldByName(field.Name); sourceField.IsValid() {
+				convert(sourceField, target.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if source.Kind() != reflect.Slice || source.IsNil() {
+			return
+		}
+		result := reflect.MakeSlice(target.Type(), source.Len(), source.Len())
+		for i := 0; i < source.Len(); i++ {
+			convert(source.Index(i), result.Index(i))
+		}
+		target.Set(result)
+	case reflect.Map:
+		if source.Kind() != reflect.Map || source.IsNil() {
+			return
+		}
+		result := reflect.MakeMapWithSize(target.Type(), source.Len())
+		for _, key := range source.MapKeys() {
+			targetKey := reflect.New(target.Type().Key()).Elem()
+			convert(key, targetKey)
+			value := reflect.New(target.Type().Elem()).Elem()
+			convert(source.MapIndex(key), value)
+			result.SetMapIndex(targetKey, value)
+		}
+		target.Set(result)
+	default:
+		if sameKind(source.Kind(), target.Kind()) {
+			target.Set(source.Convert(target.Type()))
+		}
+	}
+}
+
+//sameKind returns true for string, bool or numeric kinds that convert without changing meaning
+func sameKind(source, target reflect.Kind) bool {
+	category := func(kind reflect.Kind) int {
+		switch kind {
+		case reflect.String:
+			return 1
+		case reflect.Bool:
+			return 2
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return 3
+		}
+		return 0
+	}
+	return category(source) != 0 && category(source) == category(target)
+}
+
+//toV2 converts SDK v1 attribute value
+func toV2(value *dynamodb.AttributeValue) types.AttributeValue {
+	switch {
+	case value.S != nil:
+		return &types.AttributeValueMemberS{Value: *value.S}
+	case value.N != nil:
+		return &types.AttributeValueMemberN{Value: *value.N}
+	case value.B != nil:
+		return &types.AttributeValueMemberB{Value: value.B}
+	case value.SS != nil:
+		return &types.AttributeValueMemberSS{Value: aws.StringValueSlice(value.SS)}
+	case value.NS != nil:
+		return &types.AttributeValueMemberNS{Value: aws.StringValueSlice(value.NS)}
+	case value.BS != nil:
+		return &types.AttributeValueMemberBS{Value: value.BS}
+	case value.M != nil:
+		var result = make(map[string]types.AttributeValue, len(value.M))
+		for key, element := range value.M {
+			if element != nil {
+				result[key] = toV2(element)
+			}
+		}
+		return &types.AttributeValueMemberM{Value: result}
+	case value.L != nil:
+		var result = make([]types.AttributeValue, 0, len(value.L))
+		for _, element := range value.L {
+			if element != nil {
+				result = append(result, toV2(element))
+			}
+		}
+		return &types.AttributeValueMemberL{Value: result}
+	case value.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *value.BOOL}
+	}
+	return &types.AttributeValueMemberNULL{Value: true}
+}
+
+//toV1 converts SDK v2 attribute value
+func toV1(value types.AttributeValue) *dynamodb.AttributeValue {
+	switch actual := value.(type) {
+	case *types.AttributeValueMemberS:
+		return &dynamodb.AttributeValue{S: aws.String(actual.Value)}
+	case *types.AttributeValueMemberN:
+		return &dynamodb.AttributeValue{N: aws.String(actual.Value)}
+	case *types.AttributeValueMemberB:
+		return &dynamodb.AttributeValue{B: actual.Value}
+	case *types.AttributeValueMemberSS:
+		return &dynamodb.AttributeValue{SS: aws.StringSlice(actual.Value)}
+	case *types.AttributeValueMemberNS:
+		return &dynamodb.AttributeValue{NS: aws.StringSlice(actual.Value)}
+	case *types.AttributeValueMemberBS:
+		return &dynamodb.AttributeValue{BS: actual.Value}
+	case *types.AttributeValueMemberM:
+		var result = make(map[string]*dynamodb.AttributeValue, len(actual.Value))
+		for key, element := range actual.Value {
+			result[key] = toV1(element)
+		}
+		return &dynamodb.AttributeValue{M: result}
+	case *types.AttributeValueMemberL:
+		var result = make([]*dynamodb.AttributeValue, 0, len(actual.Value))
+		for _, element := range actual.Value {
+			result = append(result, toV1(element))
+		}
+		return &dynamodb.AttributeValue{L: result}
+	case *types.AttributeValueMemberBOOL:
+		return &dynamodb.AttributeValue{BOOL: aws.Bool(actual.Value)}
+	}
+	return &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+}
+
+//asAWSError converts SDK v2 API error to awserr.Error so that error codes are checked the same way for both SDKs
+func asAWSError(err error) error {
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		result := &dynamodb.TransactionCanceledException{Message_: canceled.Message}
+		convert(reflect.ValueOf(canceled.CancellationReasons), reflect.ValueOf(&result.CancellationReasons).Elem())
+		return result
+	}
+	var apiError smithy.APIError
+	if errors.As(err, &apiError) {
+		return awserr.New(apiError.ErrorCode(), apiError.ErrorMessage(), err)
+	}
+	return err
+}
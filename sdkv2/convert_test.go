@@ -0,0 +1,99 @@
+package sdkv2
+
+import (
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	dynamodbstreamsv2 "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamstypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String("music"),
+		IndexName:              aws.String("genre"),
+		KeyConditionExpression: aws.String("Artist = :a"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":a": {S: aws.String("Artist0")},
+			":m": {M: map[string]*dynamodb.AttributeValue{
+				"Tags":  {SS: aws.StringSlice([]string{"rock", "pop"})},
+				"Plays": {L: []*dynamodb.AttributeValue{{N: aws.String("1")}, {BOOL: aws.Bool(true)}, {NULL: aws.Bool(true)}}},
+			}},
+			":b": {B: []byte("abc")},
+		},
+		ExpressionAttributeNames: map[string]*string{"#a": aws.String("Artist")},
+		Limit:                    aws.Int64(10),
+		ScanIndexForward:         aws.Bool(false),
+		Select:                   aws.String(dynamodb.SelectAllProjectedAttributes),
+	}
+	v2Input := &dynamodbv2.QueryInput{}
+	convert(reflect.ValueOf(input), reflect.ValueOf(v2Input).Elem())
+	assert.EqualValues(t, "music", *v2Input.TableName)
+	assert.EqualValues(t, 10, *v2Input.Limit)
+	assert.EqualValues(t, false, *v2Input.ScanIndexForward)
+	assert.EqualValues(t, types.SelectAllProjectedAttributes, v2Input.Select)
+	assert.EqualValues(t, "Artist", v2Input.ExpressionAttributeNames["#a"])
+	assert.EqualValues(t, &types.AttributeValueMemberS{Value: "Artist0"}, v2Input.ExpressionAttributeValues[":a"])
+
+	actual := &dynamodb.QueryInput{}
+	convert(reflect.ValueOf(v2Input), reflect.ValueOf(actual).Elem())
+	assert.EqualValues(t, input, actual)
+}
+
+func TestConvert_StreamRecord(t *testing.T) {
+	record := &dynamodbstreamsv2.GetRecordsOutput{
+		NextShardIterator: awsv2.String("next"),
+		Records: []streamstypes.Record{
+			{
+				EventName: streamstypes.OperationTypeModify,
+				Dynamodb: &streamstypes.StreamRecord{
+					SequenceNumber: awsv2.String("1"),
+					Keys:           map[string]streamstypes.AttributeValue{"Id": &streamstypes.AttributeValueMemberN{Value: "1"}},
+					NewImage: map[string]streamstypes.AttributeValue{
+						"Id":   &streamstypes.AttributeValueMemberN{Value: "1"},
+						"Tags": &streamstypes.AttributeValueMemberL{Value: []streamstypes.AttributeValue{&streamstypes.AttributeValueMemberS{Value: "rock"}}},
+					},
+				},
+			},
+		},
+	}
+	actual := &dynamodbstreams.GetRecordsOutput{}
+	convert(reflect.ValueOf(record), reflect.ValueOf(actual).Elem())
+	assert.EqualValues(t, &dynamodbstreams.GetRecordsOutput{
+		NextShardIterator: aws.String("next"),
+		Records: []*dynamodbstreams.Record{
+			{
+				EventName: aws.String(dynamodbstreams.OperationTypeModify),
+				Dynamodb: &dynamodbstreams.StreamRecord{
+					SequenceNumber: aws.String("1"),
+					Keys:           map[string]*dynamodb.AttributeValue{"Id": {N: aws.String("1")}},
+					NewImage: map[string]*dynamodb.AttributeValue{
+						"Id":   {N: aws.String("1")},
+						"Tags": {L: []*dynamodb.AttributeValue{{S: aws.String("rock")}}},
+					},
+				},
+			},
+		},
+	}, actual)
+}
+
+func TestMarshal(t *testing.T) {
+	item, err := MarshalMap(map[string]interface{}{"Id": 1, "Name": "user1", "Tags": []string{"a"}})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, map[string]*dynamodb.AttributeValue{
+		"Id":   {N: aws.String("1")},
+		"Name": {S: aws.String("user1")},
+		"Tags": {L: []*dynamodb.AttributeValue{{S: aws.String("a")}}},
+	}, item)
+	var actual = make(map[string]interface{})
+	assert.Nil(t, UnmarshalMap(item, &actual))
+	assert.EqualValues(t, map[string]interface{}{"Id": 1.0, "Name": "user1", "Tags": []interface{}{"a"}}, actual)
+}
@@ -0,0 +1,40 @@
+package sdkv2
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+//Marshal marshals value with v2 attributevalue.Marshal, it returns SDK v1 attribute value
+func Marshal(in interface{}) (*dynamodb.AttributeValue, error) {
+	value, err := attributevalue.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	return toV1(value), nil
+}
+
+//MarshalMap marshals value with v2 attributevalue.MarshalMap, it returns SDK v1 attribute values
+func MarshalMap(in interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	values, err := attributevalue.MarshalMap(in)
+	if err != nil {
+		return nil, err
+	}
+	var result = make(map[string]*dynamodb.AttributeValue, len(values))
+	for key, value := range values {
+		result[key] = toV1(value)
+	}
+	return result, nil
+}
+
+//UnmarshalMap unmarshals SDK v1 attribute values with v2 attributevalue.UnmarshalMap
+func UnmarshalMap(item map[string]*dynamodb.AttributeValue, out interface{}) error {
+	var values = make(map[string]types.AttributeValue, len(item))
+	for key, value := range item {
+		if value != nil {
+			values[key] = toV2(value)
+		}
+	}
+	return attributevalue.UnmarshalMap(values, out)
+}
@@ -0,0 +1,82 @@
+package sdkv2
+
+import (
+	"context"
+	dynamodbstreamsv2 "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
+)
+
+//StreamsClient adapts SDK v2 DynamoDB Streams client to SDK v1 interface, calls are context first
+type StreamsClient struct {
+	client *dynamodbstreamsv2.Client
+}
+
+var _ dynamodbstreamsiface.DynamoDBStreamsAPI = (*StreamsClient)(nil)
+
+//NewStreams creates v1 interface adapter for SDK v2 DynamoDB Streams client
+func NewStreams(client *dynamodbstreamsv2.Client) *StreamsClient {
+	return &StreamsClient{client: client}
+}
+
+//NewStreamsClient creates SDK v2 DynamoDB Streams client with config.LoadDefaultConfig
+func NewStreamsClient(ctx context.Context, options *Options) (*StreamsClient, error) {
+	v2Config, err := options.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := dynamodbstreamsv2.NewFromConfig(v2Config, func(v2Options *dynamodbstreamsv2.Options) {
+		v2Options.BaseEndpoint = options.endpoint()
+	})
+	return NewStreams(client), nil
+}
+
+func (c *StreamsClient) DescribeStream(input *dynamodbstreams.DescribeStreamInput) (*dynamodbstreams.DescribeStreamOutput, error) {
+	return c.DescribeStreamWithContext(context.Background(), input)
+}
+
+func (c *StreamsClient) DescribeStreamWithContext(ctx aws.Context, input *dynamodbstreams.DescribeStreamInput, _ ...request.Option) (*dynamodbstreams.DescribeStreamOutput, error) {
+	return call[dynamodbstreams.DescribeStreamOutput](ctx, input, c.client.DescribeStream)
+}
+
+func (c *StreamsClient) DescribeStreamRequest(_ *dynamodbstreams.DescribeStreamInput) (*request.Request, *dynamodbstreams.DescribeStreamOutput) {
+	return unsupportedRequest("DescribeStreamRequest"), &dynamodbstreams.DescribeStreamOutput{}
+}
+
+func (c *StreamsClient) GetRecords(input *dynamodbstreams.GetRecordsInput) (*dynamodbstreams.GetRecordsOutput, error) {
+	return c.GetRecordsWithContext(context.Background(), input)
+}
+
+func (c *StreamsClient) GetRecordsWithContext(ctx aws.Context, input *dynamodbstreams.GetRecordsInput, _ ...request.Option) (*dynamodbstreams.GetRecordsOutput, error) {
+	return call[dynamodbstreams.GetRecordsOutput](ctx, input, c.client.GetRecords)
+}
+
+func (c *StreamsClient) GetRecordsRequest(_ *dynamodbstreams.GetRecordsInput) (*request.Request, *dynamodbstreams.GetRecordsOutput) {
+	return unsupportedRequest("GetRecordsRequest"), &dynamodbstreams.GetRecordsOutput{}
+}
+
+func (c *StreamsClient) GetShardIterator(input *dynamodbstreams.GetShardIteratorInput) (*dynamodbstreams.GetShardIteratorOutput, error) {
+	return c.GetShardIteratorWithContext(context.Background(), input)
+}
+
+func (c *StreamsClient) GetShardIteratorWithContext(ctx aws.Context, input *dynamodbstreams.GetShardIteratorInput, _ ...request.Option) (*dynamodbstreams.GetShardIteratorOutput, error) {
+	return call[dynamodbstreams.GetShardIteratorOutput](ctx, input, c.client.GetShardIterator)
+}
+
+func (c *StreamsClient) GetShardIteratorRequest(_ *dynamodbstreams.GetShardIteratorInput) (*request.Request, *dynamodbstreams.GetShardIteratorOutput) {
+	return unsupportedRequest("GetShardIteratorRequest"), &dynamodbstreams.GetShardIteratorOutput{}
+}
+
+func (c *StreamsClient) ListStreams(input *dynamodbstreams.ListStreamsInput) (*dynamodbstreams.ListStreamsOutput, error) {
+	return c.ListStreamsWithContext(context.Background(), input)
+}
+
+func (c *StreamsClient) ListStreamsWithContext(ctx aws.Context, input *dynamodbstreams.ListStreamsInput, _ ...request.Option) (*dynamodbstreams.ListStreamsOutput, error) {
+	return call[dynamodbstreams.ListStreamsOutput](ctx, input, c.client.ListStreams)
+}
+
+func (c *StreamsClient) ListStreamsRequest(_ *dynamodbstreams.ListStreamsInput) (*request.Request, *dynamodbstreams.ListStreamsOutput) {
+	return unsupportedRequest("ListStreamsRequest"), &dynamodbstreams.ListStreamsOutput{}
+}
@@ -0,0 +1,486 @@
+package sdkv2
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+//ErrCodeUnsupportedOperation is returned for SDK v1 interface operations not implemented with SDK v2
+const ErrCodeUnsupportedOperation = "UnsupportedOperation"
+
+//unsupported returns UnsupportedOperation error
+func unsupported(operation string) error {
+	return awserr.New(ErrCodeUnsupportedOperation, fmt.Sprintf("%v is not supported by SDK v2 client", operation), nil)
+}
+
+//unsupportedRequest returns request failing on Send with UnsupportedOperation error
+func unsupportedRequest(operation string) *request.Request {
+	return &request.Request{Error: unsupported(operation)}
+}
+
+func (c *Client) BatchExecuteStatementRequest(_ *dynamodb.BatchExecuteStatementInput) (*request.Request, *dynamodb.BatchExecuteStatementOutput) {
+	return unsupportedRequest("BatchExecuteStatementRequest"), &dynamodb.BatchExecuteStatementOutput{}
+}
+
+func (c *Client) BatchGetItemPages(_ *dynamodb.BatchGetItemInput, _ func(*dynamodb.BatchGetItemOutput, bool) bool) error {
+	return unsupported("BatchGetItemPages")
+}
+
+func (c *Client) BatchGetItemPagesWithContext(_ aws.Context, _ *dynamodb.BatchGetItemInput, _ func(*dynamodb.BatchGetItemOutput, bool) bool, _ ...request.Option) error {
+	return unsupported("BatchGetItemPages")
+}
+
+func (c *Client) BatchGetItemRequest(_ *dynamodb.BatchGetItemInput) (*request.Request, *dynamodb.BatchGetItemOutput) {
+	return unsupportedRequest("BatchGetItemRequest"), &dynamodb.BatchGetItemOutput{}
+}
+
+func (c *Client) BatchWriteItemRequest(_ *dynamodb.BatchWriteItemInput) (*request.Request, *dynamodb.BatchWriteItemOutput) {
+	return unsupportedRequest("BatchWriteItemRequest"), &dynamodb.BatchWriteItemOutput{}
+}
+
+func (c *Client) CreateBackupRequest(_ *dynamodb.CreateBackupInput) (*request.Request, *dynamodb.CreateBackupOutput) {
+	return unsupportedRequest("CreateBackupRequest"), &dynamodb.CreateBackupOutput{}
+}
+
+func (c *Client) CreateGlobalTable(_ *dynamodb.CreateGlobalTableInput) (*dynamodb.CreateGlobalTableOutput, error) {
+	return nil, unsupported("CreateGlobalTable")
+}
+
+func (c *Client) CreateGlobalTableRequest(_ *dynamodb.CreateGlobalTableInput) (*request.Request, *dynamodb.CreateGlobalTableOutput) {
+	return unsupportedRequest("CreateGlobalTableRequest"), &dynamodb.CreateGlobalTableOutput{}
+}
+
+func (c *Client) CreateGlobalTableWithContext(_ aws.Context, _ *dynamodb.CreateGlobalTableInput, _ ...request.Option) (*dynamodb.CreateGlobalTableOutput, error) {
+	return nil, unsupported("CreateGlobalTable")
+}
+
+func (c *Client) CreateTableRequest(_ *dynamodb.CreateTableInput) (*request.Request, *dynamodb.CreateTableOutput) {
+	return unsupportedRequest("CreateTableRequest"), &dynamodb.CreateTableOutput{}
+}
+
+func (c *Client) DeleteBackup(_ *dynamodb.DeleteBackupInput) (*dynamodb.DeleteBackupOutput, error) {
+	return nil, unsupported("DeleteBackup")
+}
+
+func (c *Client) DeleteBackupRequest(_ *dynamodb.DeleteBackupInput) (*request.Request, *dynamodb.DeleteBackupOutput) {
+	return unsupportedRequest("DeleteBackupRequest"), &dynamodb.DeleteBackupOutput{}
+}
+
+func (c *Client) DeleteBackupWithContext(_ aws.Context, _ *dynamodb.DeleteBackupInput, _ ...request.Option) (*dynamodb.DeleteBackupOutput, error) {
+	return nil, unsupported("DeleteBackup")
+}
+
+func (c *Client) DeleteItemRequest(_ *dynamodb.DeleteItemInput) (*request.Request, *dynamodb.DeleteItemOutput) {
+	return unsupportedRequest("DeleteItemRequest"), &dynamodb.DeleteItemOutput{}
+}
+
+func (c *Client) DeleteResourcePolicy(_ *dynamodb.DeleteResourcePolicyInput) (*dynamodb.DeleteResourcePolicyOutput, error) {
+	return nil, unsupported("DeleteResourcePolicy")
+}
+
+func (c *Client) DeleteResourcePolicyRequest(_ *dynamodb.DeleteResourcePolicyInput) (*request.Request, *dynamodb.DeleteResourcePolicyOutput) {
+	return unsupportedRequest("DeleteResourcePolicyRequest"), &dynamodb.DeleteResourcePolicyOutput{}
+}
+
+func (c *Client) DeleteResourcePolicyWithContext(_ aws.Context, _ *dynamodb.DeleteResourcePolicyInput, _ ...request.Option) (*dynamodb.DeleteResourcePolicyOutput, error) {
+	return nil, unsupported("DeleteResourcePolicy")
+}
+
+func (c *Client) DeleteTableRequest(_ *dynamodb.DeleteTableInput) (*request.Request, *dynamodb.DeleteTableOutput) {
+	return unsupportedRequest("DeleteTableRequest"), &dynamodb.DeleteTableOutput{}
+}
+
+func (c *Client) DescribeContributorInsights(_ *dynamodb.DescribeContributorInsightsInput) (*dynamodb.DescribeContributorInsightsOutput, error) {
+	return nil, unsupported("DescribeContributorInsights")
+}
+
+func (c *Client) DescribeContributorInsightsRequest(_ *dynamodb.DescribeContributorInsightsInput) (*request.Request, *dynamodb.DescribeContributorInsightsOutput) {
+	return unsupportedRequest("DescribeContributorInsightsRequest"), &dynamodb.DescribeContributorInsightsOutput{}
+}
+
+func (c *Client) DescribeContributorInsightsWithContext(_ aws.Context, _ *dynamodb.DescribeContributorInsightsInput, _ ...request.Option) (*dynamodb.DescribeContributorInsightsOutput, error) {
+	return nil, unsupported("DescribeContributorInsights")
+}
+
+func (c *Client) DescribeEndpoints(_ *dynamodb.DescribeEndpointsInput) (*dynamodb.DescribeEndpointsOutput, error) {
+	return nil, unsupported("DescribeEndpoints")
+}
+
+func (c *Client) DescribeEndpointsRequest(_ *dynamodb.DescribeEndpointsInput) (*request.Request, *dynamodb.DescribeEndpointsOutput) {
+	return unsupportedRequest("DescribeEndpointsRequest"), &dynamodb.DescribeEndpointsOutput{}
+}
+
+func (c *Client) DescribeEndpointsWithContext(_ aws.Context, _ *dynamodb.DescribeEndpointsInput, _ ...request.Option) (*dynamodb.DescribeEndpointsOutput, error) {
+	return nil, unsupported("DescribeEndpoints")
+}
+
+func (c *Client) DescribeExport(_ *dynamodb.DescribeExportInput) (*dynamodb.DescribeExportOutput, error) {
+	return nil, unsupported("DescribeExport")
+}
+
+func (c *Client) DescribeExportRequest(_ *dynamodb.DescribeExportInput) (*request.Request, *dynamodb.DescribeExportOutput) {
+	return unsupportedRequest("DescribeExportRequest"), &dynamodb.DescribeExportOutput{}
+}
+
+func (c *Client) DescribeExportWithContext(_ aws.Context, _ *dynamodb.DescribeExportInput, _ ...request.Option) (*dynamodb.DescribeExportOutput, error) {
+	return nil, unsupported("DescribeExport")
+}
+
+func (c *Client) DescribeGlobalTable(_ *dynamodb.DescribeGlobalTableInput) (*dynamodb.DescribeGlobalTableOutput, error) {
+	return nil, unsupported("DescribeGlobalTable")
+}
+
+func (c *Client) DescribeGlobalTableRequest(_ *dynamodb.DescribeGlobalTableInput) (*request.Request, *dynamodb.DescribeGlobalTableOutput) {
+	return unsupportedRequest("DescribeGlobalTableRequest"), &dynamodb.DescribeGlobalTableOutput{}
+}
+
+func (c *Client) DescribeGlobalTableSettings(_ *dynamodb.DescribeGlobalTableSettingsInput) (*dynamodb.DescribeGlobalTableSettingsOutput, error) {
+	return nil, unsupported("DescribeGlobalTableSettings")
+}
+
+func (c *Client) DescribeGlobalTableSettingsRequest(_ *dynamodb.DescribeGlobalTableSettingsInput) (*request.Request, *dynamodb.DescribeGlobalTableSettingsOutput) {
+	return unsupportedRequest("DescribeGlobalTableSettingsRequest"), &dynamodb.DescribeGlobalTableSettingsOutput{}
+}
+
+func (c *Client) DescribeGlobalTableSettingsWithContext(_ aws.Context, _ *dynamodb.DescribeGlobalTableSettingsInput, _ ...request.Option) (*dynamodb.DescribeGlobalTableSettingsOutput, error) {
+	return nil, unsupported("DescribeGlobalTableSettings")
+}
+
+func (c *Client) DescribeGlobalTableWithContext(_ aws.Context, _ *dynamodb.DescribeGlobalTableInput, _ ...request.Option) (*dynamodb.DescribeGlobalTableOutput, error) {
+	return nil, unsupported("DescribeGlobalTable")
+}
+
+func (c *Client) DescribeImport(_ *dynamodb.DescribeImportInput) (*dynamodb.DescribeImportOutput, error) {
+	return nil, unsupported("DescribeImport")
+}
+
+func (c *Client) DescribeImportRequest(_ *dynamodb.DescribeImportInput) (*request.Request, *dynamodb.DescribeImportOutput) {
+	return unsupportedRequest("DescribeImportRequest"), &dynamodb.DescribeImportOutput{}
+}
+
+func (c *Client) DescribeImportWithContext(_ aws.Context, _ *dynamodb.DescribeImportInput, _ ...request.Option) (*dynamodb.DescribeImportOutput, error) {
+	return nil, unsupported("DescribeImport")
+}
+
+func (c *Client) DescribeKinesisStreamingDestination(_ *dynamodb.DescribeKinesisStreamingDestinationInput) (*dynamodb.DescribeKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("DescribeKinesisStreamingDestination")
+}
+
+func (c *Client) DescribeKinesisStreamingDestinationRequest(_ *dynamodb.DescribeKinesisStreamingDestinationInput) (*request.Request, *dynamodb.DescribeKinesisStreamingDestinationOutput) {
+	return unsupportedRequest("DescribeKinesisStreamingDestinationRequest"), &dynamodb.DescribeKinesisStreamingDestinationOutput{}
+}
+
+func (c *Client) DescribeKinesisStreamingDestinationWithContext(_ aws.Context, _ *dynamodb.DescribeKinesisStreamingDestinationInput, _ ...request.Option) (*dynamodb.DescribeKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("DescribeKinesisStreamingDestination")
+}
+
+func (c *Client) DescribeLimits(_ *dynamodb.DescribeLimitsInput) (*dynamodb.DescribeLimitsOutput, error) {
+	return nil, unsupported("DescribeLimits")
+}
+
+func (c *Client) DescribeLimitsRequest(_ *dynamodb.DescribeLimitsInput) (*request.Request, *dynamodb.DescribeLimitsOutput) {
+	return unsupportedRequest("DescribeLimitsRequest"), &dynamodb.DescribeLimitsOutput{}
+}
+
+func (c *Client) DescribeLimitsWithContext(_ aws.Context, _ *dynamodb.DescribeLimitsInput, _ ...request.Option) (*dynamodb.DescribeLimitsOutput, error) {
+	return nil, unsupported("DescribeLimits")
+}
+
+func (c *Client) DescribeTableReplicaAutoScaling(_ *dynamodb.DescribeTableReplicaAutoScalingInput) (*dynamodb.DescribeTableReplicaAutoScalingOutput, error) {
+	return nil, unsupported("DescribeTableReplicaAutoScaling")
+}
+
+func (c *Client) DescribeTableReplicaAutoScalingRequest(_ *dynamodb.DescribeTableReplicaAutoScalingInput) (*request.Request, *dynamodb.DescribeTableReplicaAutoScalingOutput) {
+	return unsupportedRequest("DescribeTableReplicaAutoScalingRequest"), &dynamodb.DescribeTableReplicaAutoScalingOutput{}
+}
+
+func (c *Client) DescribeTableReplicaAutoScalingWithContext(_ aws.Context, _ *dynamodb.DescribeTableReplicaAutoScalingInput, _ ...request.Option) (*dynamodb.DescribeTableReplicaAutoScalingOutput, error) {
+	return nil, unsupported("DescribeTableReplicaAutoScaling")
+}
+
+func (c *Client) DescribeTimeToLiveRequest(_ *dynamodb.DescribeTimeToLiveInput) (*request.Request, *dynamodb.DescribeTimeToLiveOutput) {
+	return unsupportedRequest("DescribeTimeToLiveRequest"), &dynamodb.DescribeTimeToLiveOutput{}
+}
+
+func (c *Client) DisableKinesisStreamingDestination(_ *dynamodb.DisableKinesisStreamingDestinationInput) (*dynamodb.DisableKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("DisableKinesisStreamingDestination")
+}
+
+func (c *Client) DisableKinesisStreamingDestinationRequest(_ *dynamodb.DisableKinesisStreamingDestinationInput) (*request.Request, *dynamodb.DisableKinesisStreamingDestinationOutput) {
+	return unsupportedRequest("DisableKinesisStreamingDestinationRequest"), &dynamodb.DisableKinesisStreamingDestinationOutput{}
+}
+
+func (c *Client) DisableKinesisStreamingDestinationWithContext(_ aws.Context, _ *dynamodb.DisableKinesisStreamingDestinationInput, _ ...request.Option) (*dynamodb.DisableKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("DisableKinesisStreamingDestination")
+}
+
+func (c *Client) EnableKinesisStreamingDestination(_ *dynamodb.EnableKinesisStreamingDestinationInput) (*dynamodb.EnableKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("EnableKinesisStreamingDestination")
+}
+
+func (c *Client) EnableKinesisStreamingDestinationRequest(_ *dynamodb.EnableKinesisStreamingDestinationInput) (*request.Request, *dynamodb.EnableKinesisStreamingDestinationOutput) {
+	return unsupportedRequest("EnableKinesisStreamingDestinationRequest"), &dynamodb.EnableKinesisStreamingDestinationOutput{}
+}
+
+func (c *Client) EnableKinesisStreamingDestinationWithContext(_ aws.Context, _ *dynamodb.EnableKinesisStreamingDestinationInput, _ ...request.Option) (*dynamodb.EnableKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("EnableKinesisStreamingDestination")
+}
+
+func (c *Client) ExecuteStatementRequest(_ *dynamodb.ExecuteStatementInput) (*request.Request, *dynamodb.ExecuteStatementOutput) {
+	return unsupportedRequest("ExecuteStatementRequest"), &dynamodb.ExecuteStatementOutput{}
+}
+
+func (c *Client) ExecuteTransactionRequest(_ *dynamodb.ExecuteTransactionInput) (*request.Request, *dynamodb.ExecuteTransactionOutput) {
+	return unsupportedRequest("ExecuteTransactionRequest"), &dynamodb.ExecuteTransactionOutput{}
+}
+
+func (c *Client) ExportTableToPointInTime(_ *dynamodb.ExportTableToPointInTimeInput) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+	return nil, unsupported("ExportTableToPointInTime")
+}
+
+func (c *Client) ExportTableToPointInTimeRequest(_ *dynamodb.ExportTableToPointInTimeInput) (*request.Request, *dynamodb.ExportTableToPointInTimeOutput) {
+	return unsupportedRequest("ExportTableToPointInTimeRequest"), &dynamodb.ExportTableToPointInTimeOutput{}
+}
+
+func (c *Client) ExportTableToPointInTimeWithContext(_ aws.Context, _ *dynamodb.ExportTableToPointInTimeInput, _ ...request.Option) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+	return nil, unsupported("ExportTableToPointInTime")
+}
+
+func (c *Client) GetItemRequest(_ *dynamodb.GetItemInput) (*request.Request, *dynamodb.GetItemOutput) {
+	return unsupportedRequest("GetItemRequest"), &dynamodb.GetItemOutput{}
+}
+
+func (c *Client) GetResourcePolicy(_ *dynamodb.GetResourcePolicyInput) (*dynamodb.GetResourcePolicyOutput, error) {
+	return nil, unsupported("GetResourcePolicy")
+}
+
+func (c *Client) GetResourcePolicyRequest(_ *dynamodb.GetResourcePolicyInput) (*request.Request, *dynamodb.GetResourcePolicyOutput) {
+	return unsupportedRequest("GetResourcePolicyRequest"), &dynamodb.GetResourcePolicyOutput{}
+}
+
+func (c *Client) GetResourcePolicyWithContext(_ aws.Context, _ *dynamodb.GetResourcePolicyInput, _ ...request.Option) (*dynamodb.GetResourcePolicyOutput, error) {
+	return nil, unsupported("GetResourcePolicy")
+}
+
+func (c *Client) ImportTable(_ *dynamodb.ImportTableInput) (*dynamodb.ImportTableOutput, error) {
+	return nil, unsupported("ImportTable")
+}
+
+func (c *Client) ImportTableRequest(_ *dynamodb.ImportTableInput) (*request.Request, *dynamodb.ImportTableOutput) {
+	return unsupportedRequest("ImportTableRequest"), &dynamodb.ImportTableOutput{}
+}
+
+func (c *Client) ImportTableWithContext(_ aws.Context, _ *dynamodb.ImportTableInput, _ ...request.Option) (*dynamodb.ImportTableOutput, error) {
+	return nil, unsupported("ImportTable")
+}
+
+func (c *Client) ListBackupsRequest(_ *dynamodb.ListBackupsInput) (*request.Request, *dynamodb.ListBackupsOutput) {
+	return unsupportedRequest("ListBackupsRequest"), &dynamodb.ListBackupsOutput{}
+}
+
+func (c *Client) ListContributorInsights(_ *dynamodb.ListContributorInsightsInput) (*dynamodb.ListContributorInsightsOutput, error) {
+	return nil, unsupported("ListContributorInsights")
+}
+
+func (c *Client) ListContributorInsightsPages(_ *dynamodb.ListContributorInsightsInput, _ func(*dynamodb.ListContributorInsightsOutput, bool) bool) error {
+	return unsupported("ListContributorInsightsPages")
+}
+
+func (c *Client) ListContributorInsightsPagesWithContext(_ aws.Context, _ *dynamodb.ListContributorInsightsInput, _ func(*dynamodb.ListContributorInsightsOutput, bool) bool, _ ...request.Option) error {
+	return unsupported("ListContributorInsightsPages")
+}
+
+func (c *Client) ListContributorInsightsRequest(_ *dynamodb.ListContributorInsightsInput) (*request.Request, *dynamodb.ListContributorInsightsOutput) {
+	return unsupportedRequest("ListContributorInsightsRequest"), &dynamodb.ListContributorInsightsOutput{}
+}
+
+func (c *Client) ListContributorInsightsWithContext(_ aws.Context, _ *dynamodb.ListContributorInsightsInput, _ ...request.Option) (*dynamodb.ListContributorInsightsOutput, error) {
+	return nil, unsupported("ListContributorInsights")
+}
+
+func (c *Client) ListExports(_ *dynamodb.ListExportsInput) (*dynamodb.ListExportsOutput, error) {
+	return nil, unsupported("ListExports")
+}
+
+func (c *Client) ListExportsPages(_ *dynamodb.ListExportsInput, _ func(*dynamodb.ListExportsOutput, bool) bool) error {
+	return unsupported("ListExportsPages")
+}
+
+func (c *Client) ListExportsPagesWithContext(_ aws.Context, _ *dynamodb.ListExportsInput, _ func(*dynamodb.ListExportsOutput, bool) bool, _ ...request.Option) error {
+	return unsupported("ListExportsPages")
+}
+
+func (c *Client) ListExportsRequest(_ *dynamodb.ListExportsInput) (*request.Request, *dynamodb.ListExportsOutput) {
+	return unsupportedRequest("ListExportsRequest"), &dynamodb.ListExportsOutput{}
+}
+
+func (c *Client) ListExportsWithContext(_ aws.Context, _ *dynamodb.ListExportsInput, _ ...request.Option) (*dynamodb.ListExportsOutput, error) {
+	return nil, unsupported("ListExports")
+}
+
+func (c *Client) ListGlobalTables(_ *dynamodb.ListGlobalTablesInput) (*dynamodb.ListGlobalTablesOutput, error) {
+	return nil, unsupported("ListGlobalTables")
+}
+
+func (c *Client) ListGlobalTablesRequest(_ *dynamodb.ListGlobalTablesInput) (*request.Request, *dynamodb.ListGlobalTablesOutput) {
+	return unsupportedRequest("ListGlobalTablesRequest"), &dynamodb.ListGlobalTablesOutput{}
+}
+
+func (c *Client) ListGlobalTablesWithContext(_ aws.Context, _ *dynamodb.ListGlobalTablesInput, _ ...request.Option) (*dynamodb.ListGlobalTablesOutput, error) {
+	return nil, unsupported("ListGlobalTables")
+}
+
+func (c *Client) ListImports(_ *dynamodb.ListImportsInput) (*dynamodb.ListImportsOutput, error) {
+	return nil, unsupported("ListImports")
+}
+
+func (c *Client) ListImportsPages(_ *dynamodb.ListImportsInput, _ func(*dynamodb.ListImportsOutput, bool) bool) error {
+	return unsupported("ListImportsPages")
+}
+
+func (c *Client) ListImportsPagesWithContext(_ aws.Context, _ *dynamodb.ListImportsInput, _ func(*dynamodb.ListImportsOutput, bool) bool, _ ...request.Option) error {
+	return unsupported("ListImportsPages")
+}
+
+func (c *Client) ListImportsRequest(_ *dynamodb.ListImportsInput) (*request.Request, *dynamodb.ListImportsOutput) {
+	return unsupportedRequest("ListImportsRequest"), &dynamodb.ListImportsOutput{}
+}
+
+func (c *Client) ListImportsWithContext(_ aws.Context, _ *dynamodb.ListImportsInput, _ ...request.Option) (*dynamodb.ListImportsOutput, error) {
+	return nil, unsupported("ListImports")
+}
+
+func (c *Client) ListTablesRequest(_ *dynamodb.ListTablesInput) (*request.Request, *dynamodb.ListTablesOutput) {
+	return unsupportedRequest("ListTablesRequest"), &dynamodb.ListTablesOutput{}
+}
+
+func (c *Client) ListTagsOfResourceRequest(_ *dynamodb.ListTagsOfResourceInput) (*request.Request, *dynamodb.ListTagsOfResourceOutput) {
+	return unsupportedRequest("ListTagsOfResourceRequest"), &dynamodb.ListTagsOfResourceOutput{}
+}
+
+func (c *Client) PutItemRequest(_ *dynamodb.PutItemInput) (*request.Request, *dynamodb.PutItemOutput) {
+	return unsupportedRequest("PutItemRequest"), &dynamodb.PutItemOutput{}
+}
+
+func (c *Client) PutResourcePolicy(_ *dynamodb.PutResourcePolicyInput) (*dynamodb.PutResourcePolicyOutput, error) {
+	return nil, unsupported("PutResourcePolicy")
+}
+
+func (c *Client) PutResourcePolicyRequest(_ *dynamodb.PutResourcePolicyInput) (*request.Request, *dynamodb.PutResourcePolicyOutput) {
+	return unsupportedRequest("PutResourcePolicyRequest"), &dynamodb.PutResourcePolicyOutput{}
+}
+
+func (c *Client) PutResourcePolicyWithContext(_ aws.Context, _ *dynamodb.PutResourcePolicyInput, _ ...request.Option) (*dynamodb.PutResourcePolicyOutput, error) {
+	return nil, unsupported("PutResourcePolicy")
+}
+
+func (c *Client) QueryRequest(_ *dynamodb.QueryInput) (*request.Request, *dynamodb.QueryOutput) {
+	return unsupportedRequest("QueryRequest"), &dynamodb.QueryOutput{}
+}
+
+func (c *Client) RestoreTableFromBackupRequest(_ *dynamodb.RestoreTableFromBackupInput) (*request.Request, *dynamodb.RestoreTableFromBackupOutput) {
+	return unsupportedRequest("RestoreTableFromBackupRequest"), &dynamodb.RestoreTableFromBackupOutput{}
+}
+
+func (c *Client) RestoreTableToPointInTimeRequest(_ *dynamodb.RestoreTableToPointInTimeInput) (*request.Request, *dynamodb.RestoreTableToPointInTimeOutput) {
+	return unsupportedRequest("RestoreTableToPointInTimeRequest"), &dynamodb.RestoreTableToPointInTimeOutput{}
+}
+
+func (c *Client) ScanRequest(_ *dynamodb.ScanInput) (*request.Request, *dynamodb.ScanOutput) {
+	return unsupportedRequest("ScanRequest"), &dynamodb.ScanOutput{}
+}
+
+func (c *Client) TagResourceRequest(_ *dynamodb.TagResourceInput) (*request.Request, *dynamodb.TagResourceOutput) {
+	return unsupportedRequest("TagResourceRequest"), &dynamodb.TagResourceOutput{}
+}
+
+func (c *Client) TransactGetItemsRequest(_ *dynamodb.TransactGetItemsInput) (*request.Request, *dynamodb.TransactGetItemsOutput) {
+	return unsupportedRequest("TransactGetItemsRequest"), &dynamodb.TransactGetItemsOutput{}
+}
+
+func (c *Client) TransactWriteItemsRequest(_ *dynamodb.TransactWriteItemsInput) (*request.Request, *dynamodb.TransactWriteItemsOutput) {
+	return unsupportedRequest("TransactWriteItemsRequest"), &dynamodb.TransactWriteItemsOutput{}
+}
+
+func (c *Client) UntagResourceRequest(_ *dynamodb.UntagResourceInput) (*request.Request, *dynamodb.UntagResourceOutput) {
+	return unsupportedRequest("UntagResourceRequest"), &dynamodb.UntagResourceOutput{}
+}
+
+func (c *Client) UpdateContinuousBackupsRequest(_ *dynamodb.UpdateContinuousBackupsInput) (*request.Request, *dynamodb.UpdateContinuousBackupsOutput) {
+	return unsupportedRequest("UpdateContinuousBackupsRequest"), &dynamodb.UpdateContinuousBackupsOutput{}
+}
+
+func (c *Client) UpdateContributorInsights(_ *dynamodb.UpdateContributorInsightsInput) (*dynamodb.UpdateContributorInsightsOutput, error) {
+	return nil, unsupported("UpdateContributorInsights")
+}
+
+func (c *Client) UpdateContributorInsightsRequest(_ *dynamodb.UpdateContributorInsightsInput) (*request.Request, *dynamodb.UpdateContributorInsightsOutput) {
+	return unsupportedRequest("UpdateContributorInsightsRequest"), &dynamodb.UpdateContributorInsightsOutput{}
+}
+
+func (c *Client) UpdateContributorInsightsWithContext(_ aws.Context, _ *dynamodb.UpdateContributorInsightsInput, _ ...request.Option) (*dynamodb.UpdateContributorInsightsOutput, error) {
+	return nil, unsupported("UpdateContributorInsights")
+}
+
+func (c *Client) UpdateGlobalTable(_ *dynamodb.UpdateGlobalTableInput) (*dynamodb.UpdateGlobalTableOutput, error) {
+	return nil, unsupported("UpdateGlobalTable")
+}
+
+func (c *Client) UpdateGlobalTableRequest(_ *dynamodb.UpdateGlobalTableInput) (*request.Request, *dynamodb.UpdateGlobalTableOutput) {
+	return unsupportedRequest("UpdateGlobalTableRequest"), &dynamodb.UpdateGlobalTableOutput{}
+}
+
+func (c *Client) UpdateGlobalTableSettings(_ *dynamodb.UpdateGlobalTableSettingsInput) (*dynamodb.UpdateGlobalTableSettingsOutput, error) {
+	return nil, unsupported("UpdateGlobalTableSettings")
+}
+
+func (c *Client) UpdateGlobalTableSettingsRequest(_ *dynamodb.UpdateGlobalTableSettingsInput) (*request.Request, *dynamodb.UpdateGlobalTableSettingsOutput) {
+	return unsupportedRequest("UpdateGlobalTableSettingsRequest"), &dynamodb.UpdateGlobalTableSettingsOutput{}
+}
+
+func (c *Client) UpdateGlobalTableSettingsWithContext(_ aws.Context, _ *dynamodb.UpdateGlobalTableSettingsInput, _ ...request.Option) (*dynamodb.UpdateGlobalTableSettingsOutput, error) {
+	return nil, unsupported("UpdateGlobalTableSettings")
+}
+
+func (c *Client) UpdateGlobalTableWithContext(_ aws.Context, _ *dynamodb.UpdateGlobalTableInput, _ ...request.Option) (*dynamodb.UpdateGlobalTableOutput, error) {
+	return nil, unsupported("UpdateGlobalTable")
+}
+
+func (c *Client) UpdateItemRequest(_ *dynamodb.UpdateItemInput) (*request.Request, *dynamodb.UpdateItemOutput) {
+	return unsupportedRequest("UpdateItemRequest"), &dynamodb.UpdateItemOutput{}
+}
+
+func (c *Client) UpdateKinesisStreamingDestination(_ *dynamodb.UpdateKinesisStreamingDestinationInput) (*dynamodb.UpdateKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("UpdateKinesisStreamingDestination")
+}
+
+func (c *Client) UpdateKinesisStreamingDestinationRequest(_ *dynamodb.UpdateKinesisStreamingDestinationInput) (*request.Request, *dynamodb.UpdateKinesisStreamingDestinationOutput) {
+	return unsupportedRequest("UpdateKinesisStreamingDestinationRequest"), &dynamodb.UpdateKinesisStreamingDestinationOutput{}
+}
+
+func (c *Client) UpdateKinesisStreamingDestinationWithContext(_ aws.Context, _ *dynamodb.UpdateKinesisStreamingDestinationInput, _ ...request.Option) (*dynamodb.UpdateKinesisStreamingDestinationOutput, error) {
+	return nil, unsupported("UpdateKinesisStreamingDestination")
+}
+
+func (c *Client) UpdateTableReplicaAutoScaling(_ *dynamodb.UpdateTableReplicaAutoScalingInput) (*dynamodb.UpdateTableReplicaAutoScalingOutput, error) {
+	return nil, unsupported("UpdateTableReplicaAutoScaling")
+}
+
+func (c *Client) UpdateTableReplicaAutoScalingRequest(_ *dynamodb.UpdateTableReplicaAutoScalingInput) (*request.Request, *dynamodb.UpdateTableReplicaAutoScalingOutput) {
+	return unsupportedRequest("UpdateTableReplicaAutoScalingRequest"), &dynamodb.UpdateTableReplicaAutoScalingOutput{}
+}
+
+func (c *Client) UpdateTableReplicaAutoScalingWithContext(_ aws.Context, _ *dynamodb.UpdateTableReplicaAutoScalingInput, _ ...request.Option) (*dynamodb.UpdateTableReplicaAutoScalingOutput, error) {
+	return nil, unsupported("UpdateTableReplicaAutoScaling")
+}
+
+func (c *Client) UpdateTableRequest(_ *dynamodb.UpdateTableInput) (*request.Request, *dynamodb.UpdateTableOutput) {
+	return unsupportedRequest("UpdateTableRequest"), &dynamodb.UpdateTableOutput{}
+}
+
+func (c *Client) UpdateTimeToLiveRequest(_ *dynamodb.UpdateTimeToLiveInput) (*request.Request, *dynamodb.UpdateTimeToLiveOutput) {
+	return unsupportedRequest("UpdateTimeToLiveRequest"), &dynamodb.UpdateTimeToLiveOutput{}
+}
@@ -0,0 +1,172 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+)
+
+//driverName represents database/sql driver name
+const driverName = "dyndb"
+
+//sqlDriver represents database/sql driver, DSN uses dsc parameters format i.e. region:us-west-1,endpoint:localhost,key:xxx,secret:yyy
+type sqlDriver struct{}
+
+//Open opens a new connection for supplied DSN
+func (d *sqlDriver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+//OpenConnector creates a connector sharing one manager across all database/sql connections
+func (d *sqlDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	config, err := dsc.NewConfigWithParameters(driverName, "", "", toolbox.MakeMap(dsn, ":", ","))
+	if err != nil {
+		return nil, err
+	}
+	manager, err := newManagerFactory().Create(config)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConnector{driver: d, manager: manager}, nil
+}
+
+type sqlConnector struct {
+	driver  *sqlDriver
+	manager dsc.Manager
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	connection, err := c.manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{manager: c.manager.(*manager), connection: connection}, nil
+}
+
+func (c *sqlConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+//sqlConn represents database/sql connection, statements are translated by the dyndb manager
+type sqlConn struct {
+	manager    *manager
+	connection dsc.Connection
+	tx         *sqlTx
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return &sqlStmt{conn: c, query: query}, nil
+}
+
+func (c *sqlConn) Close() error {
+	return c.connection.Close()
+}
+
+func (c *sqlConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, options driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, fmt.Errorf("transaction has already been started")
+	}
+	if options.ReadOnly {
+		return nil, fmt.Errorf("read only transactions are not supported")
+	}
+	switch sql.IsolationLevel(options.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return nil, fmt.Errorf("unsupported transaction isolation level: %v, TransactWriteItems is serializable", sql.IsolationLevel(options.Isolation))
+	}
+	c.tx = &sqlTx{conn: c, ctx: ctx}
+	return c.tx, nil
+}
+
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	parameters, err := namedValuesToParameters(args)
+	if err != nil {
+		return nil, err
+	}
+	if c.tx != nil {
+		return c.tx.add(ctx, query, parameters)
+	}
+	return c.manager.ExecuteOnConnection(withContext(c.connection, ctx), query, parameters)
+}
+
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	parameters, err := namedValuesToParameters(args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := newSQLRows(ctx, func(ctx context.Context, handler func(scanner dsc.Scanner) (bool, error)) error {
+		return c.manager.ReadAllOnWithHandlerOnConnection(withContext(c.connection, ctx), query, parameters, handler)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+//CheckNamedValue passes values as is since dynamodbattribute marshaler handles slices, maps and structs
+func (c *sqlConn) CheckNamedValue(value *driver.NamedValue) (err error) {
+	if valuer, ok := value.Value.(driver.Valuer); ok {
+		value.Value, err = valuer.Value()
+	}
+	return err
+}
+
+type sqlStmt struct {
+	conn  *sqlConn
+	query string
+}
+
+func (s *sqlStmt) Close() error {
+	return nil
+}
+
+//NumInput returns -1 as the number of '?' placeholders is validated during translation
+func (s *sqlStmt) NumInput() int {
+	return -1
+}
+
+func (s *sqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *sqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValuesToParameters(args []driver.NamedValue) ([]interface{}, error) {
+	var result = make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("named parameters are not supported: %v, use '?' placeholder", arg.Name)
+		}
+		result[i] = arg.Value
+	}
+	return result, nil
+}
+
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	var result = make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		result[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return result
+}
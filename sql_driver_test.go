@@ -0,0 +1,263 @@
+package dyndb_test
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSQLDriver(t *testing.T) {
+	db, err := sql.Open("dyndb", "endpoint:memory://sql,region:us-west-1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("DROP TABLE IF EXISTS sql_music")
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = db.Exec("CREATE TABLE sql_music(Artist VARCHAR(255) HASH KEY, SongTitle VARCHAR(255) RANGE KEY)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	tx, err := db.Begin()
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, title := range []string{"Title0", "Title1"} {
+		_, err = tx.Exec("INSERT INTO sql_music(Artist, SongTitle, ReleaseYear) VALUES(?, ?, ?)", "Artist0", title, 2000)
+		if !assert.Nil(t, err) {
+			return
+		}
+	}
+	if !assert.Nil(t, tx.Commit()) {
+		return
+	}
+
+	result, err := db.Exec("UPDATE sql_music SET ReleaseYear = ? WHERE Artist = ? AND SongTitle = ?", 2001, "Artist0", "Title1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	affected, _ := result.RowsAffected()
+	assert.EqualValues(t, 1, affected)
+
+	rows, err := db.Query("SELECT SongTitle, ReleaseYear FROM sql_music WHERE Artist = ? AND SongTitle = ?", "Artist0", "Title1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if assert.Nil(t, err) && assert.Len(t, types, 2) {
+		assert.EqualValues(t, "S", types[0].DatabaseTypeName())
+		assert.EqualValues(t, "N", types[1].DatabaseTypeName())
+	}
+	var count int
+	for rows.Next() {
+		var title string
+		var year int
+		if !assert.Nil(t, rows.Scan(&title, &year)) {
+			return
+		}
+		assert.EqualValues(t, "Title1", title)
+		assert.EqualValues(t, 2001, year)
+		count++
+	}
+	assert.EqualValues(t, 1, count)
+}
+
+func TestSQLDriver_Transaction(t *testing.T) {
+	db, err := sql.Open("dyndb", "endpoint:memory://sqlTx,region:us-west-1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+	for _, SQL := range []string{"DROP TABLE IF EXISTS sql_events", "CREATE TABLE sql_events(Id INT HASH KEY)"} {
+		_, err = db.Exec(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+
+	var useCases = []struct {
+		description string
+		options     *sql.TxOptions
+		hasError    bool
+	}{
+		{description: "default", options: &sql.TxOptions{}},
+		{description: "serializable", options: &sql.TxOptions{Isolation: sql.LevelSerializable}},
+		{description: "read committed", options: &sql.TxOptions{Isolation: sql.LevelReadCommitted}, hasError: true},
+		{description: "read only", options: &sql.TxOptions{ReadOnly: true}, hasError: true},
+	}
+	for _, useCase := range useCases {
+		tx, err := db.BeginTx(context.Background(), useCase.options)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.Nil(t, tx.Rollback(), useCase.description)
+		}
+	}
+
+	tx, err := db.Begin()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer tx.Rollback()
+	for i := 1; i <= 100; i++ {
+		_, err = tx.Exec("INSERT INTO sql_events(Id) VALUES(?)", i)
+		if !assert.Nil(t, err, i) {
+			return
+		}
+	}
+	_, err = tx.Exec("INSERT INTO sql_events(Id) VALUES(?)", 101)
+	assert.NotNil(t, err)
+}
+
+func TestSQLDriver_TransactionUpdate(t *testing.T) {
+	db, err := sql.Open("dyndb", "endpoint:memory://sqlTxUpdate,region:us-west-1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+	for _, SQL := range []string{"DROP TABLE IF EXISTS sql_songs", "CREATE TABLE sql_songs(Artist VARCHAR(255) HASH KEY, SongTitle VARCHAR(255) RANGE KEY)"} {
+		_, err = db.Exec(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	for _, title := range []string{"Title0", "Title1", "Title2", "Title3"} {
+		_, err = db.Exec("INSERT INTO sql_songs(Artist, SongTitle, ReleaseYear) VALUES(?, ?, ?)", "Artist0", title, 2000)
+		if !assert.Nil(t, err, title) {
+			return
+		}
+	}
+
+	var useCases = []struct {
+		description string
+		SQL         string
+		parameters  []interface{}
+		expect      int64
+		hasError    bool
+	}{
+		{
+			description: "tuple IN",
+			SQL:         "UPDATE sql_songs SET ReleaseYear = ? WHERE (Artist, SongTitle) IN ((?, ?), (?, ?))",
+			parameters:  []interface{}{2001, "Artist0", "Title0", "Artist0", "Title1"},
+			expect:      2,
+		},
+		{
+			description: "range key IN",
+			SQL:         "UPDATE sql_songs SET ReleaseYear = ? WHERE Artist = ? AND SongTitle IN (?, ?)",
+			parameters:  []interface{}{2002, "Artist0", "Title2", "Title2"},
+			expect:      1,
+		},
+		{
+			description: "missing item skipped as without transaction",
+			SQL:         "UPDATE sql_songs SET ReleaseYear = ? WHERE (Artist, SongTitle) IN ((?, ?), (?, ?))",
+			parameters:  []interface{}{2004, "Artist0", "Title3", "Artist0", "Missing"},
+			expect:      1,
+		},
+		{
+			description: "without key criteria",
+			SQL:         "UPDATE sql_songs SET ReleaseYear = ? WHERE ReleaseYear = ?",
+			parameters:  []interface{}{2003, 2000},
+			hasError:    true,
+		},
+	}
+	tx, err := db.Begin()
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, useCase := range useCases {
+		result, err := tx.Exec(useCase.SQL, useCase.parameters...)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			affected, _ := result.RowsAffected()
+			assert.EqualValues(t, useCase.expect, affected, useCase.description)
+		}
+	}
+	if !assert.Nil(t, tx.Commit()) {
+		return
+	}
+	var expect = map[string]int{"Title0": 2001, "Title1": 2001, "Title2": 2002, "Title3": 2004}
+	for title, year := range expect {
+		var actual int
+		err = db.QueryRow("SELECT ReleaseYear FROM sql_songs WHERE Artist = ? AND SongTitle = ?", "Artist0", title).Scan(&actual)
+		if assert.Nil(t, err, title) {
+			assert.EqualValues(t, year, actual, title)
+		}
+	}
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM sql_songs").Scan(&count)
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, 4, count)
+	}
+	result, err := db.Exec("UPDATE sql_songs SET ReleaseYear = ? WHERE (Artist, SongTitle) IN ((?, ?), (?, ?))", 2001, "Artist0", "Title0", "Artist0", "Missing")
+	if assert.Nil(t, err) {
+		affected, _ := result.RowsAffected()
+		assert.EqualValues(t, 1, affected)
+	}
+}
+
+func TestSQLDriver_OrderByLimit(t *testing.T) {
+	db, err := sql.Open("dyndb", "endpoint:memory://sqlOrderBy,region:us-west-1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+	_, err = db.Exec("DROP TABLE IF EXISTS sql_music")
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = db.Exec("CREATE TABLE sql_music(Artist VARCHAR(255) HASH KEY, SongTitle VARCHAR(255) RANGE KEY)")
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, title := range []string{"Title0", "Title1", "Title2"} {
+		if _, err = db.Exec("INSERT INTO sql_music(Artist, SongTitle) VALUES(?, ?)", "Artist0", title); !assert.Nil(t, err) {
+			return
+		}
+	}
+	var useCases = []struct {
+		description string
+		SQL         string
+		expect      []string
+	}{
+		{
+			description: "sort key descending",
+			SQL:         "SELECT SongTitle FROM sql_music WHERE Artist = ? ORDER BY SongTitle DESC",
+			expect:      []string{"Title2", "Title1", "Title0"},
+		},
+		{
+			description: "sort key descending with limit",
+			SQL:         "SELECT SongTitle FROM sql_music m WHERE Artist = ? ORDER BY SongTitle DESC LIMIT 2",
+			expect:      []string{"Title2", "Title1"},
+		},
+		{
+			description: "limit",
+			SQL:         "SELECT SongTitle FROM sql_music WHERE Artist = ? LIMIT 1",
+			expect:      []string{"Title0"},
+		},
+	}
+	for _, useCase := range useCases {
+		rows, err := db.Query(useCase.SQL, "Artist0")
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		var titles []string
+		for rows.Next() {
+			var title string
+			assert.Nil(t, rows.Scan(&title), useCase.description)
+			titles = append(titles, title)
+		}
+		assert.Nil(t, rows.Close(), useCase.description)
+		assert.EqualValues(t, useCase.expect, titles, useCase.description)
+	}
+}
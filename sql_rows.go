@@ -0,0 +1,145 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"github.com/viant/dsc"
+	"io"
+	"time"
+)
+
+//sqlRows represents database/sql rows streamed from reading handler running in its own goroutine, the handler waits till
+//a row is consumed so memory use does not depend on result size, closing rows or cancelling query context stops reading,
+//column types are derived from the first non nil value read so far with getAttributeType
+type sqlRows struct {
+	columns []string
+	types   []string
+	values  chan map[string]interface{}
+	done    chan struct{}
+	pending []driver.Value
+	err     error
+	cancel  context.CancelFunc
+}
+
+//scan passes scanned values to rows consumer, it returns false once rows are closed or context is cancelled
+func (r *sqlRows) scan(ctx context.Context, scanner dsc.Scanner) (bool, error) {
+	if r.columns == nil {
+		columns, err := scanner.Columns()
+		if err != nil {
+			return false, err
+		}
+		r.columns = columns
+		r.types = make([]string, len(columns))
+	}
+	var values = make(map[string]interface{})
+	if err := scanner.Scan(values); err != nil {
+		return false, err
+	}
+	select {
+	case r.values <- values:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+//record converts scanned values into driver values
+func (r *sqlRows) record(values map[string]interface{}) ([]driver.Value, error) {
+	var record = make([]driver.Value, len(r.columns))
+	for i, column := range r.columns {
+		value := values[column]
+		if value == nil {
+			continue
+		}
+		if r.types[i] == "" {
+			attribute, err := marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			r.types[i] = getAttributeType(attribute)
+		}
+		converted, err := asDriverValue(value)
+		if err != nil {
+			return nil, err
+		}
+		record[i] = converted
+	}
+	return record, nil
+}
+
+func (r *sqlRows) Columns() []string {
+	return r.columns
+}
+
+func (r *sqlRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.types[index]
+}
+
+//Close stops reading and waits till reading handler returns
+func (r *sqlRows) Close() error {
+	r.cancel()
+	<-r.done
+	r.pending = nil
+	return nil
+}
+
+func (r *sqlRows) Next(dest []driver.Value) error {
+	record := r.pending
+	r.pending = nil
+	if record == nil {
+		values, ok := <-r.values
+		if !ok {
+			if r.err != nil {
+				return r.err
+			}
+			return io.EOF
+		}
+		var err error
+		if record, err = r.record(values); err != nil {
+			return err
+		}
+	}
+	copy(dest, record)
+	return nil
+}
+
+//asDriverValue converts unmarshaled attribute into driver value, lists, sets and maps are JSON encoded
+func asDriverValue(value interface{}) (driver.Value, error) {
+	switch actual := value.(type) {
+	case string, bool, float64, int64, []byte, time.Time:
+		return actual, nil
+	case int:
+		return int64(actual), nil
+	}
+	return json.Marshal(value)
+}
+
+//newSQLRows starts reading rows with supplied read function, it waits for the first row so that read errors,
+//columns and column types of the first row are known when rows are returned
+func newSQLRows(ctx context.Context, read func(ctx context.Context, handler func(scanner dsc.Scanner) (bool, error)) error) (*sqlRows, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	result := &sqlRows{values: make(chan map[string]interface{}), done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer close(result.done)
+		defer close(result.values)
+		result.err = read(ctx, func(scanner dsc.Scanner) (bool, error) {
+			return result.scan(ctx, scanner)
+		})
+	}()
+	values, ok := <-result.values
+	if !ok {
+		cancel()
+		if result.err != nil {
+			return nil, result.err
+		}
+		return result, nil
+	}
+	record, err := result.record(values)
+	if err != nil {
+		_ = result.Close()
+		return nil, err
+	}
+	result.pending = record
+	return result, nil
+}
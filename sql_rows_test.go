@@ -0,0 +1,146 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"io"
+	"testing"
+)
+
+func TestSQLRows(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	statement := &dsc.QueryStatement{BaseStatement: &dsc.BaseStatement{Columns: []*dsc.SQLColumn{{Name: "Id"}, {Name: "Name"}}}}
+	//readRows passes count rows to handler, negative count reads till handler stops, it records number of handled rows
+	readRows := func(count int, handled *int, readErr error) func(ctx context.Context, handler func(scanner dsc.Scanner) (bool, error)) error {
+		return func(ctx context.Context, handler func(scanner dsc.Scanner) (bool, error)) error {
+			for i := 0; count < 0 || i < count; i++ {
+				scanner := dsc.NewSQLScanner(statement, config, []string{"Id", "Name"})
+				scanner.Values = map[string]interface{}{"Id": i}
+				if i > 0 {
+					scanner.Values["Name"] = fmt.Sprintf("name%d", i)
+				}
+				toContinue, err := handler(scanner)
+				if err != nil || !toContinue {
+					return err
+				}
+				*handled = i + 1
+			}
+			return readErr
+		}
+	}
+	var useCases = []struct {
+		description string
+		count       int
+		readErr     error
+		closeAfter  int
+		expect      int
+		handled     int
+		types       []string
+		hasError    bool
+		hasNextErr  bool
+	}{
+		{
+			description: "all rows",
+			count:       3,
+			expect:      3,
+			handled:     3,
+			types:       []string{"N", "S"},
+		},
+		{
+			description: "no rows",
+			expect:      0,
+			types:       nil,
+		},
+		{
+			description: "close stops reading",
+			count:       -1,
+			closeAfter:  2,
+			expect:      2,
+			handled:     2,
+			types:       []string{"N", "S"},
+		},
+		{
+			description: "error before first row",
+			readErr:     fmt.Errorf("test error"),
+			hasError:    true,
+		},
+		{
+			description: "error after rows",
+			count:       2,
+			readErr:     fmt.Errorf("test error"),
+			expect:      2,
+			handled:     2,
+			types:       []string{"N", "S"},
+			hasNextErr:  true,
+		},
+	}
+	for _, useCase := range useCases {
+		var handled int
+		rows, err := newSQLRows(context.Background(), readRows(useCase.count, &handled, useCase.readErr))
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		if len(useCase.types) > 0 {
+			assert.EqualValues(t, "N", rows.ColumnTypeDatabaseTypeName(0), useCase.description)
+		}
+		var count int
+		var nextErr error
+		dest := make([]driver.Value, len(rows.Columns()))
+		for useCase.closeAfter == 0 || count < useCase.closeAfter {
+			if nextErr = rows.Next(dest); nextErr != nil {
+				break
+			}
+			count++
+		}
+		assert.Nil(t, rows.Close(), useCase.description)
+		assert.EqualValues(t, useCase.expect, count, useCase.description)
+		assert.EqualValues(t, useCase.handled, handled, useCase.description)
+		assert.EqualValues(t, useCase.types, rows.types, useCase.description)
+		if useCase.hasNextErr {
+			assert.EqualValues(t, useCase.readErr, nextErr, useCase.description)
+		} else if useCase.closeAfter == 0 {
+			assert.EqualValues(t, io.EOF, nextErr, useCase.description)
+		}
+	}
+}
+
+func TestSQLRows_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	statement := &dsc.QueryStatement{BaseStatement: &dsc.BaseStatement{Columns: []*dsc.SQLColumn{{Name: "Id"}}}}
+	rows, err := newSQLRows(ctx, func(ctx context.Context, handler func(scanner dsc.Scanner) (bool, error)) error {
+		for i := 0; ; i++ {
+			scanner := dsc.NewSQLScanner(statement, config, []string{"Id"})
+			scanner.Values = map[string]interface{}{"Id": i}
+			if toContinue, err := handler(scanner); err != nil || !toContinue {
+				return err
+			}
+		}
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	dest := make([]driver.Value, 1)
+	assert.Nil(t, rows.Next(dest))
+	assert.EqualValues(t, 0, dest[0])
+	cancel()
+	for err == nil {
+		err = rows.Next(dest)
+	}
+	assert.EqualValues(t, context.Canceled, err)
+	assert.Nil(t, rows.Close())
+}
@@ -0,0 +1,120 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+)
+
+//maxTransactWriteItems max number of items supported by TransactWriteItems
+const maxTransactWriteItems = 100
+
+//sqlTx represents database/sql transaction, DML statements are collected and committed with TransactWriteItems
+type sqlTx struct {
+	conn  *sqlConn
+	ctx   context.Context
+	items []*dynamodb.TransactWriteItem
+}
+
+func (t *sqlTx) add(ctx context.Context, SQL string, parameters []interface{}) (driver.Result, error) {
+	parameters, returned, err := outParameter(parameters)
+	if err != nil {
+		return nil, err
+	}
+	if returned != nil {
+		return nil, fmt.Errorf("sql.Out parameter is not supported in transaction: %v", SQL)
+	}
+	db, err := asDatabase(t.conn.connection)
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.conn.manager.transactWriteItems(ctx, db, SQL, parameters)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.items)+len(items) > maxTransactWriteItems {
+		return nil, fmt.Errorf("transaction exceeded %v items supported by TransactWriteItems: %v", maxTransactWriteItems, SQL)
+	}
+	t.items = append(t.items, items...)
+	return dsc.NewSQLResult(int64(len(items)), 0), nil
+}
+
+func (t *sqlTx) Commit() error {
+	defer t.close()
+	if len(t.items) == 0 {
+		return nil
+	}
+	db, err := asDatabase(t.conn.connection)
+	if err != nil {
+		return err
+	}
+	_, err = db.TransactWriteItemsWithContext(t.ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: t.items,
+	})
+	return err
+}
+
+func (t *sqlTx) Rollback() error {
+	t.close()
+	return nil
+}
+
+func (t *sqlTx) close() {
+	t.items = nil
+	t.conn.tx = nil
+}
+
+//transactWriteItems returns transact write items for supplied DML, UPDATE returns an item for each existing item matching the key,
+//the same items as updated without transaction, an item deleted before commit cancels the transaction
+func (m *manager) transactWriteItems(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string, sqlParameters []interface{}) ([]*dynamodb.TransactWriteItem, error) {
+	parser := dsc.NewDmlParser()
+	statement, err := parser.Parse(SQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v due to %v", SQL, err)
+	}
+	statement.Table = m.tableName(statement.Table)
+	switch statement.Type {
+	case "INSERT":
+		input, err := m.insertInput(statement, sqlParameters)
+		if err != nil {
+			return nil, err
+		}
+		return []*dynamodb.TransactWriteItem{{Put: &dynamodb.Put{
+			TableName: input.TableName,
+			Item:      input.Item,
+		}}}, nil
+	case "UPDATE":
+		inputs, err := m.updateInputs(ctx, db, SQL, statement, sqlParameters)
+		if err != nil {
+			return nil, err
+		}
+		var result = make([]*dynamodb.TransactWriteItem, 0, len(inputs))
+		for _, input := range inputs {
+			result = append(result, &dynamodb.TransactWriteItem{Update: &dynamodb.Update{
+				TableName:                 input.TableName,
+				Key:                       input.Key,
+				UpdateExpression:          input.UpdateExpression,
+				ConditionExpression:       input.ConditionExpression,
+				ExpressionAttributeNames:  input.ExpressionAttributeNames,
+				ExpressionAttributeValues: input.ExpressionAttributeValues,
+			}})
+		}
+		return result, nil
+	case "DELETE":
+		input, err := m.deleteInput(statement, sqlParameters)
+		if err != nil {
+			return nil, err
+		}
+		if input == nil {
+			return nil, fmt.Errorf("unsupported transactional delete without key criteria: %v", SQL)
+		}
+		return []*dynamodb.TransactWriteItem{{Delete: &dynamodb.Delete{
+			TableName: input.TableName,
+			Key:       input.Key,
+		}}}, nil
+	}
+	return nil, fmt.Errorf("unsupported transactional statement: %v", SQL)
+}
@@ -0,0 +1,344 @@
+package dyndb
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
+	"github.com/viant/dsc"
+	"time"
+)
+
+const (
+	//StreamInsert new item was added to the table
+	StreamInsert = dynamodbstreams.OperationTypeInsert
+	//StreamModify existing item attributes were updated
+	StreamModify = dynamodbstreams.OperationTypeModify
+	//StreamRemove item was deleted from the table
+	StreamRemove = dynamodbstreams.OperationTypeRemove
+
+	defaultStreamBatchSize        = 1000
+	defaultStreamPollInterval     = time.Second
+	defaultStreamDescribeInterval = time.Minute
+)
+
+//StreamRecord represents a single table change
+type StreamRecord struct {
+	EventID        string
+	EventName      string //INSERT, MODIFY or REMOVE
+	StreamARN      string
+	ShardID        string
+	SequenceNumber string
+	CreatedAt      *time.Time
+	Keys           map[string]interface{}
+	OldImage       map[string]interface{} //set for MODIFY and REMOVE with OLD_IMAGE or NEW_AND_OLD_IMAGES stream view
+	NewImage       map[string]interface{} //set for INSERT and MODIFY with NEW_IMAGE or NEW_AND_OLD_IMAGES stream view
+}
+
+//StreamReader reads table changes from DynamoDB stream, shards are read after their parents so that changes of an item are delivered in order
+type StreamReader struct {
+	provider  *connectionProvider
+	client    dynamodbstreamsiface.DynamoDBStreamsAPI
+	table     string
+	store     CheckpointStore
+	streamARN string
+	//BatchSize max number of records fetched with a single GetRecords call
+	BatchSize int64
+	//PollInterval wait time after a read round without new records
+	PollInterval time.Duration
+	//DescribeInterval max time between DescribeStream calls looking for new shards, the stream is also described after a shard is closed
+	DescribeInterval time.Duration
+	//Latest starts reading shards without checkpoint from the latest record instead of the oldest available one
+	Latest bool
+}
+
+//Describe returns table stream description with all shards
+func (r *StreamReader) Describe() (*dynamodbstreams.StreamDescription, error) {
+	return r.describe(context.Background())
+}
+
+func (r *StreamReader) describe(ctx context.Context) (*dynamodbstreams.StreamDescription, error) {
+	streamARN, err := r.getStreamARN(ctx)
+	if err != nil {
+		return nil, err
+	}
+	input := &dynamodbstreams.DescribeStreamInput{StreamArn: aws.String(streamARN)}
+	var result *dynamodbstreams.StreamDescription
+	for {
+		output, err := r.client.DescribeStreamWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = output.StreamDescription
+		} else {
+			result.Shards = append(result.Shards, output.StreamDescription.Shards...)
+		}
+		if output.StreamDescription.LastEvaluatedShardId == nil {
+			result.LastEvaluatedShardId = nil
+			return result, nil
+		}
+		input.ExclusiveStartShardId = output.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+//Read reads table changes till handler stops reading, returns an error or context is done,
+//the stream is described again only after a shard is closed or DescribeInterval elapses
+func (r *StreamReader) Read(ctx context.Context, handler func(record *StreamRecord) (toContinue bool, err error)) error {
+	streamARN, err := r.getStreamARN(ctx)
+	if err != nil {
+		return err
+	}
+	finished := make(map[string]bool)
+	iterators := make(map[string]*string)
+	var shards []*dynamodbstreams.Shard
+	var described time.Time
+	refresh := true
+	for {
+		if refresh || time.Since(described) >= r.DescribeInterval {
+			description, err := r.describe(ctx)
+			if err != nil {
+				return err
+			}
+			shards, described, refresh = description.Shards, time.Now(), false
+			if err = r.loadFinished(shards, finished); err != nil {
+				return err
+			}
+		}
+		idle := true
+		for _, shard := range readableShards(shards, finished) {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+			shardID := aws.StringValue(shard.ShardId)
+			iterator, ok := iterators[shardID]
+			if !ok {
+				if iterator, err = r.shardIterator(ctx, streamARN, shardID); err != nil {
+					return err
+				}
+			}
+			output, err := r.client.GetRecordsWithContext(ctx, &dynamodbstreams.GetRecordsInput{
+				ShardIterator: iterator,
+				Limit:         aws.Int64(r.BatchSize),
+			})
+			if err != nil {
+				if isAWSError(err, dynamodbstreams.ErrCodeExpiredIteratorException) {
+					delete(iterators, shardID)
+					idle = false
+					continue
+				}
+				return err
+			}
+			if len(output.Records) > 0 {
+				idle = false
+			}
+			toContinue, err := r.handleRecords(streamARN, shardID, output.Records, handler)
+			if err != nil || !toContinue {
+				return err
+			}
+			if output.NextShardIterator == nil {
+				finished[shardID] = true
+				delete(iterators, shardID)
+				idle, refresh = false, true
+				if err = r.store.Put(streamARN, shardID, shardEnd); err != nil {
+					return err
+				}
+				continue
+			}
+			iterators[shardID] = output.NextShardIterator
+		}
+		if !idle {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.PollInterval):
+		}
+	}
+}
+
+//handleRecords passes records to handler and checkpoints the last handled one
+func (r *StreamReader) handleRecords(streamARN, shardID string, records []*dynamodbstreams.Record, handler func(record *StreamRecord) (toContinue bool, err error)) (bool, error) {
+	var checkpoint string
+	var toContinue = true
+	var err error
+	for _, record := range records {
+		var streamRecord *StreamRecord
+		if streamRecord, err = newStreamRecord(streamARN, shardID, record); err != nil {
+			break
+		}
+		if toContinue, err = handler(streamRecord); err != nil {
+			break
+		}
+		checkpoint = streamRecord.SequenceNumber
+		if !toContinue {
+			break
+		}
+	}
+	if checkpoint != "" {
+		if putErr := r.store.Put(streamARN, shardID, checkpoint); putErr != nil && err == nil {
+			err = putErr
+		}
+	}
+	return toContinue && err == nil, err
+}
+
+//shardIterator returns shard iterator after checkpoint, or at trim horizon/latest record if shard has no checkpoint or checkpoint was trimmed
+func (r *StreamReader) shardIterator(ctx context.Context, streamARN, shardID string) (*string, error) {
+	checkpoint, err := r.store.Get(streamARN, shardID)
+	if err != nil {
+		return nil, err
+	}
+	input := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(streamARN),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: aws.String(dynamodbstreams.ShardIteratorTypeTrimHorizon),
+	}
+	if checkpoint != "" {
+		input.ShardIteratorType = aws.String(dynamodbstreams.ShardIteratorTypeAfterSequenceNumber)
+		input.SequenceNumber = aws.String(checkpoint)
+	} else if r.Latest {
+		input.ShardIteratorType = aws.String(dynamodbstreams.ShardIteratorTypeLatest)
+	}
+	output, err := r.client.GetShardIteratorWithContext(ctx, input)
+	if err != nil && checkpoint != "" && isAWSError(err, dynamodbstreams.ErrCodeTrimmedDataAccessException) {
+		input.ShardIteratorType = aws.String(dynamodbstreams.ShardIteratorTypeTrimHorizon)
+		input.SequenceNumber = nil
+		output, err = r.client.GetShardIteratorWithContext(ctx, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return output.ShardIterator, nil
+}
+
+//loadFinished marks shards with shard end checkpoint as finished
+func (r *StreamReader) loadFinished(shards []*dynamodbstreams.Shard, finished map[string]bool) error {
+	for _, shard := range shards {
+		shardID := aws.StringValue(shard.ShardId)
+		if finished[shardID] {
+			continue
+		}
+		checkpoint, err := r.store.Get(r.streamARN, shardID)
+		if err != nil {
+			return err
+		}
+		if checkpoint == shardEnd {
+			finished[shardID] = true
+		}
+	}
+	return nil
+}
+
+func (r *StreamReader) getStreamARN(ctx context.Context) (string, error) {
+	if r.streamARN != "" {
+		return r.streamARN, nil
+	}
+	connection, err := r.provider.Get()
+	if err != nil {
+		return "", err
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if err != nil {
+		return "", err
+	}
+	output, err := db.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(r.table)})
+	if err != nil {
+		return "", err
+	}
+	if output.Table.LatestStreamArn == nil || output.Table.StreamSpecification == nil || !aws.BoolValue(output.Table.StreamSpecification.StreamEnabled) {
+		return "", fmt.Errorf("stream is not enabled on table: %v", r.table)
+	}
+	r.streamARN = *output.Table.LatestStreamArn
+	return r.streamARN, nil
+}
+
+//readableShards returns unfinished shards whose parent has been finished or is no longer part of the stream
+func readableShards(shards []*dynamodbstreams.Shard, finished map[string]bool) []*dynamodbstreams.Shard {
+	var present = make(map[string]bool)
+	for _, shard := range shards {
+		present[aws.StringValue(shard.ShardId)] = true
+	}
+	var result = make([]*dynamodbstreams.Shard, 0)
+	for _, shard := range shards {
+		if finished[aws.StringValue(shard.ShardId)] {
+			continue
+		}
+		if parentID := aws.StringValue(shard.ParentShardId); parentID != "" && present[parentID] && !finished[parentID] {
+			continue
+		}
+		result = append(result, shard)
+	}
+	return result
+}
+
+func newStreamRecord(streamARN, shardID string, record *dynamodbstreams.Record) (*StreamRecord, error) {
+	result := &StreamRecord{
+		EventID:   aws.StringValue(record.EventID),
+		EventName: aws.StringValue(record.EventName),
+		StreamARN: streamARN,
+		ShardID:   shardID,
+	}
+	change := record.Dynamodb
+	if change == nil {
+		return result, nil
+	}
+	result.SequenceNumber = aws.StringValue(change.SequenceNumber)
+	result.CreatedAt = change.ApproximateCreationDateTime
+	var err error
+	if result.Keys, err = unmarshalImage(change.Keys); err != nil {
+		return nil, err
+	}
+	if result.OldImage, err = unmarshalImage(change.OldImage); err != nil {
+		return nil, err
+	}
+	if result.NewImage, err = unmarshalImage(change.NewImage); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func unmarshalImage(image map[string]*dynamodb.AttributeValue) (map[string]interface{}, error) {
+	if len(image) == 0 {
+		return nil, nil
+	}
+	var result = make(map[string]interface{})
+	err := unmarshalMap(image, &result)
+	return result, err
+}
+
+func isAWSError(err error, code string) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == code
+	}
+	return false
+}
+
+//NewStreamReader returns table stream reader for supplied dyndb manager, if store is nil progress is kept in memory
+func NewStreamReader(manager dsc.Manager, table string, store CheckpointStore) (*StreamReader, error) {
+	provider, ok := manager.ConnectionProvider().(*connectionProvider)
+	if !ok {
+		return nil, fmt.Errorf("unsupported connection provider: %T", manager.ConnectionProvider())
+	}
+	client, err := provider.newStreamsClient()
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		store = NewMemoryCheckpointStore()
+	}
+	return &StreamReader{
+		provider:         provider,
+		client:           client,
+		table:            tableName(manager.Config(), table),
+		store:            store,
+		BatchSize:        defaultStreamBatchSize,
+		PollInterval:     defaultStreamPollInterval,
+		DescribeInterval: defaultStreamDescribeInterval,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestReadableShards(t *testing.T) {
+	shard := func(id, parent string) *dynamodbstreams.Shard {
+		result := &dynamodbstreams.Shard{ShardId: aws.String(id)}
+		if parent != "" {
+			result.ParentShardId = aws.String(parent)
+		}
+		return result
+	}
+	//shard 1 was split into 2 and 3, shard 0 was trimmed from the stream
+	shards := []*dynamodbstreams.Shard{
+		shard("1", "0"),
+		shard("2", "1"),
+		shard("3", "1"),
+		shard("4", "2"),
+	}
+	var useCases = []struct {
+		description string
+		finished    map[string]bool
+		expect      []string
+	}{
+		{
+			description: "parent first",
+			finished:    map[string]bool{},
+			expect:      []string{"1"},
+		},
+		{
+			description: "split children after parent",
+			finished:    map[string]bool{"1": true},
+			expect:      []string{"2", "3"},
+		},
+		{
+			description: "grandchild after child",
+			finished:    map[string]bool{"1": true, "2": true},
+			expect:      []string{"3", "4"},
+		},
+		{
+			description: "all finished",
+			finished:    map[string]bool{"1": true, "2": true, "3": true, "4": true},
+			expect:      []string{},
+		},
+	}
+	for _, useCase := range useCases {
+		var actual = make([]string, 0)
+		for _, shard := range readableShards(shards, useCase.finished) {
+			actual = append(actual, *shard.ShardId)
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+//fakeStreams serves shard 1 with a record per GetRecords call till it closes and its child shard 2 afterwards
+type fakeStreams struct {
+	dynamodbstreamsiface.DynamoDBStreamsAPI
+	records   map[string][]string //records by shard iterator, iterator is shard id followed by position
+	described int
+}
+
+func (f *fakeStreams) DescribeStreamWithContext(ctx aws.Context, input *dynamodbstreams.DescribeStreamInput, options ...request.Option) (*dynamodbstreams.DescribeStreamOutput, error) {
+	f.described++
+	return &dynamodbstreams.DescribeStreamOutput{StreamDescription: &dynamodbstreams.StreamDescription{Shards: []*dynamodbstreams.Shard{
+		{ShardId: aws.String("1")},
+		{ShardId: aws.String("2"), ParentShardId: aws.String("1")},
+	}}}, nil
+}
+
+func (f *fakeStreams) GetShardIteratorWithContext(ctx aws.Context, input *dynamodbstreams.GetShardIteratorInput, options ...request.Option) (*dynamodbstreams.GetShardIteratorOutput, error) {
+	return &dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String(*input.ShardId + "0")}, nil
+}
+
+func (f *fakeStreams) GetRecordsWithContext(ctx aws.Context, input *dynamodbstreams.GetRecordsInput, options ...request.Option) (*dynamodbstreams.GetRecordsOutput, error) {
+	iterator := *input.ShardIterator
+	sequences := f.records[iterator[:1]]
+	position := int(iterator[1] - '0')
+	result := &dynamodbstreams.GetRecordsOutput{}
+	if position < len(sequences) {
+		result.Records = []*dynamodbstreams.Record{{EventName: aws.String(StreamInsert), Dynamodb: &dynamodbstreams.StreamRecord{SequenceNumber: aws.String(sequences[position])}}}
+	}
+	if position+1 < len(sequences) || iterator[:1] != "1" {
+		result.NextShardIterator = aws.String(iterator[:1] + string(rune('0'+position+1)))
+	}
+	return result, nil
+}
+
+func TestStreamReader_Read(t *testing.T) {
+	client := &fakeStreams{records: map[string][]string{"1": {"101", "102", "103"}, "2": {"201"}}}
+	reader := &StreamReader{
+		client:           client,
+		streamARN:        "arn",
+		store:            NewMemoryCheckpointStore(),
+		BatchSize:        defaultStreamBatchSize,
+		PollInterval:     time.Millisecond,
+		DescribeInterval: time.Hour,
+	}
+	var sequences []string
+	err := reader.Read(context.Background(), func(record *StreamRecord) (bool, error) {
+		sequences = append(sequences, record.SequenceNumber)
+		return record.ShardID != "2", nil
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"101", "102", "103", "201"}, sequences)
+	//initial description and description after shard 1 was closed
+	assert.EqualValues(t, 2, client.described)
+}
+
+func TestNewStreamRecord(t *testing.T) {
+	record, err := newStreamRecord("arn", "shard-1", &dynamodbstreams.Record{
+		EventID:   aws.String("1"),
+		EventName: aws.String(StreamModify),
+		Dynamodb: &dynamodbstreams.StreamRecord{
+			SequenceNumber: aws.String("100000000000000000001"),
+			Keys: map[string]*dynamodb.AttributeValue{
+				"Artist": {S: aws.String("Madonna")},
+			},
+			OldImage: map[string]*dynamodb.AttributeValue{
+				"Artist": {S: aws.String("Madonna")},
+				"Year":   {N: aws.String("1984")},
+			},
+			NewImage: map[string]*dynamodb.AttributeValue{
+				"Artist": {S: aws.String("Madonna")},
+				"Year":   {N: aws.String("1985")},
+				"Tags":   {L: []*dynamodb.AttributeValue{{S: aws.String("pop")}}},
+			},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, StreamModify, record.EventName)
+	assert.EqualValues(t, "shard-1", record.ShardID)
+	assert.EqualValues(t, "100000000000000000001", record.SequenceNumber)
+	assert.EqualValues(t, map[string]interface{}{"Artist": "Madonna"}, record.Keys)
+	assert.EqualValues(t, 1984, record.OldImage["Year"])
+	assert.EqualValues(t, 1985, record.NewImage["Year"])
+	assert.EqualValues(t, []interface{}{"pop"}, record.NewImage["Tags"])
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dyndb")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "checkpoint.json")
+	store, err := NewFileCheckpointStore(filename)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, store.Put("arn", "shard-1", "101"))
+	assert.Nil(t, store.Put("arn", "shard-0", shardEnd))
+
+	store, err = NewFileCheckpointStore(filename)
+	if !assert.Nil(t, err) {
+		return
+	}
+	checkpoint, err := store.Get("arn", "shard-1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "101", checkpoint)
+	checkpoint, _ = store.Get("arn", "shard-0")
+	assert.EqualValues(t, shardEnd, checkpoint)
+	checkpoint, _ = store.Get("arn", "shard-2")
+	assert.EqualValues(t, "", checkpoint)
+}
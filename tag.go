@@ -0,0 +1,183 @@
+package dyndb
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"sort"
+	"strings"
+)
+
+const (
+	//tableTagsKey config parameter, tags applied to tables created with CREATE TABLE or dialect, map or key=value pairs separated with ; or ,
+	tableTagsKey = "tableTags"
+	//tagsOption CREATE TABLE option i.e. CREATE TABLE music(...) WITH (tags = 'team=payments,env=prod')
+	tagsOption = "tags"
+	//tagsKeyword alter table option i.e. ALTER TABLE music SET TAGS 'team=payments' or ALTER TABLE music UNSET TAGS 'team'
+	tagsKeyword = "TAGS"
+)
+
+//TableTagsDialect represents dialect listing table tags, dsc.GetDatastoreDialect("dyndb") implements it
+type TableTagsDialect interface {
+	//GetTableTags returns table tags
+	GetTableTags(manager dsc.Manager, datastore, table string) (map[string]string, error)
+}
+
+func isTagSeparator(r rune) bool {
+	return r == ',' || r == ';'
+}
+
+//parseTags returns tags sorted by key for map or key=value pairs separated with , or ;
+func parseTags(value interface{}) ([]*dynamodb.Tag, error) {
+	var tags = make(map[string]string)
+	if toolbox.IsMap(value) {
+		for k, v := range toolbox.AsMap(value) {
+			tags[k] = toolbox.AsString(v)
+		}
+	} else {
+		for _, pair := range splitQuoted(toolbox.AsString(value), isTagSeparator) {
+			index := strings.Index(pair, "=")
+			if index == -1 {
+				return nil, fmt.Errorf("invalid tag: %v, expected key=value", pair)
+			}
+			tags[unquote(strings.TrimSpace(pair[:index]))] = unquote(strings.TrimSpace(pair[index+1:]))
+		}
+	}
+	var result = make([]*dynamodb.Tag, 0, len(tags))
+	for key, value := range tags {
+		if key == "" {
+			return nil, fmt.Errorf("tag key was empty")
+		}
+		result = append(result, &dynamodb.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return *result[i].Key < *result[j].Key
+	})
+	return result, nil
+}
+
+//tableTags returns config table tags overridden by supplied tags, nil if there are no tags
+func tableTags(config *dsc.Config, tags []*dynamodb.Tag) ([]*dynamodb.Tag, error) {
+	var merged = make(map[string]interface{})
+	if config.Has(tableTagsKey) {
+		defaults, err := parseTags(config.Parameters[tableTagsKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", tableTagsKey, err)
+		}
+		tags = append(defaults, tags...)
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	for _, tag := range tags {
+		merged[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return parseTags(merged)
+}
+
+//createTableOptions removes WITH (name = 'value', ...) clause following CREATE TABLE columns, it returns options by lower case name
+func createTableOptions(SQL string) (string, map[string]string, error) {
+	var options = make(map[string]string)
+	var depth int
+	var quote rune
+	for i, r := range SQL {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			continue
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth--; depth != 0 {
+				continue
+			}
+			clause := strings.TrimSuffix(strings.TrimSpace(SQL[i+1:]), ";")
+			if clause == "" {
+				return SQL, options, nil
+			}
+			if !hasKeywordPrefix(clause, "WITH") {
+				return "", nil, fmt.Errorf("unsupported create table clause: %v", clause)
+			}
+			clause = strings.TrimSpace(clause[len("WITH"):])
+			if !strings.HasPrefix(clause, "(") || !strings.HasSuffix(clause, ")") {
+				return "", nil, fmt.Errorf("invalid create table options: %v, expected WITH (name = 'value')", clause)
+			}
+			for _, option := range splitQuoted(clause[1:len(clause)-1], func(r rune) bool { return r == ',' }) {
+				index := strings.Index(option, "=")
+				if index == -1 {
+					return "", nil, fmt.Errorf("invalid create table option: %v, expected name = 'value'", option)
+				}
+				options[strings.ToLower(strings.TrimSpace(option[:index]))] = unquote(strings.TrimSpace(option[index+1:]))
+			}
+			return SQL[:i+1], options, nil
+		}
+	}
+	return SQL, options, nil
+}
+
+//alterTags adds or replaces (SET) tags with TagResource or removes (UNSET) tag keys with UntagResource
+func (m *manager) alterTags(ctx context.Context, db dynamodbiface.DynamoDBAPI, table, operation, value string) error {
+	description, err := m.describe(db, table)
+	if err != nil {
+		return err
+	}
+	switch strings.ToUpper(operation) {
+	case "SET":
+		tags, err := parseTags(value)
+		if err != nil {
+			return err
+		}
+		_, err = db.TagResourceWithContext(ctx, &dynamodb.TagResourceInput{ResourceArn: description.TableArn, Tags: tags})
+		return err
+	case "UNSET":
+		var keys = make([]*string, 0)
+		for _, key := range splitQuoted(value, isTagSeparator) {
+			keys = append(keys, aws.String(unquote(key)))
+		}
+		_, err = db.UntagResourceWithContext(ctx, &dynamodb.UntagResourceInput{ResourceArn: description.TableArn, TagKeys: keys})
+		return err
+	}
+	return fmt.Errorf("unsupported tags operation: %v, expected SET or UNSET", operation)
+}
+
+//GetTableTags returns table tags
+func (d *dialect) GetTableTags(manager dsc.Manager, datastore, table string) (map[string]string, error) {
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if err != nil {
+		return nil, err
+	}
+	db = primaryClient(db)
+	table = tableName(manager.Config(), table)
+	description, err := describeCached(manager, db, table)
+	if err != nil {
+		return nil, err
+	}
+	var result = make(map[string]string)
+	input := &dynamodb.ListTagsOfResourceInput{ResourceArn: description.TableArn}
+	for {
+		output, err := db.ListTagsOfResource(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %v, %v", table, err)
+		}
+		for _, tag := range output.Tags {
+			result[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if output.NextToken == nil {
+			return result, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
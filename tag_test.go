@@ -0,0 +1,237 @@
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	var useCases = []struct {
+		description string
+		value       interface{}
+		expect      map[string]string
+		hasError    bool
+	}{
+		{
+			description: "comma separated pairs",
+			value:       "team=payments,env=prod",
+			expect:      map[string]string{"team": "payments", "env": "prod"},
+		},
+		{
+			description: "semicolon separated pairs with quoted value",
+			value:       "team = payments; owner = 'Jane Doe, Jr'",
+			expect:      map[string]string{"team": "payments", "owner": "Jane Doe, Jr"},
+		},
+		{
+			description: "map",
+			value:       map[string]interface{}{"team": "payments"},
+			expect:      map[string]string{"team": "payments"},
+		},
+		{
+			description: "missing value",
+			value:       "team",
+			hasError:    true,
+		},
+		{
+			description: "empty key",
+			value:       "=payments",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		tags, err := parseTags(useCase.value)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		var actual = make(map[string]string)
+		for _, tag := range tags {
+			actual[*tag.Key] = *tag.Value
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestCreateTableOptions(t *testing.T) {
+	var useCases = []struct {
+		description string
+		SQL         string
+		expectSQL   string
+		expect      map[string]string
+		hasError    bool
+	}{
+		{
+			description: "without options",
+			SQL:         "CREATE TABLE music(Artist VARCHAR(255) HASH KEY)",
+			expectSQL:   "CREATE TABLE music(Artist VARCHAR(255) HASH KEY)",
+			expect:      map[string]string{},
+		},
+		{
+			description: "tags option",
+			SQL:         "CREATE TABLE music(Artist VARCHAR(255) HASH KEY) WITH (TAGS = 'team=payments,env=prod');",
+			expectSQL:   "CREATE TABLE music(Artist VARCHAR(255) HASH KEY)",
+			expect:      map[string]string{"tags": "team=payments,env=prod"},
+		},
+		{
+			description: "unsupported clause",
+			SQL:         "CREATE TABLE music(Artist VARCHAR(255) HASH KEY) PARTITION BY Artist",
+			hasError:    true,
+		},
+		{
+			description: "invalid option",
+			SQL:         "CREATE TABLE music(Artist VARCHAR(255) HASH KEY) WITH (tags)",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		SQL, options, err := createTableOptions(useCase.SQL)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, useCase.expectSQL, SQL, useCase.description)
+			assert.EqualValues(t, useCase.expect, options, useCase.description)
+		}
+	}
+}
+
+func TestManager_Tags(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint":    "memory://tags",
+		"region":      "us-west-1",
+		"tablePrefix": "dev_",
+		"tableTags":   "env=dev;costCenter=42",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	dialect := dsc.GetDatastoreDialect("dyndb")
+	tagsDialect, ok := dialect.(TableTagsDialect)
+	if !assert.True(t, ok) {
+		return
+	}
+	for _, table := range []string{"users", "events"} {
+		_, err = manager.Execute("DROP TABLE IF EXISTS " + table)
+		assert.Nil(t, err, table)
+	}
+	var useCases = []struct {
+		description string
+		SQL         string
+		expect      map[string]string
+		hasError    bool
+	}{
+		{
+			description: "create with tags",
+			SQL:         "CREATE TABLE users(Id INT HASH KEY) WITH (tags = 'team=payments,env=prod')",
+			expect:      map[string]string{"team": "payments", "env": "prod", "costCenter": "42"},
+		},
+		{
+			description: "set tags",
+			SQL:         "ALTER TABLE users SET TAGS 'owner=Jane Doe,team=billing'",
+			expect:      map[string]string{"team": "billing", "env": "prod", "costCenter": "42", "owner": "Jane Doe"},
+		},
+		{
+			description: "unset tags",
+			SQL:         "ALTER TABLE users UNSET TAGS 'owner,costCenter'",
+			expect:      map[string]string{"team": "billing", "env": "prod"},
+		},
+		{
+			description: "unsupported create option",
+			SQL:         "CREATE TABLE events(Id INT HASH KEY) WITH (ttl = 'Expiry')",
+			hasError:    true,
+		},
+		{
+			description: "invalid tags",
+			SQL:         "ALTER TABLE users SET TAGS 'owner'",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		_, err := manager.Execute(useCase.SQL)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		tags, err := tagsDialect.GetTableTags(manager, "", "users")
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, useCase.expect, tags, useCase.description)
+		}
+	}
+
+	err = dialect.CreateTable(manager, "", "events", map[string]interface{}{
+		"AttributeDefinitions": []interface{}{map[string]interface{}{"AttributeName": "Id", "AttributeType": "N"}},
+		"KeySchema":            []interface{}{map[string]interface{}{"AttributeName": "Id", "KeyType": "HASH"}},
+		"BillingMode":          dynamodb.BillingModePayPerRequest,
+		"tags":                 map[string]interface{}{"team": "analytics"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	tags, err := tagsDialect.GetTableTags(manager, "", "events")
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, map[string]string{"team": "analytics", "env": "dev", "costCenter": "42"}, tags)
+	}
+	_, err = tagsDialect.GetTableTags(manager, "", "missing")
+	assert.NotNil(t, err)
+
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db, _ := asDatabase(connection)
+	_, err = db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String("dev_events")})
+	assert.Nil(t, err)
+}
+
+func TestDialect_GetTableTags(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://cachedTags",
+		"region":   "us-west-1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	client := &describeCounter{}
+	manager, err := NewManagerFactory(func(sess *session.Session, awsConfig *aws.Config) (dynamodbiface.DynamoDBAPI, error) {
+		client.DynamoDBAPI = dynamodb.New(sess, awsConfig)
+		return client, nil
+	}).Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS tagged", "CREATE TABLE tagged(Id INT HASH KEY) WITH (tags = 'team=payments')"} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	tagsDialect := dsc.GetDatastoreDialect("dyndb").(TableTagsDialect)
+	client.count = 0
+	for i := 0; i < 3; i++ {
+		tags, err := tagsDialect.GetTableTags(manager, "", "tagged")
+		if assert.Nil(t, err) {
+			assert.EqualValues(t, map[string]string{"team": "payments"}, tags)
+		}
+	}
+	assert.EqualValues(t, 1, client.count)
+	_, err = manager.Execute("ALTER TABLE tagged SET TAGS 'env=prod'")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, client.count)
+}
@@ -0,0 +1,471 @@
+package dyndb
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	//JSONFormat new line delimited JSON, one item per line
+	JSONFormat = "json"
+	//CSVFormat CSV with header
+	CSVFormat = "csv"
+
+	//PlainEncoding represents attributes as plain JSON values, sets are written as lists
+	PlainEncoding = "plain"
+	//DynamoDBEncoding represents attributes as DynamoDB JSON i.e. {"Year":{"N":"1984"}}
+	DynamoDBEncoding = "dynamodb"
+
+	//maxBatchWriteItems max number of items supported by BatchWriteItem
+	maxBatchWriteItems   = 25
+	maxBatchWriteRetries = 10
+	progressFileSuffix   = ".progress"
+)
+
+//ExportOptions represents table export options
+type ExportOptions struct {
+	Format     string   //json (default) or csv
+	Encoding   string   //plain (default) or dynamodb
+	Columns    []string //CSV columns, by default the header is inferred from the first sampleSize items, export fails if a later item has other attributes
+	Where      string   //optional criteria i.e. Artist = ? AND Year > 2000
+	Parameters []interface{}
+}
+
+//ImportOptions represents table import options
+type ImportOptions struct {
+	Format   string //json (default) or csv
+	Encoding string //plain (default) or dynamodb
+	Offset   int    //number of records already imported, skipped on resume
+	//Progress is called after each batch is written with number of processed records including offset
+	Progress func(processed int) error
+}
+
+func (o *ExportOptions) init() error {
+	var err error
+	if o.Format, o.Encoding, err = transferFormat(o.Format, o.Encoding); err != nil {
+		return err
+	}
+	if where := strings.TrimSpace(o.Where); hasKeywordPrefix(where, "WHERE") {
+		o.Where = where[len("WHERE"):]
+	}
+	return nil
+}
+
+func (o *ImportOptions) init() error {
+	var err error
+	o.Format, o.Encoding, err = transferFormat(o.Format, o.Encoding)
+	return err
+}
+
+func transferFormat(format, encoding string) (string, string, error) {
+	format, encoding = strings.ToLower(format), strings.ToLower(encoding)
+	if format == "" {
+		format = JSONFormat
+	}
+	if encoding == "" {
+		encoding = PlainEncoding
+	}
+	if format != JSONFormat && format != CSVFormat {
+		return "", "", fmt.Errorf("unsupported format: %v", format)
+	}
+	if encoding != PlainEncoding && encoding != DynamoDBEncoding {
+		return "", "", fmt.Errorf("unsupported encoding: %v", encoding)
+	}
+	return format, encoding, nil
+}
+
+//Export writes table items matching optional criteria to writer, it returns number of exported items
+func Export(manager dsc.Manager, table string, writer io.Writer, options *ExportOptions) (int, error) {
+	if options == nil {
+		options = &ExportOptions{}
+	}
+	if err := options.init(); err != nil {
+		return 0, err
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return 0, err
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if err != nil {
+		return 0, err
+	}
+	input, err := exportInput(tableName(manager.Config(), table), options)
+	if err != nil {
+		return 0, err
+	}
+	if options.Format == CSVFormat {
+		return exportCSV(db, input, writer, options, configSampleSize(manager.Config()))
+	}
+	count := 0
+	encoder := json.NewEncoder(writer)
+	err = scanItems(db, input, func(item map[string]*dynamodb.AttributeValue) error {
+		count++
+		if options.Encoding == DynamoDBEncoding {
+			return encoder.Encode(encodeItem(item))
+		}
+		return encoder.Encode(plainItem(item))
+	})
+	return count, err
+}
+
+//ExportFile writes table items to a file, format is inferred from .csv extension unless specified
+func ExportFile(manager dsc.Manager, table string, filename string, options *ExportOptions) (int, error) {
+	if options == nil {
+		options = &ExportOptions{}
+	}
+	if options.Format == "" && strings.EqualFold(path.Ext(filename), ".csv") {
+		options.Format = CSVFormat
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return 0, err
+	}
+	count, err := Export(manager, table, file, options)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return count, err
+}
+
+func exportInput(table string, options *ExportOptions) (*dynamodb.ScanInput, error) {
+	input := &dynamodb.ScanInput{TableName: aws.String(table)}
+	if strings.TrimSpace(options.Where) == "" {
+		return input, nil
+	}
+	criteria, err := parseCriteria(options.Where)
+	if err != nil {
+		return nil, err
+	}
+	builder := newExpressionBuilder(options.Parameters)
+	condition, err := builder.condition(criteria)
+	if err != nil {
+		return nil, err
+	}
+	input.FilterExpression = aws.String(condition)
+	input.ExpressionAttributeNames = builder.attributeNames()
+	input.ExpressionAttributeValues, err = builder.attributeValues()
+	return input, err
+}
+
+//exportCSV writes CSV items, if columns are not specified the header is inferred from up to sampleSize first items which are buffered
+func exportCSV(db dynamodbiface.DynamoDBAPI, input *dynamodb.ScanInput, writer io.Writer, options *ExportOptions, sampleSize int) (int, error) {
+	csvWriter := csv.NewWriter(writer)
+	columns := options.Columns
+	var header map[string]bool
+	var sample = make([]map[string]*dynamodb.AttributeValue, 0)
+	count := 0
+	writeItem := func(item map[string]*dynamodb.AttributeValue) error {
+		if header != nil {
+			for key := range item {
+				if !header[key] {
+					return fmt.Errorf("attribute %v is not in CSV header inferred from %v sampled items, specify export columns", key, len(sample))
+				}
+			}
+		}
+		var record = make([]string, len(columns))
+		for i, column := range columns {
+			value, ok := item[column]
+			if !ok {
+				continue
+			}
+			cell, err := csvCell(value, options.Encoding)
+			if err != nil {
+				return err
+			}
+			record[i] = cell
+		}
+		count++
+		return csvWriter.Write(record)
+	}
+	writeHeader := func() error {
+		if len(options.Columns) == 0 {
+			columns = itemColumns(sample)
+			header = make(map[string]bool)
+			for _, column := range columns {
+				header[column] = true
+			}
+		}
+		if err := csvWriter.Write(columns); err != nil {
+			return err
+		}
+		for _, item := range sample {
+			if err := writeItem(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(columns) > 0 {
+		if err := writeHeader(); err != nil {
+			return 0, err
+		}
+	}
+	err := scanItems(db, input, func(item map[string]*dynamodb.AttributeValue) error {
+		if len(columns) > 0 {
+			return writeItem(item)
+		}
+		if sample = append(sample, item); len(sample) < sampleSize {
+			return nil
+		}
+		return writeHeader()
+	})
+	if err == nil && len(columns) == 0 {
+		err = writeHeader()
+	}
+	csvWriter.Flush()
+	if err != nil {
+		return count, err
+	}
+	return count, csvWriter.Error()
+}
+
+//itemColumns returns sorted union of item attribute names
+func itemColumns(items []map[string]*dynamodb.AttributeValue) []string {
+	var unique = make(map[string]bool)
+	var result = make([]string, 0)
+	for _, item := range items {
+		for key := range item {
+			if !unique[key] {
+				unique[key] = true
+				result = append(result, key)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func csvCell(value *dynamodb.AttributeValue, encoding string) (string, error) {
+	if encoding == DynamoDBEncoding {
+		data, err := json.Marshal(encodeAttribute(value))
+		return string(data), err
+	}
+	switch plain := plainAttribute(value).(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return encodePlainString(plain)
+	case json.Number:
+		return plain.String(), nil
+	case bool:
+		return toolbox.AsString(plain), nil
+	default:
+		data, err := json.Marshal(plain)
+		return string(data), err
+	}
+}
+
+//scanItems scans all table pages passing each item to handler
+func scanItems(db dynamodbiface.DynamoDBAPI, input *dynamodb.ScanInput, handler func(item map[string]*dynamodb.AttributeValue) error) error {
+	var handlerErr error
+	err := db.ScanPagesWithContext(context.Background(), input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range output.Items {
+			if handlerErr = handler(item); handlerErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if handlerErr != nil {
+		return handlerErr
+	}
+	return err
+}
+
+//Import writes records read from reader to the table with BatchWriteItem, it returns number of processed records including offset
+func Import(manager dsc.Manager, table string, reader io.Reader, options *ImportOptions) (int, error) {
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	if err := options.init(); err != nil {
+		return 0, err
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return 0, err
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if err != nil {
+		return 0, err
+	}
+	table = tableName(manager.Config(), table)
+	next := jsonItemReader(reader, options.Encoding)
+	if options.Format == CSVFormat {
+		if next, err = csvItemReader(reader, options.Encoding); err != nil {
+			return 0, err
+		}
+	}
+	processed := 0
+	var batch = make([]*dynamodb.WriteRequest, 0, maxBatchWriteItems)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := batchWriteItems(context.Background(), db, table, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		if options.Progress != nil {
+			return options.Progress(processed)
+		}
+		return nil
+	}
+	for {
+		item, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return processed, fmt.Errorf("failed to read record %v: %v", processed+1, err)
+		}
+		processed++
+		if processed <= options.Offset {
+			continue
+		}
+		batch = append(batch, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}})
+		if len(batch) == maxBatchWriteItems {
+			if err = flush(); err != nil {
+				return processed - len(batch), err
+			}
+		}
+	}
+	if err = flush(); err != nil {
+		return processed - len(batch), err
+	}
+	return processed, nil
+}
+
+//ImportFile imports a file, progress is kept in <filename>.progress so that interrupted import resumes after the last written batch
+func ImportFile(manager dsc.Manager, table string, filename string, options *ImportOptions) (int, error) {
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	if options.Format == "" && strings.EqualFold(path.Ext(filename), ".csv") {
+		options.Format = CSVFormat
+	}
+	progressFile := filename + progressFileSuffix
+	if data, err := ioutil.ReadFile(progressFile); err == nil {
+		options.Offset = toolbox.AsInt(strings.TrimSpace(string(data)))
+	}
+	progress := options.Progress
+	options.Progress = func(processed int) error {
+		if err := ioutil.WriteFile(progressFile, []byte(toolbox.AsString(processed)), 0644); err != nil {
+			return err
+		}
+		if progress != nil {
+			return progress(processed)
+		}
+		return nil
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	count, err := Import(manager, table, file, options)
+	if err == nil {
+		if removeErr := os.Remove(progressFile); removeErr != nil && !os.IsNotExist(removeErr) {
+			err = removeErr
+		}
+	}
+	return count, err
+}
+
+//batchWriteItems writes requests retrying unprocessed items with backoff
+func batchWriteItems(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string, requests []*dynamodb.WriteRequest) error {
+	input := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{table: requests},
+	}
+	for i := 0; ; i++ {
+		output, err := db.BatchWriteItemWithContext(ctx, input)
+		if err != nil {
+			return err
+		}
+		if len(output.UnprocessedItems) == 0 {
+			return nil
+		}
+		if i == maxBatchWriteRetries {
+			return fmt.Errorf("failed to write %v unprocessed items to %v", len(output.UnprocessedItems[table]), table)
+		}
+		time.Sleep(time.Duration(50*(1<<uint(i))) * time.Millisecond)
+		input.RequestItems = output.UnprocessedItems
+	}
+}
+
+func jsonItemReader(reader io.Reader, encoding string) func() (map[string]*dynamodb.AttributeValue, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	return func() (map[string]*dynamodb.AttributeValue, error) {
+		if encoding == DynamoDBEncoding {
+			var item = make(map[string]json.RawMessage)
+			if err := decoder.Decode(&item); err != nil {
+				return nil, err
+			}
+			var result = make(map[string]*dynamodb.AttributeValue)
+			for key, data := range item {
+				value, err := decodeAttribute(data)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %v: %v", key, err)
+				}
+				result[key] = value
+			}
+			return result, nil
+		}
+		var record = make(map[string]interface{})
+		if err := decoder.Decode(&record); err != nil {
+			return nil, err
+		}
+		return asItem(record)
+	}
+}
+
+func csvItemReader(reader io.Reader, encoding string) (func() (map[string]*dynamodb.AttributeValue, error), error) {
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return func() (map[string]*dynamodb.AttributeValue, error) { return nil, io.EOF }, nil
+		}
+		return nil, err
+	}
+	return func() (map[string]*dynamodb.AttributeValue, error) {
+		record, err := csvReader.Read()
+		if err != nil {
+			return nil, err
+		}
+		var result = make(map[string]*dynamodb.AttributeValue)
+		for i, cell := range record {
+			if i >= len(header) || cell == "" {
+				continue
+			}
+			var value *dynamodb.AttributeValue
+			if encoding == DynamoDBEncoding {
+				value, err = decodeAttribute([]byte(cell))
+			} else {
+				value, err = decodePlainValue(cell)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v: %v", header[i], err)
+			}
+			result[header[i]] = value
+		}
+		return result, nil
+	}, nil
+}
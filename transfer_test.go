@@ -0,0 +1,220 @@
+package dyndb
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestItemReaders(t *testing.T) {
+	var item = map[string]*dynamodb.AttributeValue{
+		"Artist": {S: aws.String("Madonna")},
+		"Year":   {N: aws.String("12345678901234567890")},
+		"Active": {BOOL: aws.Bool(true)},
+		"Tags":   {L: []*dynamodb.AttributeValue{{S: aws.String("pop")}, {N: aws.String("1")}}},
+		"Label":  {M: map[string]*dynamodb.AttributeValue{"Name": {S: aws.String("Sire")}}},
+	}
+	var useCases = []struct {
+		description string
+		format      string
+		encoding    string
+	}{
+		{description: "plain JSON", format: JSONFormat, encoding: PlainEncoding},
+		{description: "DynamoDB JSON", format: JSONFormat, encoding: DynamoDBEncoding},
+		{description: "plain CSV", format: CSVFormat, encoding: PlainEncoding},
+		{description: "DynamoDB CSV", format: CSVFormat, encoding: DynamoDBEncoding},
+	}
+	for _, useCase := range useCases {
+		var text string
+		if useCase.format == JSONFormat {
+			var record interface{} = plainItem(item)
+			if useCase.encoding == DynamoDBEncoding {
+				record = encodeItem(item)
+			}
+			data, err := json.Marshal(record)
+			if !assert.Nil(t, err, useCase.description) {
+				continue
+			}
+			text = string(data) + "\n"
+		} else {
+			columns := itemColumns([]map[string]*dynamodb.AttributeValue{item})
+			assert.EqualValues(t, []string{"Active", "Artist", "Label", "Tags", "Year"}, columns)
+			var cells = make([]string, 0)
+			for _, column := range columns {
+				cell, err := csvCell(item[column], useCase.encoding)
+				assert.Nil(t, err, useCase.description)
+				cells = append(cells, `"`+strings.Replace(cell, `"`, `""`, -1)+`"`)
+			}
+			text = strings.Join(columns, ",") + "\n" + strings.Join(cells, ",") + "\n"
+		}
+		next := jsonItemReader(strings.NewReader(text), useCase.encoding)
+		if useCase.format == CSVFormat {
+			var err error
+			next, err = csvItemReader(strings.NewReader(text), useCase.encoding)
+			if !assert.Nil(t, err, useCase.description) {
+				continue
+			}
+		}
+		actual, err := next()
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, item, actual, useCase.description)
+		_, err = next()
+		assert.Equal(t, io.EOF, err, useCase.description)
+	}
+}
+
+func TestDecodePlainValue(t *testing.T) {
+	var useCases = []struct {
+		text   string
+		expect *dynamodb.AttributeValue
+	}{
+		{text: "abc", expect: &dynamodb.AttributeValue{S: aws.String("abc")}},
+		{text: "00123", expect: &dynamodb.AttributeValue{S: aws.String("00123")}},
+		{text: "1.5", expect: &dynamodb.AttributeValue{N: aws.String("1.5")}},
+		{text: "12 Main St", expect: &dynamodb.AttributeValue{S: aws.String("12 Main St")}},
+		{text: "false", expect: &dynamodb.AttributeValue{BOOL: aws.Bool(false)}},
+		{text: "null", expect: &dynamodb.AttributeValue{NULL: aws.Bool(true)}},
+		{text: `"quoted"`, expect: &dynamodb.AttributeValue{S: aws.String("quoted")}},
+		{text: `"123"`, expect: &dynamodb.AttributeValue{S: aws.String("123")}},
+	}
+	for _, useCase := range useCases {
+		actual, err := decodePlainValue(useCase.text)
+		assert.Nil(t, err, useCase.text)
+		assert.EqualValues(t, useCase.expect, actual, useCase.text)
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	var useCases = []struct {
+		description string
+		sampleSize  int
+		columns     []string
+		expect      string
+		hasError    bool
+	}{
+		{
+			description: "header inferred from sample",
+			sampleSize:  100,
+			expect:      "Id,Name,Year\n1,a,\n2,,2000\n",
+		},
+		{
+			description: "columns",
+			sampleSize:  1,
+			columns:     []string{"Id", "Year"},
+			expect:      "Id,Year\n1,\n2,2000\n",
+		},
+		{
+			description: "attribute outside of sample",
+			sampleSize:  1,
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+			"endpoint":   "memory://export",
+			"region":     "us-west-1",
+			"sampleSize": useCase.sampleSize,
+		})
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		manager, err := dsc.NewManagerFactory().Create(config)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		for _, SQL := range []string{"DROP TABLE IF EXISTS exported", "CREATE TABLE exported(Id INT HASH KEY)"} {
+			_, err = manager.Execute(SQL)
+			if !assert.Nil(t, err, SQL) {
+				return
+			}
+		}
+		_, err = manager.Execute("INSERT INTO exported(Id, Name) VALUES(?, ?)", 1, "a")
+		assert.Nil(t, err, useCase.description)
+		_, err = manager.Execute("INSERT INTO exported(Id, Year) VALUES(?, ?)", 2, 2000)
+		assert.Nil(t, err, useCase.description)
+		writer := new(bytes.Buffer)
+		count, err := Export(manager, "exported", writer, &ExportOptions{Format: CSVFormat, Columns: useCase.columns})
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, 2, count, useCase.description)
+		lines := strings.Split(strings.TrimSpace(writer.String()), "\n")
+		expect := strings.Split(strings.TrimSpace(useCase.expect), "\n")
+		assert.EqualValues(t, expect[0], lines[0], useCase.description)
+		assert.ElementsMatch(t, expect[1:], lines[1:], useCase.description)
+	}
+}
+
+func TestCSV_RoundTrip(t *testing.T) {
+	config, err := dsc.NewConfigWithParameters("dyndb", "", "", map[string]interface{}{
+		"endpoint": "memory://csv",
+		"region":   "us-west-1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	manager, err := dsc.NewManagerFactory().Create(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, SQL := range []string{"DROP TABLE IF EXISTS codes", "CREATE TABLE codes(Code STRING HASH KEY)"} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	var items = []map[string]*dynamodb.AttributeValue{
+		{"Code": {S: aws.String("123")}, "Value": {S: aws.String("true")}, "Count": {N: aws.String("1")}},
+		{"Code": {S: aws.String("00123")}, "Value": {S: aws.String("null")}, "Count": {N: aws.String("2")}},
+		{"Code": {S: aws.String("1.5")}, "Value": {S: aws.String("")}, "Count": {N: aws.String("3")}},
+		{"Code": {S: aws.String(`"quoted"`)}, "Value": {S: aws.String("[1,2]")}, "Count": {N: aws.String("4")}},
+		{"Code": {S: aws.String("abc")}, "Value": {BOOL: aws.Bool(false)}, "Count": {N: aws.String("5")}},
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer connection.Close()
+	db, err := asDatabase(connection)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, item := range items {
+		_, err = db.PutItem(&dynamodb.PutItemInput{TableName: aws.String("codes"), Item: item})
+		assert.Nil(t, err)
+	}
+	writer := new(bytes.Buffer)
+	count, err := Export(manager, "codes", writer, &ExportOptions{Format: CSVFormat})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, len(items), count)
+	for _, SQL := range []string{"DROP TABLE codes", "CREATE TABLE codes(Code STRING HASH KEY)"} {
+		_, err = manager.Execute(SQL)
+		if !assert.Nil(t, err, SQL) {
+			return
+		}
+	}
+	count, err = Import(manager, "codes", bytes.NewReader(writer.Bytes()), &ImportOptions{Format: CSVFormat})
+	if !assert.Nil(t, err, writer.String()) {
+		return
+	}
+	assert.EqualValues(t, len(items), count)
+	output, err := db.Scan(&dynamodb.ScanInput{TableName: aws.String("codes")})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.ElementsMatch(t, items, output.Items, writer.String())
+}
@@ -0,0 +1,49 @@
+package dyndb
+
+import (
+	"github.com/viant/dsc"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	//maxIdleConnsKey config parameter, max idle HTTP connections of the shared client
+	maxIdleConnsKey = "maxIdleConns"
+	//maxIdleConnsPerHostKey config parameter, max idle HTTP connections per host, it bounds connections reused by concurrent goroutines
+	maxIdleConnsPerHostKey = "maxIdleConnsPerHost"
+	//maxConnsPerHostKey config parameter, max HTTP connections per host, 0 means no limit
+	maxConnsPerHostKey = "maxConnsPerHost"
+	//idleConnTimeoutKey config parameter, idle HTTP connection timeout in ms
+	idleConnTimeoutKey = "idleConnTimeoutMs"
+	//connectTimeoutKey config parameter, HTTP connection dial timeout in ms
+	connectTimeoutKey = "connectTimeoutMs"
+	//keepAliveKey config parameter, TCP keep-alive period in ms
+	keepAliveKey = "keepAliveMs"
+	//requestTimeoutKey config parameter, HTTP request timeout in ms including reading response, 0 means no timeout
+	requestTimeoutKey = "requestTimeoutMs"
+
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 100
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultConnectTimeout      = 30 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+)
+
+//newHTTPClient returns HTTP client shared by manager connections with transport config parameters
+func newHTTPClient(config *dsc.Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   config.GetDuration(connectTimeoutKey, time.Millisecond, defaultConnectTimeout),
+		KeepAlive: config.GetDuration(keepAliveKey, time.Millisecond, defaultKeepAlive),
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.MaxIdleConns = config.GetInt(maxIdleConnsKey, defaultMaxIdleConns)
+	transport.MaxIdleConnsPerHost = config.GetInt(maxIdleConnsPerHostKey, defaultMaxIdleConnsPerHost)
+	transport.MaxConnsPerHost = config.GetInt(maxConnsPerHostKey, 0)
+	transport.IdleConnTimeout = config.GetDuration(idleConnTimeoutKey, time.Millisecond, defaultIdleConnTimeout)
+	return &http.Client{
+		Transport: transport,
+		Timeout:   config.GetDuration(requestTimeoutKey, time.Millisecond, 0),
+	}
+}
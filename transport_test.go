@@ -0,0 +1,47 @@
+package dyndb
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	var useCases = []struct {
+		description         string
+		parameters          map[string]interface{}
+		maxIdleConnsPerHost int
+		maxConnsPerHost     int
+		idleConnTimeout     time.Duration
+		timeout             time.Duration
+	}{
+		{
+			description:         "defaults",
+			parameters:          map[string]interface{}{},
+			maxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+			idleConnTimeout:     defaultIdleConnTimeout,
+		},
+		{
+			description:         "config parameters",
+			parameters:          map[string]interface{}{"maxIdleConnsPerHost": 8, "maxConnsPerHost": "16", "idleConnTimeoutMs": 1500, "requestTimeoutMs": 3000},
+			maxIdleConnsPerHost: 8,
+			maxConnsPerHost:     16,
+			idleConnTimeout:     1500 * time.Millisecond,
+			timeout:             3 * time.Second,
+		},
+	}
+	for _, useCase := range useCases {
+		config, err := dsc.NewConfigWithParameters("dyndb", "", "", useCase.parameters)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		client := newHTTPClient(config)
+		transport := client.Transport.(*http.Transport)
+		assert.EqualValues(t, useCase.maxIdleConnsPerHost, transport.MaxIdleConnsPerHost, useCase.description)
+		assert.EqualValues(t, useCase.maxConnsPerHost, transport.MaxConnsPerHost, useCase.description)
+		assert.EqualValues(t, useCase.idleConnTimeout, transport.IdleConnTimeout, useCase.description)
+		assert.EqualValues(t, useCase.timeout, client.Timeout, useCase.description)
+	}
+}
@@ -0,0 +1,142 @@
+package dyndb
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/viant/dsc"
+	"github.com/viant/toolbox"
+	"strings"
+)
+
+//planUpdate returns UpdateItem plan for items matching criteria, SET clause bind parameters precede criteria bind parameters
+func (m *manager) planUpdate(db dynamodbiface.DynamoDBAPI, SQL string, statement *dsc.DmlStatement, queryHints hints, parameters []interface{}) (*Plan, error) {
+	head, where := splitCriteria(SQL)
+	if where == "" {
+		return nil, fmt.Errorf("missing update criteria: %v", SQL)
+	}
+	offset := countPlaceholders(head)
+	if offset > len(parameters) {
+		offset = len(parameters)
+	}
+	return m.planKeys(db, statement.Table, where, queryHints, parameters[offset:], updateItemOperation)
+}
+
+//runUpdate updates existing items matching criteria, it returns number of updated items
+func (m *manager) runUpdate(ctx context.Context, db dynamodbiface.DynamoDBAPI, SQL string, statement *dsc.DmlStatement, sqlParameters []interface{}, queryHints hints, returned *returnedItems) (affected int, err error) {
+	record, err := statement.ColumnValueMap(toolbox.NewSliceIterator(sqlParameters))
+	if err != nil {
+		return 0, err
+	}
+	if len(record) == 0 { //nothing to change
+		return 0, nil
+	}
+	plan, err := m.planUpdate(db, SQL, statement, queryHints, sqlParameters)
+	if err != nil {
+		return 0, err
+	}
+	if err = m.checkScan(SQL, plan); err != nil {
+		return 0, err
+	}
+	expression, names, values, err := updateExpression(statement, record)
+	if err != nil {
+		return 0, err
+	}
+	names["#k"] = aws.String(plan.keyNames[0])
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(plan.Table),
+		UpdateExpression:          expression,
+		ConditionExpression:       aws.String("attribute_exists(#k)"),
+		ReturnValues:              aws.String(returned.returnValues(queryHints, dynamodb.ReturnValueAllNew)),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+	if plan.Operation == updateItemOperation {
+		return updateKeys(ctx, db, input, plan.keys, returned)
+	}
+	err = plan.readPages(ctx, db, func(items []map[string]*dynamodb.AttributeValue, _ int64) (bool, error) {
+		updated, err := updateKeys(ctx, db, input, items, returned)
+		affected += updated
+		return err == nil, err
+	})
+	return affected, err
+}
+
+//updateKeys applies update to each existing item, duplicated keys are updated once, it returns number of updated items
+func updateKeys(ctx context.Context, db dynamodbiface.DynamoDBAPI, input *dynamodb.UpdateItemInput, keys []map[string]*dynamodb.AttributeValue, returned *returnedItems) (int, error) {
+	var affected int
+	var updated = make(map[string]bool)
+	for _, key := range keys {
+		described := describeKey(key)
+		if updated[described] {
+			continue
+		}
+		updated[described] = true
+		input.Key = key
+		output, err := db.UpdateItemWithContext(ctx, input)
+		if err != nil {
+			if isAWSError(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+				continue
+			}
+			return affected, err
+		}
+		affected++
+		if err = returned.add(output.Attributes); err != nil {
+			return affected, err
+		}
+	}
+	return affected, nil
+}
+
+//existingKeys returns unique keys of existing items in supplied order, items are read with consistent BatchGetItem projecting key attributes
+func existingKeys(ctx context.Context, db dynamodbiface.DynamoDBAPI, table string, keyNames []string, keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, error) {
+	var unique = make([]map[string]*dynamodb.AttributeValue, 0, len(keys))
+	var described = make(map[string]bool)
+	for _, key := range keys {
+		if !described[describeKey(key)] {
+			described[describeKey(key)] = true
+			unique = append(unique, key)
+		}
+	}
+	var names = make(map[string]*string)
+	var projection = make([]string, 0, len(keyNames))
+	for i, name := range keyNames {
+		placeholder := fmt.Sprintf("#k%d", i)
+		names[placeholder] = aws.String(name)
+		projection = append(projection, placeholder)
+	}
+	var existing = make(map[string]bool)
+	for i := 0; i < len(unique); i += maxBatchGetItems {
+		end := i + maxBatchGetItems
+		if end > len(unique) {
+			end = len(unique)
+		}
+		requestItems := map[string]*dynamodb.KeysAndAttributes{
+			table: {
+				Keys:                     unique[i:end],
+				ProjectionExpression:     aws.String(strings.Join(projection, ", ")),
+				ExpressionAttributeNames: names,
+				ConsistentRead:           aws.Bool(true),
+			},
+		}
+		for len(requestItems) > 0 {
+			output, err := db.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range output.Responses[table] {
+				existing[describeKey(item)] = true
+			}
+			requestItems = output.UnprocessedKeys
+		}
+	}
+	var result = make([]map[string]*dynamodb.AttributeValue, 0, len(existing))
+	for _, key := range unique {
+		if existing[describeKey(key)] {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}